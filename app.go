@@ -310,6 +310,63 @@ func (a *App) ToggleMCPTool(name string) error {
 	return a.mcpManager.ToggleTool(name)
 }
 
+// CreateMCPToken issues an API token for the MCP server scoped to the given projects and tools.
+// Empty scopes grant access to all projects or tools. The secret is only returned once.
+func (a *App) CreateMCPToken(name string, projects []string, tools []string) (models.MCPAPITokenSecret, error) {
+	if a.mcpManager == nil {
+		return models.MCPAPITokenSecret{}, fmt.Errorf("mcp manager not initialized")
+	}
+	return a.mcpManager.CreateToken(name, projects, tools)
+}
+
+// ListMCPTokens lists issued MCP API tokens without their secrets.
+func (a *App) ListMCPTokens() ([]models.MCPAPIToken, error) {
+	if a.mcpManager == nil {
+		return nil, fmt.Errorf("mcp manager not initialized")
+	}
+	return a.mcpManager.ListTokens()
+}
+
+// QueryMCPAuditLog returns MCP audit entries matching the query, newest first.
+func (a *App) QueryMCPAuditLog(query models.MCPAuditQuery) ([]models.MCPAuditEntry, error) {
+	if a.mcpManager == nil {
+		return nil, fmt.Errorf("mcp manager not initialized")
+	}
+	return a.mcpManager.QueryAuditLog(query)
+}
+
+// GetMCPClientStats returns per-client call counts, error counts and latency.
+func (a *App) GetMCPClientStats() ([]models.MCPClientStats, error) {
+	if a.mcpManager == nil {
+		return nil, fmt.Errorf("mcp manager not initialized")
+	}
+	return a.mcpManager.GetClientStats()
+}
+
+// ExportMCPAuditLog writes matching audit entries to path as JSON Lines and returns the count.
+func (a *App) ExportMCPAuditLog(path string, query models.MCPAuditQuery) (int, error) {
+	if a.mcpManager == nil {
+		return 0, fmt.Errorf("mcp manager not initialized")
+	}
+	return a.mcpManager.ExportAuditLog(path, query)
+}
+
+// ClearMCPAuditLog deletes the MCP audit history.
+func (a *App) ClearMCPAuditLog() error {
+	if a.mcpManager == nil {
+		return fmt.Errorf("mcp manager not initialized")
+	}
+	return a.mcpManager.ClearAuditLog()
+}
+
+// RevokeMCPToken deletes an MCP API token.
+func (a *App) RevokeMCPToken(id string) error {
+	if a.mcpManager == nil {
+		return fmt.Errorf("mcp manager not initialized")
+	}
+	return a.mcpManager.RevokeToken(id)
+}
+
 // GetAllProjectsStats returns cumulative statistics across all projects.
 // Exposed to frontend as: window.go.main.App.GetAllProjectsStats
 func (a *App) GetAllProjectsStats() (*models.ProjectStats, error) {
@@ -351,7 +408,62 @@ func (a *App) DownloadEmbeddingModel(modelID string) (*models.EmbeddingModelInfo
 	return a.projectService.DownloadEmbeddingModel(modelID)
 }
 
+// ImportEmbeddingModel registers an ONNX model from a local directory or .tar.gz archive.
+func (a *App) ImportEmbeddingModel(path string) (*models.EmbeddingModelInfo, error) {
+	return a.projectService.ImportEmbeddingModel(path)
+}
+
+// MigrateEmbeddingModel re-embeds a project with another model in the background and switches
+// to it once every chunk is covered. Progress is reported by GetIndexingProgress.
+func (a *App) MigrateEmbeddingModel(projectID, modelID string) error {
+	return a.projectService.MigrateEmbeddingModel(projectID, modelID)
+}
+
+// CancelEmbeddingMigration stops a running model migration and keeps the current model.
+func (a *App) CancelEmbeddingMigration(projectID string) error {
+	return a.projectService.CancelEmbeddingMigration(projectID)
+}
+
+// ListRerankerModels returns the cross-encoder catalog used by the search rerank stage.
+func (a *App) ListRerankerModels() ([]*models.EmbeddingModelInfo, error) {
+	return a.projectService.ListRerankerModels()
+}
+
+// DownloadRerankerModel downloads a reranker model ahead of its first search.
+func (a *App) DownloadRerankerModel(modelID string) (*models.EmbeddingModelInfo, error) {
+	return a.projectService.DownloadRerankerModel(modelID)
+}
+
 // Search executes semantic search for a project.
-func (a *App) Search(projectID, query string, k int) (*models.SearchResponse, error) {
-	return a.projectService.Search(projectID, query, k)
+// Pass the previous response's NextCursor in req.Cursor to fetch the following page.
+func (a *App) Search(req models.SearchRequest) (*models.SearchResponse, error) {
+	return a.projectService.SearchWithOptions(req)
+}
+
+// SimilarTo returns chunks similar to an indexed chunk using its stored embedding.
+func (a *App) SimilarTo(projectID, chunkID string, k int, scope string) (*models.SimilarResponse, error) {
+	return a.projectService.SimilarTo(projectID, chunkID, k, models.SimilarScope(scope))
+}
+
+// ==================== Retrieval Evaluation API ====================
+
+// GetEvaluationCases returns the retrieval test cases stored for a project.
+func (a *App) GetEvaluationCases(projectID string) ([]models.EvalCase, error) {
+	return a.projectService.GetEvaluationCases(projectID)
+}
+
+// SaveEvaluationCases replaces the retrieval test cases stored for a project.
+func (a *App) SaveEvaluationCases(projectID string, cases []models.EvalCase) ([]models.EvalCase, error) {
+	return a.projectService.SaveEvaluationCases(projectID, cases)
+}
+
+// GenerateEvaluationCases derives up to limit test cases from indexed docstrings without storing them.
+func (a *App) GenerateEvaluationCases(projectID string, limit int) ([]models.EvalCase, error) {
+	return a.projectService.GenerateEvaluationCases(projectID, limit)
+}
+
+// RunEvaluation compares embedding models and search modes on a project's test cases and
+// reports recall@k, MRR, latency and index size. StopIndexing cancels it.
+func (a *App) RunEvaluation(req models.EvalRequest) (*models.EvalReport, error) {
+	return a.projectService.RunEvaluation(a.ctx, req)
 }
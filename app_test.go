@@ -29,10 +29,13 @@ type MockProjectServiceAPI struct {
 	GetChunkByIDFunc             func(projectID, chunkID string) (*models.Chunk, error)
 	GetFileOutlineFunc           func(projectID, path string) ([]*models.OutlineNode, error)
 	GetOutlineTimestampsFunc     func(projectID string) (map[string]int64, error)
+	ListIndexedFilesFunc         func(projectID string) ([]string, error)
 	ReadFileContentFunc          func(projectID, relativePath string) (string, error)
 	StartIndexingFunc            func(projectID string) error
 	ResetProjectIndexFunc        func(projectID string) error
 	ReindexProjectFunc           func(projectID string) error
+	ReindexFilesFunc             func(projectID string, paths []string) ([]models.FileReindexResult, error)
+	FindSymbolChunksFunc         func(projectID string, names, kinds []string) ([]*models.Chunk, error)
 	StopIndexingFunc             func(projectID string) error
 	GetIndexingProgressFunc      func(projectID string) (models.IndexingProgress, error)
 	GetGitIgnorePatternsFunc     func(projectID string) ([]string, error)
@@ -144,6 +147,12 @@ func (m *MockProjectServiceAPI) GetOutlineTimestamps(projectID string) (map[stri
 	}
 	return map[string]int64{}, nil
 }
+func (m *MockProjectServiceAPI) ListIndexedFiles(projectID string) ([]string, error) {
+	if m.ListIndexedFilesFunc != nil {
+		return m.ListIndexedFilesFunc(projectID)
+	}
+	return []string{}, nil
+}
 func (m *MockProjectServiceAPI) ReadFileContent(projectID, relativePath string) (string, error) {
 	if m.ReadFileContentFunc != nil {
 		return m.ReadFileContentFunc(projectID, relativePath)
@@ -168,6 +177,20 @@ func (m *MockProjectServiceAPI) ReindexProject(projectID string) error {
 	}
 	return nil
 }
+
+func (m *MockProjectServiceAPI) ReindexFiles(projectID string, paths []string) ([]models.FileReindexResult, error) {
+	if m.ReindexFilesFunc != nil {
+		return m.ReindexFilesFunc(projectID, paths)
+	}
+	return nil, nil
+}
+
+func (m *MockProjectServiceAPI) FindSymbolChunks(projectID string, names, kinds []string) ([]*models.Chunk, error) {
+	if m.FindSymbolChunksFunc != nil {
+		return m.FindSymbolChunksFunc(projectID, names, kinds)
+	}
+	return nil, nil
+}
 func (m *MockProjectServiceAPI) StopIndexing(projectID string) error {
 	if m.StopIndexingFunc != nil {
 		return m.StopIndexingFunc(projectID)
@@ -216,6 +239,12 @@ func (m *MockProjectServiceAPI) DownloadEmbeddingModel(modelID string) (*models.
 	}
 	return &models.EmbeddingModelInfo{}, nil
 }
+func (m *MockProjectServiceAPI) ListRerankerModels() ([]*models.EmbeddingModelInfo, error) {
+	return []*models.EmbeddingModelInfo{}, nil
+}
+func (m *MockProjectServiceAPI) DownloadRerankerModel(modelID string) (*models.EmbeddingModelInfo, error) {
+	return &models.EmbeddingModelInfo{}, nil
+}
 func (m *MockProjectServiceAPI) GetEmbeddingCapabilities() (*models.EmbeddingCapabilities, error) {
 	if m.GetEmbeddingCapabilitiesFunc != nil {
 		return m.GetEmbeddingCapabilitiesFunc()
@@ -237,6 +266,34 @@ func (m *MockProjectServiceAPI) Search(projectID, query string, k int) (*models.
 	}
 	return &models.SearchResponse{}, nil
 }
+func (m *MockProjectServiceAPI) ImportEmbeddingModel(path string) (*models.EmbeddingModelInfo, error) {
+	return nil, nil
+}
+func (m *MockProjectServiceAPI) MigrateEmbeddingModel(projectID, modelID string) error {
+	return nil
+}
+func (m *MockProjectServiceAPI) CancelEmbeddingMigration(projectID string) error {
+	return nil
+}
+func (m *MockProjectServiceAPI) SimilarTo(projectID, chunkID string, k int, scope models.SimilarScope) (*models.SimilarResponse, error) {
+	return &models.SimilarResponse{}, nil
+}
+func (m *MockProjectServiceAPI) SearchWithOptions(req models.SearchRequest) (*models.SearchResponse, error) {
+	return m.Search(req.ProjectID, req.Query, req.K)
+}
+func (m *MockProjectServiceAPI) GetEvaluationCases(projectID string) ([]models.EvalCase, error) {
+	return nil, nil
+}
+func (m *MockProjectServiceAPI) SaveEvaluationCases(projectID string, cases []models.EvalCase) ([]models.EvalCase, error) {
+	return cases, nil
+}
+func (m *MockProjectServiceAPI) GenerateEvaluationCases(projectID string, limit int) ([]models.EvalCase, error) {
+	return nil, nil
+}
+func (m *MockProjectServiceAPI) RunEvaluation(ctx context.Context, req models.EvalRequest) (*models.EvalReport, error) {
+	return &models.EvalReport{ProjectID: req.ProjectID}, nil
+}
+func (m *MockProjectServiceAPI) AddEventListener(listener func(string, interface{})) {}
 func (m *MockProjectServiceAPI) Close() error {
 	if m.CloseFunc != nil {
 		return m.CloseFunc()
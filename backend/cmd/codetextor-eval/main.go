@@ -0,0 +1,226 @@
+/*
+  File: main.go
+  Purpose: Command-line entry point for the retrieval-quality evaluation harness.
+  Author: CodeTextor project
+  Notes: Uses the same configuration and project databases as the desktop app.
+*/
+
+package main
+
+import (
+	"CodeTextor/backend/pkg/models"
+	"CodeTextor/backend/pkg/services"
+	"CodeTextor/backend/pkg/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+)
+
+// options holds the parsed command-line flags.
+type options struct {
+	project   string
+	models    []string
+	modes     []models.EvalSearchMode
+	k         int
+	casesPath string
+	generate  bool
+	maxCases  int
+	save      bool
+	jsonOut   bool
+	verbose   bool
+}
+
+// main runs the evaluation and exits non-zero on failure.
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "codetextor-eval:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses args, evaluates the selected project and writes the report to stdout.
+func run(args []string, stdout, stderr io.Writer) error {
+	opts, err := parseFlags(args, stderr)
+	if err != nil {
+		return err
+	}
+	if !opts.verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	service, err := services.NewProjectService(context.Background())
+	if err != nil {
+		return err
+	}
+	defer service.Close()
+
+	project, err := findProject(service, opts.project)
+	if err != nil {
+		return err
+	}
+	req := models.EvalRequest{
+		ProjectID:     project.ID,
+		Models:        opts.models,
+		Modes:         opts.modes,
+		K:             opts.k,
+		GenerateCases: opts.generate,
+		MaxCases:      opts.maxCases,
+	}
+	if opts.casesPath != "" {
+		if req.Cases, err = loadCases(opts.casesPath); err != nil {
+			return err
+		}
+	} else if opts.generate && opts.save {
+		if req.Cases, err = service.GenerateEvaluationCases(project.ID, opts.maxCases); err != nil {
+			return err
+		}
+	}
+	if opts.save {
+		if len(req.Cases) == 0 {
+			return errors.New("-save needs -cases or -generate")
+		}
+		if req.Cases, err = service.SaveEvaluationCases(project.ID, req.Cases); err != nil {
+			return err
+		}
+	}
+
+	// Interrupting the command still lets the evaluation remove its temporary vectors.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	report, err := service.RunEvaluation(ctx, req)
+	if err != nil {
+		return err
+	}
+	if opts.jsonOut {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	printReport(stdout, project.Name, report, opts.verbose)
+	return nil
+}
+
+// parseFlags reads the command-line flags; usage errors are written to stderr.
+func parseFlags(args []string, stderr io.Writer) (options, error) {
+	var opts options
+	var modelList, modeList string
+	fs := flag.NewFlagSet("codetextor-eval", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&opts.project, "project", "", "project ID or name (required)")
+	fs.StringVar(&modelList, "models", "", "comma-separated embedding models to compare (default: the project's model)")
+	fs.StringVar(&modeList, "modes", "", "comma-separated search modes: semantic, mmr, rerank, fused (default: semantic)")
+	fs.IntVar(&opts.k, "k", models.DefaultEvalK, "ranking depth for recall@k and MRR (max 50)")
+	fs.StringVar(&opts.casesPath, "cases", "", "JSON file with an array of {query, expectedFile, expectedSymbol} cases")
+	fs.BoolVar(&opts.generate, "generate", false, "generate cases from docstrings instead of using the stored ones")
+	fs.IntVar(&opts.maxCases, "max-cases", models.DefaultEvalGeneratedCases, "maximum number of generated cases")
+	fs.BoolVar(&opts.save, "save", false, "store the cases from -cases or -generate as the project's evaluation set")
+	fs.BoolVar(&opts.jsonOut, "json", false, "print the full report as JSON")
+	fs.BoolVar(&opts.verbose, "v", false, "list missed cases and show service logs")
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if strings.TrimSpace(opts.project) == "" {
+		fs.Usage()
+		return opts, errors.New("-project is required")
+	}
+	opts.models = splitList(modelList)
+	for _, mode := range splitList(modeList) {
+		opts.modes = append(opts.modes, models.EvalSearchMode(mode))
+	}
+	return opts, nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// loadCases reads evaluation cases from a JSON array file.
+func loadCases(path string) ([]models.EvalCase, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cases []models.EvalCase
+	if err := json.Unmarshal(data, &cases); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("%s contains no cases", path)
+	}
+	return cases, nil
+}
+
+// findProject resolves a project by ID or, case-insensitively, by name.
+func findProject(service *services.ProjectService, ref string) (*models.Project, error) {
+	projects, err := service.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+	ref = strings.TrimSpace(ref)
+	for _, project := range projects {
+		if project.ID == ref {
+			return project, nil
+		}
+	}
+	for _, project := range projects {
+		if strings.EqualFold(project.Name, ref) {
+			return project, nil
+		}
+	}
+	return nil, fmt.Errorf("no project with ID or name %q", ref)
+}
+
+// printReport writes the report as a table, followed by the missed cases when verbose.
+func printReport(w io.Writer, projectName string, report *models.EvalReport, verbose bool) {
+	fmt.Fprintf(w, "%s: %d %s cases, k=%d, %.1fs\n\n", projectName, report.CaseCount, report.CaseSource, report.K, float64(report.DurationMs)/1000)
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(table, "MODEL\tMODE\tR@1\tR@5\tR@%d\tMRR\tMEAN MS\tP95 MS\tINDEX\tDIM\tEMBED\n", report.K)
+	for _, run := range report.Runs {
+		if run.Error != "" && len(run.Cases) == 0 {
+			fmt.Fprintf(table, "%s\t%s\terror: %s\n", run.Model, run.Mode, run.Error)
+			continue
+		}
+		embed := "-"
+		if run.EmbedTimeMs > 0 {
+			embed = fmt.Sprintf("%.1fs", float64(run.EmbedTimeMs)/1000)
+		}
+		fmt.Fprintf(table, "%s\t%s\t%.3f\t%.3f\t%.3f\t%.3f\t%.1f\t%.1f\t%s\t%d\t%s\n",
+			run.Model, run.Mode, run.RecallAt1, run.RecallAt5, run.RecallAtK, run.MRR,
+			run.MeanLatencyMs, run.P95LatencyMs, utils.FormatBytes(run.IndexBytes), run.EmbeddingDim, embed)
+	}
+	table.Flush()
+
+	for _, run := range report.Runs {
+		if run.Error != "" && len(run.Cases) > 0 {
+			fmt.Fprintf(w, "\n%s/%s: %s\n", run.Model, run.Mode, run.Error)
+		}
+		if !verbose {
+			continue
+		}
+		for _, result := range run.Cases {
+			if result.Rank > 0 {
+				continue
+			}
+			expected := result.ExpectedFile
+			if result.ExpectedSymbol != "" {
+				expected += "#" + result.ExpectedSymbol
+			}
+			fmt.Fprintf(w, "  miss %s/%s: %q expected %s, top %s#%s\n", run.Model, run.Mode, result.Query, expected, result.TopFile, result.TopSymbol)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"CodeTextor/backend/pkg/models"
+)
+
+func TestParseFlags(t *testing.T) {
+	opts, err := parseFlags([]string{"-project", "demo", "-models", "a, b,", "-modes", "semantic,fused", "-k", "5"}, io.Discard)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if opts.project != "demo" || opts.k != 5 || len(opts.models) != 2 || opts.models[1] != "b" {
+		t.Fatalf("unexpected options %+v", opts)
+	}
+	if len(opts.modes) != 2 || opts.modes[1] != models.EvalModeFused {
+		t.Fatalf("unexpected modes %+v", opts.modes)
+	}
+	if opts.maxCases != models.DefaultEvalGeneratedCases {
+		t.Fatalf("expected the default case limit, got %d", opts.maxCases)
+	}
+
+	if _, err := parseFlags([]string{"-k", "5"}, io.Discard); err == nil {
+		t.Fatalf("expected a missing -project to be rejected")
+	}
+}
+
+func TestLoadCases(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cases.json")
+	if err := os.WriteFile(path, []byte(`[{"query":"parse tokens","expectedFile":"pkg/a.go","expectedSymbol":"Alpha"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cases, err := loadCases(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(cases) != 1 || cases[0].ExpectedSymbol != "Alpha" {
+		t.Fatalf("unexpected cases %+v", cases)
+	}
+
+	if err := os.WriteFile(path, []byte(`[]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadCases(path); err == nil {
+		t.Fatalf("expected an empty case file to be rejected")
+	}
+}
+
+func TestPrintReport(t *testing.T) {
+	report := &models.EvalReport{
+		K:          10,
+		CaseCount:  2,
+		CaseSource: models.EvalCaseSourceDocstring,
+		Runs: []models.EvalRun{
+			{
+				Model: "model-a", Mode: models.EvalModeSemantic, RecallAt1: 0.5, RecallAtK: 1, MRR: 0.75,
+				IndexBytes: 2048, EmbeddingDim: 384,
+				Cases: []models.EvalCaseResult{
+					{Query: "parse tokens", ExpectedFile: "pkg/a.go", Rank: 1},
+					{Query: "store values", ExpectedFile: "pkg/b.go", ExpectedSymbol: "Beta", TopFile: "pkg/c.go", TopSymbol: "Gamma"},
+				},
+			},
+			{Model: "model-b", Mode: models.EvalModeSemantic, Error: "model not downloaded"},
+		},
+	}
+	var out bytes.Buffer
+	printReport(&out, "demo", report, true)
+	text := out.String()
+	for _, want := range []string{"demo: 2 docstring cases, k=10", "R@10", "0.750", "2.0 KB", "error: model not downloaded", `"store values" expected pkg/b.go#Beta, top pkg/c.go#Gamma`} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in output:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, `miss model-a/semantic: "parse tokens"`) {
+		t.Fatalf("hits should not be listed as misses:\n%s", text)
+	}
+}
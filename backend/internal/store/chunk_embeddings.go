@@ -0,0 +1,204 @@
+package store
+
+import (
+	"CodeTextor/backend/pkg/models"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrModelEmbeddingsIncomplete is returned by PromoteModelEmbeddings while some chunks still
+// lack an embedding from the requested model, e.g. because files were re-indexed meanwhile.
+var ErrModelEmbeddingsIncomplete = errors.New("embeddings for the model are incomplete")
+
+// TemporaryModelPrefix namespaces the model IDs of vectors stored only for the duration of a
+// retrieval evaluation. They are left out of the stats and of IndexVersion.
+const TemporaryModelPrefix = "eval:"
+
+// ChunksMissingModelEmbedding returns up to limit chunks (ID and content only) that have no
+// embedding from modelID in chunk_embeddings.
+func (s *VectorStore) ChunksMissingModelEmbedding(modelID string, limit int) ([]*models.Chunk, error) {
+	rows, err := s.db.Query(`
+		SELECT c.id, c.content
+		FROM chunks c
+		LEFT JOIN chunk_embeddings ce ON ce.chunk_id = c.id AND ce.model_id = ?
+		WHERE ce.chunk_id IS NULL
+		ORDER BY c.rowid
+		LIMIT ?
+	`, modelID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chunks missing %s embeddings: %w", modelID, err)
+	}
+	defer rows.Close()
+
+	var chunks []*models.Chunk
+	for rows.Next() {
+		chunk := &models.Chunk{ProjectID: s.projectID}
+		if err := rows.Scan(&chunk.ID, &chunk.Content); err != nil {
+			return nil, fmt.Errorf("failed to scan chunk: %w", err)
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks, rows.Err()
+}
+
+// SaveModelEmbeddings stores embeddings from modelID keyed by chunk ID, encoded with the
+// project's current storage format. Chunks deleted in the meantime are skipped.
+func (s *VectorStore) SaveModelEmbeddings(modelID string, embeddings map[string][]float32) error {
+	if len(embeddings) == 0 {
+		return nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin embedding transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT OR REPLACE INTO chunk_embeddings (chunk_id, model_id, embedding, embedding_format, embedding_dim, created_at)
+		SELECT id, ?, ?, ?, ?, ? FROM chunks WHERE id = ?
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare embedding statement: %w", err)
+	}
+	defer stmt.Close()
+
+	format := s.EmbeddingStorage()
+	now := time.Now().Unix()
+	for chunkID, vec := range embeddings {
+		encoded, err := encodeEmbedding(vec, format)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.Exec(modelID, encoded, format, len(vec), now, chunkID); err != nil {
+			return fmt.Errorf("failed to store %s embedding for chunk %s: %w", modelID, chunkID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit embeddings: %w", err)
+	}
+	return nil
+}
+
+// ModelEmbeddingCoverage returns how many chunks have an embedding from modelID in
+// chunk_embeddings and the total number of chunks.
+func (s *VectorStore) ModelEmbeddingCoverage(modelID string) (int, int, error) {
+	var covered, total int
+	err := s.db.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM chunk_embeddings ce JOIN chunks c ON c.id = ce.chunk_id WHERE ce.model_id = ?),
+			(SELECT COUNT(*) FROM chunks)
+	`, modelID).Scan(&covered, &total)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to measure %s embedding coverage: %w", modelID, err)
+	}
+	return covered, total, nil
+}
+
+// modelEmbeddingUsage counts the chunks covered by each model in chunk_embeddings.
+func (s *VectorStore) modelEmbeddingUsage() ([]models.ProjectEmbeddingModelUsage, error) {
+	rows, err := s.db.Query(`
+		SELECT ce.model_id, COUNT(*) as cnt
+		FROM chunk_embeddings ce
+		JOIN chunks c ON c.id = ce.chunk_id
+		WHERE ce.model_id NOT LIKE ?
+		GROUP BY ce.model_id
+		ORDER BY cnt DESC, ce.model_id
+	`, TemporaryModelPrefix+"%")
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate additional embedding models: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []models.ProjectEmbeddingModelUsage
+	for rows.Next() {
+		var entry models.ProjectEmbeddingModelUsage
+		if err := rows.Scan(&entry.ModelID, &entry.ChunkCount); err != nil {
+			return nil, fmt.Errorf("failed to scan additional embedding model usage: %w", err)
+		}
+		usage = append(usage, entry)
+	}
+	return usage, rows.Err()
+}
+
+// DeleteModelEmbeddings drops every chunk_embeddings row produced by modelID, together with
+// rows whose chunk no longer exists.
+func (s *VectorStore) DeleteModelEmbeddings(modelID string) error {
+	if _, err := s.db.Exec(`DELETE FROM chunk_embeddings WHERE model_id = ? OR chunk_id NOT IN (SELECT id FROM chunks)`, modelID); err != nil {
+		return fmt.Errorf("failed to delete %s embeddings: %w", modelID, err)
+	}
+	return nil
+}
+
+// PromoteModelEmbeddings makes modelID the project's primary model in one transaction: the
+// chunks' embeddings are replaced by the ones in chunk_embeddings, those rows are removed and
+// the project metadata (which should already name modelID) is saved. Queries running before
+// the commit keep using the previous embeddings. It returns the number of chunks updated, or
+// ErrModelEmbeddingsIncomplete if any chunk has no embedding from modelID yet.
+func (s *VectorStore) PromoteModelEmbeddings(modelID string, project *models.Project) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin promotion transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var missing int
+	err = tx.QueryRow(`
+		SELECT COUNT(*)
+		FROM chunks c
+		LEFT JOIN chunk_embeddings ce ON ce.chunk_id = c.id AND ce.model_id = ?
+		WHERE ce.chunk_id IS NULL
+	`, modelID).Scan(&missing)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check %s embeddings: %w", modelID, err)
+	}
+	if missing > 0 {
+		return 0, fmt.Errorf("%w: %d chunks pending", ErrModelEmbeddingsIncomplete, missing)
+	}
+
+	result, err := tx.Exec(`
+		UPDATE chunks SET
+			embedding = ce.embedding,
+			embedding_format = ce.embedding_format,
+			embedding_dim = ce.embedding_dim,
+			embedding_model_id = ce.model_id,
+			updated_at = ?
+		FROM chunk_embeddings ce
+		WHERE ce.chunk_id = chunks.id AND ce.model_id = ?
+	`, time.Now().Unix(), modelID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to promote %s embeddings: %w", modelID, err)
+	}
+	updated, _ := result.RowsAffected()
+
+	if _, err := tx.Exec(`DELETE FROM chunk_embeddings WHERE model_id = ?`, modelID); err != nil {
+		return 0, fmt.Errorf("failed to clear promoted embeddings: %w", err)
+	}
+	if project != nil {
+		if err := saveProjectMetadataWithDB(tx, project); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit promotion: %w", err)
+	}
+	return int(updated), nil
+}
+
+// ModelEmbeddingSize returns the bytes taken by modelID's vectors in chunk_embeddings and their
+// dimension (0 when the model has none).
+func (s *VectorStore) ModelEmbeddingSize(modelID string) (int64, int, error) {
+	var size int64
+	var dim int
+	err := s.db.QueryRow(`
+		SELECT COALESCE(SUM(LENGTH(embedding)), 0), COALESCE(MAX(embedding_dim), 0)
+		FROM chunk_embeddings
+		WHERE model_id = ?
+	`, modelID).Scan(&size, &dim)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to measure %s embeddings: %w", modelID, err)
+	}
+	return size, dim, nil
+}
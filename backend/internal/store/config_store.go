@@ -4,6 +4,7 @@ import (
 	"CodeTextor/backend/pkg/models"
 	"CodeTextor/backend/pkg/utils"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -74,6 +75,45 @@ func NewConfigStore() (*ConfigStore, error) {
 		return nil, fmt.Errorf("failed to init embedding model schema: %w", err)
 	}
 
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS mcp_tokens (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			prefix TEXT NOT NULL,
+			token_hash TEXT NOT NULL UNIQUE,
+			projects TEXT NOT NULL DEFAULT '[]',
+			tools TEXT NOT NULL DEFAULT '[]',
+			created_at INTEGER NOT NULL,
+			last_used_at INTEGER NOT NULL DEFAULT 0
+		);
+	`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to init mcp token schema: %w", err)
+	}
+
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS mcp_audit_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			timestamp INTEGER NOT NULL,
+			session_id TEXT NOT NULL DEFAULT '',
+			client_name TEXT NOT NULL DEFAULT '',
+			client_version TEXT NOT NULL DEFAULT '',
+			token_name TEXT NOT NULL DEFAULT '',
+			project_id TEXT NOT NULL DEFAULT '',
+			method TEXT NOT NULL,
+			name TEXT NOT NULL,
+			arguments TEXT NOT NULL DEFAULT '',
+			result_bytes INTEGER NOT NULL DEFAULT 0,
+			duration_ms REAL NOT NULL DEFAULT 0,
+			error TEXT NOT NULL DEFAULT ''
+		);
+		CREATE INDEX IF NOT EXISTS idx_mcp_audit_timestamp ON mcp_audit_log(timestamp);
+		CREATE INDEX IF NOT EXISTS idx_mcp_audit_client ON mcp_audit_log(client_name);
+	`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to init mcp audit schema: %w", err)
+	}
+
 	if err := ensureEmbeddingModelColumns(db); err != nil {
 		db.Close()
 		return nil, fmt.Errorf("failed to migrate embedding model schema: %w", err)
@@ -128,8 +168,10 @@ func (s *ConfigStore) ListEmbeddingModels() ([]*models.EmbeddingModelInfo, error
 			ram_requirement_bytes, cpu_latency_ms, multilingual, code_quality,
 			notes, source_type, source_uri, local_path, license, download_status,
 			requires_conversion, preferred_filename, code_focus,
-			estimated_tokens_per_second, supports_quantization,
+			estimated_tokens_per_second, supports_quantization, supports_matryoshka,
 			tokenizer_uri, tokenizer_local_path, max_sequence_length,
+			base_url, remote_model, api_key, batch_size, max_retries, timeout_seconds,
+			query_prefix, document_prefix, sha256, tokenizer_sha256,
 			created_at, updated_at
 		FROM embedding_models
 		ORDER BY display_name COLLATE NOCASE
@@ -141,7 +183,7 @@ func (s *ConfigStore) ListEmbeddingModels() ([]*models.EmbeddingModelInfo, error
 
 	var modelsList []*models.EmbeddingModelInfo
 	for rows.Next() {
-		var multilingualInt, requiresConvInt, supportsQuantInt int
+		var multilingualInt, requiresConvInt, supportsQuantInt, supportsMatryoshkaInt int
 		meta := &models.EmbeddingModelInfo{}
 		if err := rows.Scan(
 			&meta.ID,
@@ -165,9 +207,20 @@ func (s *ConfigStore) ListEmbeddingModels() ([]*models.EmbeddingModelInfo, error
 			&meta.CodeFocus,
 			&meta.EstimatedTokensPerS,
 			&supportsQuantInt,
+			&supportsMatryoshkaInt,
 			&meta.TokenizerURI,
 			&meta.TokenizerLocalPath,
 			&meta.MaxSequenceLength,
+			&meta.BaseURL,
+			&meta.RemoteModel,
+			&meta.APIKey,
+			&meta.BatchSize,
+			&meta.MaxRetries,
+			&meta.TimeoutSeconds,
+			&meta.QueryPrefix,
+			&meta.DocumentPrefix,
+			&meta.SHA256,
+			&meta.TokenizerSHA256,
 			&meta.CreatedAt,
 			&meta.UpdatedAt,
 		); err != nil {
@@ -176,6 +229,7 @@ func (s *ConfigStore) ListEmbeddingModels() ([]*models.EmbeddingModelInfo, error
 		meta.IsMultilingual = multilingualInt == 1
 		meta.RequiresConversion = requiresConvInt == 1
 		meta.SupportsQuantization = supportsQuantInt == 1
+		meta.SupportsMatryoshka = supportsMatryoshkaInt == 1
 		modelsList = append(modelsList, meta)
 	}
 
@@ -193,13 +247,15 @@ func (s *ConfigStore) GetEmbeddingModel(id string) (*models.EmbeddingModelInfo,
 			ram_requirement_bytes, cpu_latency_ms, multilingual, code_quality,
 			notes, source_type, source_uri, local_path, license, download_status,
 			requires_conversion, preferred_filename, code_focus,
-			estimated_tokens_per_second, supports_quantization,
+			estimated_tokens_per_second, supports_quantization, supports_matryoshka,
 			tokenizer_uri, tokenizer_local_path, max_sequence_length,
+			base_url, remote_model, api_key, batch_size, max_retries, timeout_seconds,
+			query_prefix, document_prefix, sha256, tokenizer_sha256,
 			created_at, updated_at
 		FROM embedding_models WHERE id = ?
 	`, id)
 
-	var multilingualInt, requiresConvInt, supportsQuantInt int
+	var multilingualInt, requiresConvInt, supportsQuantInt, supportsMatryoshkaInt int
 	meta := &models.EmbeddingModelInfo{}
 	if err := row.Scan(
 		&meta.ID,
@@ -223,9 +279,20 @@ func (s *ConfigStore) GetEmbeddingModel(id string) (*models.EmbeddingModelInfo,
 		&meta.CodeFocus,
 		&meta.EstimatedTokensPerS,
 		&supportsQuantInt,
+		&supportsMatryoshkaInt,
 		&meta.TokenizerURI,
 		&meta.TokenizerLocalPath,
 		&meta.MaxSequenceLength,
+		&meta.BaseURL,
+		&meta.RemoteModel,
+		&meta.APIKey,
+		&meta.BatchSize,
+		&meta.MaxRetries,
+		&meta.TimeoutSeconds,
+		&meta.QueryPrefix,
+		&meta.DocumentPrefix,
+		&meta.SHA256,
+		&meta.TokenizerSHA256,
 		&meta.CreatedAt,
 		&meta.UpdatedAt,
 	); err != nil {
@@ -237,6 +304,7 @@ func (s *ConfigStore) GetEmbeddingModel(id string) (*models.EmbeddingModelInfo,
 	meta.IsMultilingual = multilingualInt == 1
 	meta.RequiresConversion = requiresConvInt == 1
 	meta.SupportsQuantization = supportsQuantInt == 1
+	meta.SupportsMatryoshka = supportsMatryoshkaInt == 1
 	return meta, nil
 }
 
@@ -266,11 +334,13 @@ func (s *ConfigStore) UpsertEmbeddingModel(meta *models.EmbeddingModelInfo) erro
 			ram_requirement_bytes, cpu_latency_ms, multilingual, code_quality,
 			notes, source_type, source_uri, local_path, license, download_status,
 			requires_conversion, preferred_filename, code_focus,
-			estimated_tokens_per_second, supports_quantization,
+			estimated_tokens_per_second, supports_quantization, supports_matryoshka,
 			tokenizer_uri, tokenizer_local_path, max_sequence_length,
+			base_url, remote_model, api_key, batch_size, max_retries, timeout_seconds,
+			query_prefix, document_prefix, sha256, tokenizer_sha256,
 			created_at, updated_at
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			display_name = excluded.display_name,
 			backend = excluded.backend,
@@ -292,9 +362,20 @@ func (s *ConfigStore) UpsertEmbeddingModel(meta *models.EmbeddingModelInfo) erro
 			code_focus = excluded.code_focus,
 			estimated_tokens_per_second = excluded.estimated_tokens_per_second,
 			supports_quantization = excluded.supports_quantization,
+			supports_matryoshka = excluded.supports_matryoshka,
 			tokenizer_uri = excluded.tokenizer_uri,
 			tokenizer_local_path = excluded.tokenizer_local_path,
 			max_sequence_length = excluded.max_sequence_length,
+			base_url = excluded.base_url,
+			remote_model = excluded.remote_model,
+			api_key = excluded.api_key,
+			batch_size = excluded.batch_size,
+			max_retries = excluded.max_retries,
+			timeout_seconds = excluded.timeout_seconds,
+			query_prefix = excluded.query_prefix,
+			document_prefix = excluded.document_prefix,
+			sha256 = excluded.sha256,
+			tokenizer_sha256 = excluded.tokenizer_sha256,
 			updated_at = excluded.updated_at
 	`, meta.ID,
 		meta.DisplayName,
@@ -317,9 +398,20 @@ func (s *ConfigStore) UpsertEmbeddingModel(meta *models.EmbeddingModelInfo) erro
 		meta.CodeFocus,
 		meta.EstimatedTokensPerS,
 		boolToInt(meta.SupportsQuantization),
+		boolToInt(meta.SupportsMatryoshka),
 		meta.TokenizerURI,
 		meta.TokenizerLocalPath,
 		meta.MaxSequenceLength,
+		meta.BaseURL,
+		meta.RemoteModel,
+		meta.APIKey,
+		meta.BatchSize,
+		meta.MaxRetries,
+		meta.TimeoutSeconds,
+		meta.QueryPrefix,
+		meta.DocumentPrefix,
+		meta.SHA256,
+		meta.TokenizerSHA256,
 		meta.CreatedAt,
 		meta.UpdatedAt,
 	)
@@ -329,6 +421,117 @@ func (s *ConfigStore) UpsertEmbeddingModel(meta *models.EmbeddingModelInfo) erro
 	return nil
 }
 
+// CreateMCPToken stores a new MCP API token together with the hash of its secret.
+func (s *ConfigStore) CreateMCPToken(token *models.MCPAPIToken, tokenHash string) error {
+	if token == nil || token.ID == "" {
+		return fmt.Errorf("mcp token id cannot be empty")
+	}
+	projects, err := json.Marshal(nonNilStrings(token.Projects))
+	if err != nil {
+		return err
+	}
+	tools, err := json.Marshal(nonNilStrings(token.Tools))
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`
+		INSERT INTO mcp_tokens (id, name, prefix, token_hash, projects, tools, created_at, last_used_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, token.ID, token.Name, token.Prefix, tokenHash, string(projects), string(tools), token.CreatedAt, token.LastUsedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create mcp token %s: %w", token.ID, err)
+	}
+	return nil
+}
+
+// ListMCPTokens returns all MCP API tokens ordered by creation time.
+func (s *ConfigStore) ListMCPTokens() ([]*models.MCPAPIToken, error) {
+	rows, err := s.db.Query(`
+		SELECT id, name, prefix, projects, tools, created_at, last_used_at
+		FROM mcp_tokens
+		ORDER BY created_at, id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mcp tokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := make([]*models.MCPAPIToken, 0)
+	for rows.Next() {
+		token, err := scanMCPToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate mcp tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// GetMCPTokenByHash looks up the token matching a secret hash; it returns nil when none matches.
+func (s *ConfigStore) GetMCPTokenByHash(tokenHash string) (*models.MCPAPIToken, error) {
+	row := s.db.QueryRow(`
+		SELECT id, name, prefix, projects, tools, created_at, last_used_at
+		FROM mcp_tokens WHERE token_hash = ?
+	`, tokenHash)
+	token, err := scanMCPToken(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return token, err
+}
+
+// TouchMCPToken records the last time a token authenticated a request.
+func (s *ConfigStore) TouchMCPToken(id string, usedAt int64) error {
+	if _, err := s.db.Exec(`UPDATE mcp_tokens SET last_used_at = ? WHERE id = ?`, usedAt, id); err != nil {
+		return fmt.Errorf("failed to update mcp token %s: %w", id, err)
+	}
+	return nil
+}
+
+// DeleteMCPToken revokes a token by id.
+func (s *ConfigStore) DeleteMCPToken(id string) error {
+	result, err := s.db.Exec(`DELETE FROM mcp_tokens WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete mcp token %s: %w", id, err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("mcp token not found: %s", id)
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanMCPToken(row rowScanner) (*models.MCPAPIToken, error) {
+	token := &models.MCPAPIToken{}
+	var projects, tools string
+	if err := row.Scan(&token.ID, &token.Name, &token.Prefix, &projects, &tools, &token.CreatedAt, &token.LastUsedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read mcp token: %w", err)
+	}
+	if err := json.Unmarshal([]byte(projects), &token.Projects); err != nil {
+		return nil, fmt.Errorf("invalid project scope for mcp token %s: %w", token.ID, err)
+	}
+	if err := json.Unmarshal([]byte(tools), &token.Tools); err != nil {
+		return nil, fmt.Errorf("invalid tool scope for mcp token %s: %w", token.ID, err)
+	}
+	return token, nil
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
 func ensureEmbeddingModelColumns(db *sql.DB) error {
 	addColumn := func(column, decl string) error {
 		stmt := fmt.Sprintf("ALTER TABLE embedding_models ADD COLUMN %s %s", column, decl)
@@ -353,5 +556,15 @@ func ensureEmbeddingModelColumns(db *sql.DB) error {
 	if err := addColumn("backend", "TEXT DEFAULT 'onnx'"); err != nil {
 		return err
 	}
+	for _, column := range []string{"base_url", "remote_model", "api_key", "query_prefix", "document_prefix", "sha256", "tokenizer_sha256"} {
+		if err := addColumn(column, "TEXT NOT NULL DEFAULT ''"); err != nil {
+			return err
+		}
+	}
+	for _, column := range []string{"batch_size", "max_retries", "timeout_seconds", "supports_matryoshka"} {
+		if err := addColumn(column, "INTEGER NOT NULL DEFAULT 0"); err != nil {
+			return err
+		}
+	}
 	return nil
 }
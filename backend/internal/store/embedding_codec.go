@@ -0,0 +1,189 @@
+package store
+
+import (
+	"CodeTextor/backend/pkg/models"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// binaryRescoreFactor is how many Hamming candidates per requested result are rescored
+// against the float query in binary storage mode.
+const binaryRescoreFactor = 4
+
+// encodeEmbedding packs vec using the given storage format (see models.EmbeddingStorage*).
+func encodeEmbedding(vec []float32, format string) ([]byte, error) {
+	switch format {
+	case models.EmbeddingStorageFloat32, "":
+		return float32SliceToByteSlice(vec)
+	case models.EmbeddingStorageFloat16:
+		out := make([]byte, 2*len(vec))
+		for i, f := range vec {
+			binary.LittleEndian.PutUint16(out[i*2:], float32ToFloat16(f))
+		}
+		return out, nil
+	case models.EmbeddingStorageInt8:
+		return encodeInt8(vec), nil
+	case models.EmbeddingStorageBinary:
+		return encodeBinary(vec), nil
+	default:
+		return nil, fmt.Errorf("unknown embedding storage format %q", format)
+	}
+}
+
+// decodeEmbedding restores a float vector of dim values from a blob written by encodeEmbedding.
+// Binary codes decode to ±1 components, which keeps cosine similarity meaningful.
+func decodeEmbedding(data []byte, format string, dim int) ([]float32, error) {
+	switch format {
+	case models.EmbeddingStorageFloat32, "":
+		return byteSliceToFloat32Slice(data)
+	case models.EmbeddingStorageFloat16:
+		if len(data)%2 != 0 {
+			return nil, fmt.Errorf("float16 embedding length %d is not a multiple of 2", len(data))
+		}
+		out := make([]float32, len(data)/2)
+		for i := range out {
+			out[i] = float16ToFloat32(binary.LittleEndian.Uint16(data[i*2:]))
+		}
+		return out, nil
+	case models.EmbeddingStorageInt8:
+		if len(data) == 0 {
+			return []float32{}, nil
+		}
+		if len(data) < 4 {
+			return nil, fmt.Errorf("int8 embedding is missing its scale")
+		}
+		scale := math.Float32frombits(binary.LittleEndian.Uint32(data))
+		out := make([]float32, len(data)-4)
+		for i, q := range data[4:] {
+			out[i] = float32(int8(q)) * scale
+		}
+		return out, nil
+	case models.EmbeddingStorageBinary:
+		if dim <= 0 || len(data) != (dim+7)/8 {
+			return nil, fmt.Errorf("binary embedding of %d bytes does not match dimension %d", len(data), dim)
+		}
+		out := make([]float32, dim)
+		for i := range out {
+			if data[i/8]&(0x80>>(i%8)) != 0 {
+				out[i] = 1
+			} else {
+				out[i] = -1
+			}
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("unknown embedding storage format %q", format)
+	}
+}
+
+// encodeInt8 stores a per-vector float32 scale followed by one signed byte per component.
+func encodeInt8(vec []float32) []byte {
+	if len(vec) == 0 {
+		return []byte{}
+	}
+	maxAbs := float64(0)
+	for _, f := range vec {
+		maxAbs = math.Max(maxAbs, math.Abs(float64(f)))
+	}
+	scale := float32(maxAbs / 127)
+	out := make([]byte, 4+len(vec))
+	binary.LittleEndian.PutUint32(out, math.Float32bits(scale))
+	if scale == 0 {
+		return out
+	}
+	for i, f := range vec {
+		q := math.Round(float64(f) / float64(scale))
+		out[4+i] = byte(int8(math.Max(-127, math.Min(127, q))))
+	}
+	return out
+}
+
+// encodeBinary keeps one sign bit per component, most significant bit first.
+func encodeBinary(vec []float32) []byte {
+	out := make([]byte, (len(vec)+7)/8)
+	for i, f := range vec {
+		if f > 0 {
+			out[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return out
+}
+
+// hammingSimilarity estimates the cosine similarity of two sign codes from their Hamming
+// distance: vectors that disagree on a fraction h of their signs are roughly cos(πh) apart.
+func hammingSimilarity(a, b []byte, dim int) float64 {
+	if dim <= 0 || len(a) != len(b) {
+		return 0
+	}
+	distance := 0
+	for i := range a {
+		distance += bits.OnesCount8(a[i] ^ b[i])
+	}
+	return math.Cos(math.Pi * float64(distance) / float64(dim))
+}
+
+// float32ToFloat16 converts to IEEE 754 half precision with round-to-nearest-even.
+func float32ToFloat16(f float32) uint16 {
+	raw := math.Float32bits(f)
+	sign := uint16(raw>>16) & 0x8000
+	rawExp := (raw >> 23) & 0xff
+	mant := raw & 0x7fffff
+	if rawExp == 0xff {
+		if mant != 0 {
+			return sign | 0x7e00
+		}
+		return sign | 0x7c00
+	}
+
+	exp := int(rawExp) - 127 + 15
+	switch {
+	case exp >= 0x1f:
+		return sign | 0x7c00
+	case exp <= 0:
+		if exp < -10 {
+			return sign
+		}
+		mant |= 0x800000
+		shift := uint32(14 - exp)
+		half := uint16(mant >> shift)
+		rem := mant & (1<<shift - 1)
+		halfway := uint32(1) << (shift - 1)
+		if rem > halfway || (rem == halfway && half&1 == 1) {
+			half++
+		}
+		return sign | half
+	}
+
+	half := sign | uint16(exp)<<10 | uint16(mant>>13)
+	rem := mant & 0x1fff
+	// A carry out of the mantissa correctly bumps the exponent (up to infinity).
+	if rem > 0x1000 || (rem == 0x1000 && half&1 == 1) {
+		half++
+	}
+	return half
+}
+
+// float16ToFloat32 widens an IEEE 754 half precision value.
+func float16ToFloat32(h uint16) float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1f
+	mant := uint32(h & 0x3ff)
+	switch exp {
+	case 0x1f:
+		return math.Float32frombits(sign | 0x7f800000 | mant<<13)
+	case 0:
+		if mant == 0 {
+			return math.Float32frombits(sign)
+		}
+		// Normalise the subnormal into a regular float32.
+		e := uint32(127 - 15 + 1)
+		for mant&0x400 == 0 {
+			mant <<= 1
+			e--
+		}
+		return math.Float32frombits(sign | e<<23 | (mant&0x3ff)<<13)
+	}
+	return math.Float32frombits(sign | (exp+127-15)<<23 | mant<<13)
+}
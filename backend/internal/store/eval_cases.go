@@ -0,0 +1,83 @@
+package store
+
+import (
+	"CodeTextor/backend/pkg/models"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ListEvalCases returns the project's stored evaluation cases in insertion order.
+func (s *VectorStore) ListEvalCases() ([]models.EvalCase, error) {
+	rows, err := s.db.Query(`SELECT query, expected_file, expected_symbol, source FROM eval_cases ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list evaluation cases: %w", err)
+	}
+	defer rows.Close()
+
+	cases := []models.EvalCase{}
+	for rows.Next() {
+		var c models.EvalCase
+		if err := rows.Scan(&c.Query, &c.ExpectedFile, &c.ExpectedSymbol, &c.Source); err != nil {
+			return nil, fmt.Errorf("failed to scan evaluation case: %w", err)
+		}
+		cases = append(cases, c)
+	}
+	return cases, rows.Err()
+}
+
+// ReplaceEvalCases swaps the stored evaluation cases for cases in one transaction.
+func (s *VectorStore) ReplaceEvalCases(cases []models.EvalCase) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin evaluation case transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM eval_cases`); err != nil {
+		return fmt.Errorf("failed to clear evaluation cases: %w", err)
+	}
+	now := time.Now().Unix()
+	for _, c := range cases {
+		if _, err := tx.Exec(
+			`INSERT INTO eval_cases (query, expected_file, expected_symbol, source, created_at) VALUES (?, ?, ?, ?, ?)`,
+			c.Query, c.ExpectedFile, c.ExpectedSymbol, c.Source, now,
+		); err != nil {
+			return fmt.Errorf("failed to store evaluation case %q: %w", c.Query, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit evaluation cases: %w", err)
+	}
+	return nil
+}
+
+// DocumentedChunks returns the file path, symbol name and kind and docstring of every chunk
+// that has both a symbol name and a docstring, ordered by file and position.
+func (s *VectorStore) DocumentedChunks() ([]*models.Chunk, error) {
+	rows, err := s.db.Query(`
+		SELECT f.path, c.symbol_name, c.symbol_kind, c.doc_string
+		FROM chunks c
+		JOIN files f ON f.pk = c.file_id
+		WHERE c.symbol_name IS NOT NULL AND c.symbol_name <> ''
+		  AND c.doc_string IS NOT NULL AND c.doc_string <> ''
+		ORDER BY f.path, c.line_start
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documented chunks: %w", err)
+	}
+	defer rows.Close()
+
+	var chunks []*models.Chunk
+	for rows.Next() {
+		var kind sql.NullString
+		chunk := &models.Chunk{ProjectID: s.projectID}
+		if err := rows.Scan(&chunk.FilePath, &chunk.SymbolName, &kind, &chunk.DocString); err != nil {
+			return nil, fmt.Errorf("failed to scan documented chunk: %w", err)
+		}
+		chunk.SymbolKind = kind.String
+		chunks = append(chunks, chunk)
+	}
+	return chunks, rows.Err()
+}
@@ -0,0 +1,186 @@
+package store
+
+import (
+	"CodeTextor/backend/pkg/models"
+	"fmt"
+	"strings"
+)
+
+const defaultAuditQueryLimit = 100
+
+// InsertMCPAuditEntry appends an entry to the MCP audit log and sets its id.
+func (s *ConfigStore) InsertMCPAuditEntry(entry *models.MCPAuditEntry) error {
+	if entry == nil {
+		return fmt.Errorf("audit entry cannot be nil")
+	}
+	result, err := s.db.Exec(`
+		INSERT INTO mcp_audit_log (
+			timestamp, session_id, client_name, client_version, token_name, project_id,
+			method, name, arguments, result_bytes, duration_ms, error
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, entry.Timestamp, entry.SessionID, entry.ClientName, entry.ClientVersion, entry.TokenName,
+		entry.ProjectID, entry.Method, entry.Name, entry.Arguments, entry.ResultBytes,
+		entry.DurationMs, entry.Error)
+	if err != nil {
+		return fmt.Errorf("failed to insert mcp audit entry: %w", err)
+	}
+	if id, err := result.LastInsertId(); err == nil {
+		entry.ID = id
+	}
+	return nil
+}
+
+func auditWhereClause(query models.MCPAuditQuery) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if query.ClientName != "" {
+		conditions = append(conditions, "client_name = ?")
+		args = append(args, query.ClientName)
+	}
+	if query.ProjectID != "" {
+		conditions = append(conditions, "project_id = ?")
+		args = append(args, query.ProjectID)
+	}
+	if query.Name != "" {
+		conditions = append(conditions, "name = ?")
+		args = append(args, query.Name)
+	}
+	if query.ErrorsOnly {
+		conditions = append(conditions, "error != ''")
+	}
+	if query.Since > 0 {
+		conditions = append(conditions, "timestamp >= ?")
+		args = append(args, query.Since)
+	}
+	if query.Until > 0 {
+		conditions = append(conditions, "timestamp < ?")
+		args = append(args, query.Until)
+	}
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// QueryMCPAuditLog returns matching audit entries, newest first.
+// A negative limit returns every matching entry.
+func (s *ConfigStore) QueryMCPAuditLog(query models.MCPAuditQuery) ([]*models.MCPAuditEntry, error) {
+	where, args := auditWhereClause(query)
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultAuditQueryLimit
+	}
+	args = append(args, limit, query.Offset)
+
+	rows, err := s.db.Query(`
+		SELECT id, timestamp, session_id, client_name, client_version, token_name, project_id,
+			method, name, arguments, result_bytes, duration_ms, error
+		FROM mcp_audit_log`+where+`
+		ORDER BY timestamp DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query mcp audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*models.MCPAuditEntry, 0)
+	for rows.Next() {
+		entry := &models.MCPAuditEntry{}
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.Timestamp,
+			&entry.SessionID,
+			&entry.ClientName,
+			&entry.ClientVersion,
+			&entry.TokenName,
+			&entry.ProjectID,
+			&entry.Method,
+			&entry.Name,
+			&entry.Arguments,
+			&entry.ResultBytes,
+			&entry.DurationMs,
+			&entry.Error,
+		); err != nil {
+			return nil, fmt.Errorf("failed to read mcp audit entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate mcp audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// MCPClientStats aggregates audit entries per client name and version.
+func (s *ConfigStore) MCPClientStats() ([]*models.MCPClientStats, error) {
+	rows, err := s.db.Query(`
+		SELECT client_name, client_version, COUNT(*),
+			SUM(CASE WHEN error != '' THEN 1 ELSE 0 END),
+			AVG(duration_ms), MAX(timestamp)
+		FROM mcp_audit_log
+		GROUP BY client_name, client_version
+		ORDER BY MAX(timestamp) DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate mcp client stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make([]*models.MCPClientStats, 0)
+	for rows.Next() {
+		stat := &models.MCPClientStats{}
+		if err := rows.Scan(
+			&stat.ClientName,
+			&stat.ClientVersion,
+			&stat.CallCount,
+			&stat.ErrorCount,
+			&stat.AverageResponseTime,
+			&stat.LastSeen,
+		); err != nil {
+			return nil, fmt.Errorf("failed to read mcp client stats: %w", err)
+		}
+		stats = append(stats, stat)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate mcp client stats: %w", err)
+	}
+	return stats, nil
+}
+
+// PruneMCPAuditLog deletes entries older than cutoff (Unix ms, ignored when zero) and keeps at
+// most maxEntries of the newest entries (ignored when zero). It returns the number removed.
+func (s *ConfigStore) PruneMCPAuditLog(cutoff int64, maxEntries int) (int64, error) {
+	var removed int64
+	if cutoff > 0 {
+		result, err := s.db.Exec(`DELETE FROM mcp_audit_log WHERE timestamp < ?`, cutoff)
+		if err != nil {
+			return 0, fmt.Errorf("failed to prune mcp audit log: %w", err)
+		}
+		if affected, err := result.RowsAffected(); err == nil {
+			removed += affected
+		}
+	}
+	if maxEntries > 0 {
+		result, err := s.db.Exec(`
+			DELETE FROM mcp_audit_log WHERE id NOT IN (
+				SELECT id FROM mcp_audit_log ORDER BY timestamp DESC, id DESC LIMIT ?
+			)
+		`, maxEntries)
+		if err != nil {
+			return removed, fmt.Errorf("failed to cap mcp audit log: %w", err)
+		}
+		if affected, err := result.RowsAffected(); err == nil {
+			removed += affected
+		}
+	}
+	return removed, nil
+}
+
+// ClearMCPAuditLog removes every audit entry.
+func (s *ConfigStore) ClearMCPAuditLog() error {
+	if _, err := s.db.Exec(`DELETE FROM mcp_audit_log`); err != nil {
+		return fmt.Errorf("failed to clear mcp audit log: %w", err)
+	}
+	return nil
+}
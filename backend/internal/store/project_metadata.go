@@ -102,8 +102,13 @@ func SaveProjectMetadata(dbPath string, project *models.Project) error {
 	return saveProjectMetadataWithDB(db, project)
 }
 
-// saveProjectMetadataWithDB uses the provided *sql.DB connection to persist metadata.
-func saveProjectMetadataWithDB(db *sql.DB, project *models.Project) error {
+// sqlExecer is satisfied by *sql.DB and *sql.Tx.
+type sqlExecer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// saveProjectMetadataWithDB uses the provided connection or transaction to persist metadata.
+func saveProjectMetadataWithDB(db sqlExecer, project *models.Project) error {
 	if project == nil {
 		return fmt.Errorf("project cannot be nil")
 	}
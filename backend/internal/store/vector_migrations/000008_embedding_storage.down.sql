@@ -0,0 +1,5 @@
+-- Older schemas read every blob as float32; quantized chunks must be re-indexed.
+DELETE FROM chunks WHERE embedding_format <> 'float32';
+
+ALTER TABLE chunks DROP COLUMN embedding_dim;
+ALTER TABLE chunks DROP COLUMN embedding_format;
@@ -0,0 +1,7 @@
+-- Chunks record how their embedding blob is encoded (float32, float16, int8, binary) and the
+-- vector dimension, which binary codes cannot recover from their length.
+ALTER TABLE chunks ADD COLUMN embedding_format TEXT NOT NULL DEFAULT 'float32';
+ALTER TABLE chunks ADD COLUMN embedding_dim INTEGER NOT NULL DEFAULT 0;
+
+-- Existing rows are raw little-endian float32 vectors.
+UPDATE chunks SET embedding_dim = LENGTH(embedding) / 4;
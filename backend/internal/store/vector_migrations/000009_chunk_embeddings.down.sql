@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_chunk_embeddings_model;
+DROP TABLE IF EXISTS chunk_embeddings;
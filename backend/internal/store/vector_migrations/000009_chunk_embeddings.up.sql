@@ -0,0 +1,14 @@
+-- Embeddings computed for chunks by a model other than the one in chunks.embedding. While a
+-- project switches models, the new model's vectors are built here and swapped in at the end.
+CREATE TABLE chunk_embeddings (
+    chunk_id TEXT NOT NULL,
+    model_id TEXT NOT NULL,
+    embedding BLOB NOT NULL,
+    embedding_format TEXT NOT NULL DEFAULT 'float32',
+    embedding_dim INTEGER NOT NULL,
+    created_at INTEGER NOT NULL,
+    PRIMARY KEY (chunk_id, model_id),
+    FOREIGN KEY(chunk_id) REFERENCES chunks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_chunk_embeddings_model ON chunk_embeddings(model_id);
@@ -0,0 +1,10 @@
+-- Retrieval-quality test cases for the project: a query and the file (optionally the symbol)
+-- a search for it is expected to return.
+CREATE TABLE eval_cases (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    query TEXT NOT NULL,
+    expected_file TEXT NOT NULL,
+    expected_symbol TEXT NOT NULL DEFAULT '',
+    source TEXT NOT NULL DEFAULT 'manual',
+    created_at INTEGER NOT NULL
+);
@@ -32,6 +32,8 @@ type VectorStore struct {
 	dbPath    string
 	fileIDMu  sync.RWMutex
 	fileIDs   map[string]int64
+	storageMu sync.RWMutex
+	storage   string
 }
 
 // NewVectorStore creates a new VectorStore instance for a given project.
@@ -70,6 +72,7 @@ func NewVectorStore(projectID, projectSlug string) (*VectorStore, error) {
 		projectID: projectID,
 		dbPath:    dbPath,
 		fileIDs:   make(map[string]int64),
+		storage:   models.EmbeddingStorageFloat32,
 	}, nil
 }
 
@@ -186,6 +189,111 @@ func (s *VectorStore) Close() error {
 	return s.db.Close()
 }
 
+// SetEmbeddingStorage selects the encoding used for embeddings inserted from now on.
+// Existing rows keep their encoding until ReencodeEmbeddings is called.
+func (s *VectorStore) SetEmbeddingStorage(format string) {
+	if format == "" {
+		format = models.EmbeddingStorageFloat32
+	}
+	s.storageMu.Lock()
+	s.storage = format
+	s.storageMu.Unlock()
+}
+
+// EmbeddingStorage returns the encoding used for newly inserted embeddings.
+func (s *VectorStore) EmbeddingStorage() string {
+	s.storageMu.RLock()
+	defer s.storageMu.RUnlock()
+	return s.storage
+}
+
+// ReencodeEmbeddings rewrites every embedding stored in another format using format, in a
+// single transaction, and returns the number of vectors converted (chunks plus the vectors of
+// additional models). Converting to a wider format keeps the precision already lost; re-index
+// the project to restore full vectors.
+func (s *VectorStore) ReencodeEmbeddings(format string) (int, error) {
+	if _, err := encodeEmbedding(nil, format); err != nil {
+		return 0, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin re-encode transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	chunks, err := reencodeRows(tx, format,
+		`SELECT id, '', embedding, embedding_format, embedding_dim FROM chunks WHERE embedding_format <> ?`,
+		`UPDATE chunks SET embedding = ?, embedding_format = ? WHERE id = ?`)
+	if err != nil {
+		return 0, err
+	}
+	additional, err := reencodeRows(tx, format,
+		`SELECT chunk_id, model_id, embedding, embedding_format, embedding_dim FROM chunk_embeddings WHERE embedding_format <> ?`,
+		`UPDATE chunk_embeddings SET embedding = ?, embedding_format = ? WHERE chunk_id = ? AND model_id = ?`)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit re-encoded embeddings: %w", err)
+	}
+	return chunks + additional, nil
+}
+
+// reencodeRows converts the embeddings returned by selectSQL (id, model, blob, format, dim) to
+// format and writes them back with updateSQL (blob, format, id, plus model when it is set).
+func reencodeRows(tx *sql.Tx, format, selectSQL, updateSQL string) (int, error) {
+	type storedEmbedding struct {
+		id     string
+		model  string
+		data   []byte
+		format string
+		dim    int
+	}
+	rows, err := tx.Query(selectSQL, format)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query embeddings to re-encode: %w", err)
+	}
+	var pending []storedEmbedding
+	for rows.Next() {
+		var row storedEmbedding
+		if err := rows.Scan(&row.id, &row.model, &row.data, &row.format, &row.dim); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan embedding: %w", err)
+		}
+		pending = append(pending, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to iterate embeddings: %w", err)
+	}
+
+	stmt, err := tx.Prepare(updateSQL)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare re-encode statement: %w", err)
+	}
+	defer stmt.Close()
+	for _, row := range pending {
+		vec, err := decodeEmbedding(row.data, row.format, row.dim)
+		if err != nil {
+			return 0, fmt.Errorf("failed to decode embedding of chunk %s: %w", row.id, err)
+		}
+		encoded, err := encodeEmbedding(vec, format)
+		if err != nil {
+			return 0, err
+		}
+		args := []any{encoded, format, row.id}
+		if row.model != "" {
+			args = append(args, row.model)
+		}
+		if _, err := stmt.Exec(args...); err != nil {
+			return 0, fmt.Errorf("failed to re-encode chunk %s: %w", row.id, err)
+		}
+	}
+	return len(pending), nil
+}
+
 // InsertChunk inserts a new chunk into the database with semantic metadata.
 // If a chunk with the same file and line range already exists, it will be replaced.
 func (s *VectorStore) InsertChunk(chunk *models.Chunk) error {
@@ -204,22 +312,23 @@ func (s *VectorStore) InsertChunk(chunk *models.Chunk) error {
 
 	stmt, err := s.db.Prepare(`
 		INSERT OR REPLACE INTO chunks (
-			id, file_id, content, embedding, embedding_model_id,
+			id, file_id, content, embedding, embedding_format, embedding_dim, embedding_model_id,
 			line_start, line_end, char_start, char_end,
 			language, symbol_name, symbol_kind, parent,
 			signature, visibility, package_name, doc_string,
 			token_count, is_collapsed, source_code,
 			created_at, updated_at
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to prepare insert chunk statement: %w", err)
 	}
 	defer stmt.Close()
 
-	// Convert []float32 to []byte for storage
-	embeddingBytes, err := float32SliceToByteSlice(chunk.Embedding)
+	// Convert []float32 to []byte in the project's storage format
+	format := s.EmbeddingStorage()
+	embeddingBytes, err := encodeEmbedding(chunk.Embedding, format)
 	if err != nil {
 		return fmt.Errorf("failed to convert embedding to bytes: %w", err)
 	}
@@ -229,6 +338,8 @@ func (s *VectorStore) InsertChunk(chunk *models.Chunk) error {
 		fileID,
 		chunk.Content,
 		embeddingBytes,
+		format,
+		len(chunk.Embedding),
 		chunk.EmbeddingModelID,
 		chunk.LineStart,
 		chunk.LineEnd,
@@ -585,6 +696,9 @@ func (s *VectorStore) RemoveFileAndArtifacts(filePath string) error {
 	if _, err := tx.Exec(`DELETE FROM chunk_symbols WHERE chunk_id IN (SELECT id FROM chunks WHERE file_id = ?)`, fileID); err != nil {
 		return fmt.Errorf("failed to delete chunk-symbol links for %s: %w", normalized, err)
 	}
+	if _, err := tx.Exec(`DELETE FROM chunk_embeddings WHERE chunk_id IN (SELECT id FROM chunks WHERE file_id = ?)`, fileID); err != nil {
+		return fmt.Errorf("failed to delete chunk embeddings for %s: %w", normalized, err)
+	}
 	if _, err := tx.Exec(`DELETE FROM chunks WHERE file_id = ?`, fileID); err != nil {
 		return fmt.Errorf("failed to delete chunks for %s: %w", normalized, err)
 	}
@@ -748,6 +862,68 @@ func (s *VectorStore) GetFileChunks(filePath string) ([]*models.Chunk, error) {
 	return chunks, nil
 }
 
+// FindSymbolChunks returns chunks that declare one of the given symbol names, optionally
+// restricted to the given symbol kinds. Only location, symbol and source fields are populated.
+func (s *VectorStore) FindSymbolChunks(names, kinds []string, limit int) ([]*models.Chunk, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+
+	query := `
+		SELECT c.id, f.path, c.line_start, c.line_end, c.language, c.symbol_name, c.symbol_kind,
+			c.signature, c.source_code
+		FROM chunks c
+		JOIN files f ON f.pk = c.file_id
+		WHERE c.symbol_name IN (` + placeholders(len(names)) + `)`
+	args := make([]interface{}, 0, len(names)+len(kinds)+1)
+	for _, name := range names {
+		args = append(args, name)
+	}
+	if len(kinds) > 0 {
+		query += ` AND c.symbol_kind IN (` + placeholders(len(kinds)) + `)`
+		for _, kind := range kinds {
+			args = append(args, kind)
+		}
+	}
+	query += ` ORDER BY f.path ASC, c.line_start ASC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query symbol chunks: %w", err)
+	}
+	defer rows.Close()
+
+	var chunks []*models.Chunk
+	for rows.Next() {
+		chunk := &models.Chunk{}
+		var language, symbolName, symbolKind, signature, sourceCode sql.NullString
+		if err := rows.Scan(
+			&chunk.ID, &chunk.FilePath, &chunk.LineStart, &chunk.LineEnd,
+			&language, &symbolName, &symbolKind, &signature, &sourceCode,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan symbol chunk: %w", err)
+		}
+		chunk.Language = language.String
+		chunk.SymbolName = symbolName.String
+		chunk.SymbolKind = symbolKind.String
+		chunk.Signature = signature.String
+		chunk.SourceCode = sourceCode.String
+		chunks = append(chunks, chunk)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating symbol chunks: %w", err)
+	}
+	return chunks, nil
+}
+
+func placeholders(count int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", count), ",")
+}
+
 // GetChunkByID retrieves a single chunk from the index.
 func (s *VectorStore) GetChunkByID(chunkID string) (*models.Chunk, error) {
 	trimmed := strings.TrimSpace(chunkID)
@@ -825,6 +1001,31 @@ func (s *VectorStore) GetChunkByID(chunkID string) (*models.Chunk, error) {
 	return chunk, nil
 }
 
+// GetChunkEmbedding returns the stored embedding of a chunk and the model that produced it.
+func (s *VectorStore) GetChunkEmbedding(chunkID string) ([]float32, string, error) {
+	trimmed := strings.TrimSpace(chunkID)
+	if trimmed == "" {
+		return nil, "", fmt.Errorf("chunk id cannot be empty")
+	}
+
+	var embeddingBytes []byte
+	var format string
+	var dim int
+	var modelID sql.NullString
+	err := s.db.QueryRow(`SELECT embedding, embedding_format, embedding_dim, embedding_model_id FROM chunks WHERE id = ?`, trimmed).Scan(&embeddingBytes, &format, &dim, &modelID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, "", fmt.Errorf("chunk not found: %s", trimmed)
+		}
+		return nil, "", fmt.Errorf("failed to load embedding for chunk %s: %w", trimmed, err)
+	}
+	vec, err := decodeEmbedding(embeddingBytes, format, dim)
+	if err != nil {
+		return nil, "", err
+	}
+	return vec, modelID.String, nil
+}
+
 // DeleteFileChunks removes all chunks associated with a file.
 func (s *VectorStore) DeleteFileChunks(filePath string) error {
 	fileID, normalizedPath, err := s.resolveFileID(filePath, true)
@@ -832,6 +1033,9 @@ func (s *VectorStore) DeleteFileChunks(filePath string) error {
 		return err
 	}
 
+	if _, err := s.db.Exec(`DELETE FROM chunk_embeddings WHERE chunk_id IN (SELECT id FROM chunks WHERE file_id = ?)`, fileID); err != nil {
+		return fmt.Errorf("failed to delete chunk embeddings for file %s: %w", normalizedPath, err)
+	}
 	if _, err := s.db.Exec(`DELETE FROM chunks WHERE file_id = ?`, fileID); err != nil {
 		return fmt.Errorf("failed to delete chunks for file %s: %w", normalizedPath, err)
 	}
@@ -872,10 +1076,11 @@ func (s *VectorStore) RebuildChunkSymbolLinks(filePath string) error {
 	return tx.Commit()
 }
 
-// ResetProjectData removes all indexed artifacts (chunks, symbols, outlines, files).
+// ResetProjectData removes all indexed artifacts (chunks, embeddings, symbols, outlines, files).
 func (s *VectorStore) ResetProjectData() error {
 	tables := []string{
 		"chunk_symbols",
+		"chunk_embeddings",
 		"chunks",
 		"symbols",
 		"outline_nodes",
@@ -939,38 +1144,77 @@ func byteSliceToFloat32Slice(bytes []byte) ([]float32, error) {
 // SearchSimilarChunks performs a brute-force cosine similarity search over all chunks.
 // This is a fallback implementation until a vector index (e.g., sqlite-vec) is integrated.
 func (s *VectorStore) SearchSimilarChunks(queryEmbedding []float32, k int) ([]*models.Chunk, error) {
+	chunks, _, err := s.SearchSimilarChunksAbove(queryEmbedding, k, math.Inf(-1))
+	return chunks, err
+}
+
+// SearchSimilarChunksAbove returns the k most similar chunks whose similarity is at least
+// minScore, plus the total number of chunks that clear the threshold.
+// Binary-encoded chunks are prefiltered by Hamming distance to the query's sign code and the
+// best candidates are rescored against the float query; their share of the total is counted
+// from the Hamming estimate.
+func (s *VectorStore) SearchSimilarChunksAbove(queryEmbedding []float32, k int, minScore float64) ([]*models.Chunk, int, error) {
+	return s.searchChunksAbove(queryEmbedding, k, minScore, `
+		SELECT c.id, f.path, c.content, c.embedding, c.embedding_format, c.embedding_dim, c.embedding_model_id, `+searchChunkColumns+`
+		FROM chunks c
+		JOIN files f ON f.pk = c.file_id
+	`)
+}
+
+// SearchModelChunksAbove works like SearchSimilarChunksAbove but ranks chunks by the vectors
+// modelID stored in chunk_embeddings; chunks without such a vector are not considered.
+func (s *VectorStore) SearchModelChunksAbove(modelID string, queryEmbedding []float32, k int, minScore float64) ([]*models.Chunk, int, error) {
+	return s.searchChunksAbove(queryEmbedding, k, minScore, `
+		SELECT c.id, f.path, c.content, ce.embedding, ce.embedding_format, ce.embedding_dim, ce.model_id, `+searchChunkColumns+`
+		FROM chunk_embeddings ce
+		JOIN chunks c ON c.id = ce.chunk_id
+		JOIN files f ON f.pk = c.file_id
+		WHERE ce.model_id = ?
+	`, modelID)
+}
+
+// searchChunkColumns are the chunk columns read after the embedding columns during search.
+const searchChunkColumns = `c.line_start, c.line_end, c.char_start, c.char_end,
+		       c.language, c.symbol_name, c.symbol_kind, c.parent, c.signature, c.visibility,
+		       c.package_name, c.doc_string, c.token_count, c.is_collapsed, c.source_code,
+		       c.created_at, c.updated_at`
+
+func (s *VectorStore) searchChunksAbove(queryEmbedding []float32, k int, minScore float64, query string, args ...any) ([]*models.Chunk, int, error) {
 	if len(queryEmbedding) == 0 {
-		return nil, fmt.Errorf("query embedding is empty")
+		return nil, 0, fmt.Errorf("query embedding is empty")
 	}
 
 	if k <= 0 {
 		k = 10
 	}
 
-	rows, err := s.db.Query(`
-		SELECT c.id, f.path, c.content, c.embedding, c.embedding_model_id, c.line_start, c.line_end, c.char_start, c.char_end,
-		       c.language, c.symbol_name, c.symbol_kind, c.parent, c.signature, c.visibility,
-		       c.package_name, c.doc_string, c.token_count, c.is_collapsed, c.source_code,
-		       c.created_at, c.updated_at
-		FROM chunks c
-		JOIN files f ON f.pk = c.file_id
-	`)
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query chunks for search: %w", err)
+		return nil, 0, fmt.Errorf("failed to query chunks for search: %w", err)
 	}
 	defer rows.Close()
 
 	queryNorm := dotProduct(queryEmbedding, queryEmbedding)
 	if queryNorm == 0 {
-		return nil, fmt.Errorf("query embedding has zero norm")
+		return nil, 0, fmt.Errorf("query embedding has zero norm")
 	}
 	queryNorm = math.Sqrt(queryNorm)
 
+	// Rows are ranked by their own encoding: decoded vectors go straight into top, while
+	// binary rows keep a wider Hamming shortlist that is rescored below. The store setting
+	// does not say how existing rows are encoded (a storage switch re-encodes them later).
 	top := newMinHeap(k)
+	hammingTop := newMinHeap(k * binaryRescoreFactor)
+	total := 0
+	queryCode := encodeBinary(queryEmbedding)
+	// Sign codes of prefiltered binary chunks, decoded only for the candidates that survive.
+	binaryCodes := make(map[string][]byte)
 
 	for rows.Next() {
 		chunk := &models.Chunk{}
 		var embeddingBytes []byte
+		var format string
+		var dim int
 		var language, symbolName, symbolKind, parent, signature, visibility sql.NullString
 		var packageName, docString, sourceCode sql.NullString
 		var tokenCount sql.NullInt64
@@ -981,6 +1225,8 @@ func (s *VectorStore) SearchSimilarChunks(queryEmbedding []float32, k int) ([]*m
 			&chunk.FilePath,
 			&chunk.Content,
 			&embeddingBytes,
+			&format,
+			&dim,
 			&chunk.EmbeddingModelID,
 			&chunk.LineStart,
 			&chunk.LineEnd,
@@ -1001,15 +1247,9 @@ func (s *VectorStore) SearchSimilarChunks(queryEmbedding []float32, k int) ([]*m
 			&chunk.UpdatedAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan chunk for search: %w", err)
+			return nil, 0, fmt.Errorf("failed to scan chunk for search: %w", err)
 		}
 
-		vec, err := byteSliceToFloat32Slice(embeddingBytes)
-		if err != nil {
-			return nil, err
-		}
-		chunk.Embedding = vec
-
 		// Assign nullable fields
 		if language.Valid {
 			chunk.Language = language.String
@@ -1045,20 +1285,106 @@ func (s *VectorStore) SearchSimilarChunks(queryEmbedding []float32, k int) ([]*m
 			chunk.SourceCode = sourceCode.String
 		}
 
-		if len(vec) == 0 {
+		if len(embeddingBytes) == 0 {
+			continue
+		}
+		var score float64
+		if format == models.EmbeddingStorageBinary {
+			if dim != len(queryEmbedding) {
+				continue
+			}
+			score = hammingSimilarity(queryCode, embeddingBytes, dim)
+			if score < minScore {
+				continue
+			}
+			binaryCodes[chunk.ID] = embeddingBytes
+			chunk.Similarity = score
+			total++
+			hammingTop.Push(chunk)
+			continue
+		}
+		vec, err := decodeEmbedding(embeddingBytes, format, dim)
+		if err != nil {
+			return nil, 0, err
+		}
+		chunk.Embedding = vec
+		score = cosineSimilarity(queryEmbedding, vec, queryNorm)
+		if score < minScore {
 			continue
 		}
-		score := cosineSimilarity(queryEmbedding, vec, queryNorm)
 		chunk.Similarity = score
+		total++
 		top.Push(chunk)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating search rows: %w", err)
+		return nil, 0, fmt.Errorf("error iterating search rows: %w", err)
+	}
+
+	// Rescore the Hamming candidates with the float query against their ±1 vectors.
+	for _, chunk := range hammingTop.Sorted() {
+		vec, err := decodeEmbedding(binaryCodes[chunk.ID], models.EmbeddingStorageBinary, len(queryEmbedding))
+		if err != nil {
+			return nil, 0, err
+		}
+		chunk.Embedding = vec
+		chunk.Similarity = cosineSimilarity(queryEmbedding, vec, queryNorm)
+		if chunk.Similarity >= minScore {
+			top.Push(chunk)
+		}
 	}
+	return top.Sorted(), total, nil
+}
 
-	result := top.Sorted()
-	return result, nil
+// PrimaryEmbeddingSize returns the bytes taken by the chunk vectors of the primary model and
+// their smallest dimension (0 when nothing is embedded), like ModelEmbeddingSize does for
+// chunk_embeddings.
+func (s *VectorStore) PrimaryEmbeddingSize() (int64, int, error) {
+	var size int64
+	var dim int
+	err := s.db.QueryRow(`
+		SELECT COALESCE(SUM(LENGTH(embedding)), 0), COALESCE(MIN(NULLIF(embedding_dim, 0)), 0)
+		FROM chunks
+	`).Scan(&size, &dim)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to measure primary embeddings: %w", err)
+	}
+	return size, dim, nil
+}
+
+// EmbeddingDimensions returns the distinct vector lengths of the stored embeddings.
+func (s *VectorStore) EmbeddingDimensions() ([]int, error) {
+	rows, err := s.db.Query(`SELECT DISTINCT embedding_dim FROM chunks WHERE embedding_dim > 0 ORDER BY embedding_dim`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list embedding dimensions: %w", err)
+	}
+	defer rows.Close()
+
+	var dimensions []int
+	for rows.Next() {
+		var dimension int
+		if err := rows.Scan(&dimension); err != nil {
+			return nil, fmt.Errorf("failed to scan embedding dimension: %w", err)
+		}
+		dimensions = append(dimensions, dimension)
+	}
+	return dimensions, rows.Err()
+}
+
+// IndexVersion returns a token that changes whenever chunks are added, replaced or removed,
+// or embeddings of additional models are stored. Temporary evaluation vectors do not count.
+// Search cursors embed it so pages are never stitched across different index states.
+func (s *VectorStore) IndexVersion() (string, error) {
+	var count, maxRowID, maxUpdated, modelEmbeddings int64
+	err := s.db.QueryRow(`
+		SELECT COUNT(*), COALESCE(MAX(rowid), 0), COALESCE(MAX(updated_at), 0),
+		       (SELECT COUNT(*) FROM chunk_embeddings WHERE model_id NOT LIKE ?)
+		FROM chunks
+	`, TemporaryModelPrefix+"%").Scan(&count, &maxRowID, &maxUpdated, &modelEmbeddings)
+	if err != nil {
+		return "", fmt.Errorf("failed to read index version: %w", err)
+	}
+	return fmt.Sprintf("%d.%d.%d.%d", count, maxRowID, maxUpdated, modelEmbeddings), nil
 }
 
 func cosineSimilarity(a []float32, b []float32, normA float64) float64 {
@@ -1121,7 +1447,13 @@ func (h *minHeap) Sorted() []*models.Chunk {
 	// Return in descending order
 	res := make([]*models.Chunk, len(h.data))
 	copy(res, h.data)
-	sort.Slice(res, func(i, j int) bool { return res[i].Similarity > res[j].Similarity })
+	// Break ties by id so repeated searches (and cursor pages) see the same order.
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Similarity != res[j].Similarity {
+			return res[i].Similarity > res[j].Similarity
+		}
+		return res[i].ID < res[j].ID
+	})
 	return res
 }
 
@@ -1197,6 +1529,23 @@ func (s *VectorStore) GetStats() (*models.ProjectStats, error) {
 		stats.LastIndexedAtUnix = t.Unix()
 	}
 
+	// Space used by embeddings (primary and additional models, but not temporary evaluation
+	// vectors) versus what raw float32 vectors would take.
+	var embeddingBytes, float32Bytes int64
+	err = s.db.QueryRow(`
+		SELECT COALESCE(SUM(bytes), 0), COALESCE(SUM(dim), 0) * 4 FROM (
+			SELECT LENGTH(embedding) AS bytes, embedding_dim AS dim FROM chunks
+			UNION ALL
+			SELECT LENGTH(embedding), embedding_dim FROM chunk_embeddings WHERE model_id NOT LIKE ?
+		)
+	`, TemporaryModelPrefix+"%").Scan(&embeddingBytes, &float32Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure embedding storage: %w", err)
+	}
+	stats.EmbeddingStorage = s.EmbeddingStorage()
+	stats.EmbeddingBytes = embeddingBytes
+	stats.EmbeddingBytesSaved = max(float32Bytes-embeddingBytes, 0)
+
 	rows, err := s.db.Query(`
 		SELECT embedding_model_id, COUNT(*) as cnt
 		FROM chunks
@@ -1217,6 +1566,7 @@ func (s *VectorStore) GetStats() (*models.ProjectStats, error) {
 		usage := models.ProjectEmbeddingModelUsage{
 			ModelID:    strings.TrimSpace(modelID.String),
 			ChunkCount: int(count),
+			Primary:    true,
 		}
 		if usage.ModelID == "" {
 			usage.ModelID = "unknown"
@@ -1226,6 +1576,18 @@ func (s *VectorStore) GetStats() (*models.ProjectStats, error) {
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("failed to iterate embedding model usage: %w", err)
 	}
+	rows.Close()
+
+	additional, err := s.modelEmbeddingUsage()
+	if err != nil {
+		return nil, err
+	}
+	stats.EmbeddingModels = append(stats.EmbeddingModels, additional...)
+	for idx := range stats.EmbeddingModels {
+		if stats.TotalChunks > 0 {
+			stats.EmbeddingModels[idx].Coverage = float64(stats.EmbeddingModels[idx].ChunkCount) / float64(stats.TotalChunks)
+		}
+	}
 
 	return stats, nil
 }
@@ -1,7 +1,112 @@
 package embedding
 
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
 // EmbeddingClient is the interface for any embedding service client.
 type EmbeddingClient interface {
+	// GenerateEmbeddings embeds documents (indexed chunks).
 	GenerateEmbeddings(texts []string) ([][]float32, error)
+	// GenerateQueryEmbeddings embeds search queries. Asymmetric models embed queries
+	// differently from documents; symmetric clients treat both the same.
+	GenerateQueryEmbeddings(texts []string) ([][]float32, error)
 	Close() error
 }
+
+// prefixedClient prepends a model's instruction prefixes (e.g. "query: " / "passage: " for e5)
+// before delegating to the wrapped client.
+type prefixedClient struct {
+	EmbeddingClient
+	queryPrefix    string
+	documentPrefix string
+}
+
+// WithInstructionPrefixes wraps client so queries and documents get the given prefixes.
+// The client is returned unchanged when both prefixes are empty.
+func WithInstructionPrefixes(client EmbeddingClient, queryPrefix, documentPrefix string) EmbeddingClient {
+	if client == nil || (queryPrefix == "" && documentPrefix == "") {
+		return client
+	}
+	return &prefixedClient{EmbeddingClient: client, queryPrefix: queryPrefix, documentPrefix: documentPrefix}
+}
+
+func (c *prefixedClient) GenerateEmbeddings(texts []string) ([][]float32, error) {
+	return c.EmbeddingClient.GenerateEmbeddings(prefixTexts(c.documentPrefix, texts))
+}
+
+func (c *prefixedClient) GenerateQueryEmbeddings(texts []string) ([][]float32, error) {
+	return c.EmbeddingClient.GenerateQueryEmbeddings(prefixTexts(c.queryPrefix, texts))
+}
+
+func prefixTexts(prefix string, texts []string) []string {
+	if prefix == "" {
+		return texts
+	}
+	prefixed := make([]string, len(texts))
+	for i, text := range texts {
+		if strings.HasPrefix(text, prefix) {
+			prefixed[i] = text
+			continue
+		}
+		prefixed[i] = prefix + text
+	}
+	return prefixed
+}
+
+// truncatingClient keeps the leading components of every vector and re-normalises them,
+// which is how Matryoshka-trained models produce smaller embeddings.
+type truncatingClient struct {
+	EmbeddingClient
+	dimension int
+}
+
+// WithOutputDimension wraps client so vectors are truncated to dimension components and
+// rescaled to unit length. The client is returned unchanged when dimension is not positive.
+func WithOutputDimension(client EmbeddingClient, dimension int) EmbeddingClient {
+	if client == nil || dimension <= 0 {
+		return client
+	}
+	return &truncatingClient{EmbeddingClient: client, dimension: dimension}
+}
+
+func (c *truncatingClient) GenerateEmbeddings(texts []string) ([][]float32, error) {
+	vectors, err := c.EmbeddingClient.GenerateEmbeddings(texts)
+	if err != nil {
+		return nil, err
+	}
+	return truncateEmbeddings(vectors, c.dimension)
+}
+
+func (c *truncatingClient) GenerateQueryEmbeddings(texts []string) ([][]float32, error) {
+	vectors, err := c.EmbeddingClient.GenerateQueryEmbeddings(texts)
+	if err != nil {
+		return nil, err
+	}
+	return truncateEmbeddings(vectors, c.dimension)
+}
+
+func truncateEmbeddings(vectors [][]float32, dimension int) ([][]float32, error) {
+	out := make([][]float32, len(vectors))
+	for i, vec := range vectors {
+		if len(vec) < dimension {
+			return nil, fmt.Errorf("cannot truncate a %d-dim embedding to %d dimensions", len(vec), dimension)
+		}
+		truncated := make([]float32, dimension)
+		copy(truncated, vec[:dimension])
+		norm := float64(0)
+		for _, f := range truncated {
+			norm += float64(f) * float64(f)
+		}
+		if norm > 0 {
+			scale := float32(1 / math.Sqrt(norm))
+			for j := range truncated {
+				truncated[j] *= scale
+			}
+		}
+		out[i] = truncated
+	}
+	return out, nil
+}
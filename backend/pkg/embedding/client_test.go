@@ -0,0 +1,79 @@
+package embedding
+
+import (
+	"math"
+	"testing"
+)
+
+type recordingClient struct {
+	documents []string
+	queries   []string
+}
+
+func (c *recordingClient) GenerateEmbeddings(texts []string) ([][]float32, error) {
+	c.documents = append(c.documents, texts...)
+	return make([][]float32, len(texts)), nil
+}
+
+func (c *recordingClient) GenerateQueryEmbeddings(texts []string) ([][]float32, error) {
+	c.queries = append(c.queries, texts...)
+	return make([][]float32, len(texts)), nil
+}
+
+func (c *recordingClient) Close() error { return nil }
+
+func TestWithInstructionPrefixes(t *testing.T) {
+	inner := &recordingClient{}
+	if WithInstructionPrefixes(inner, "", "") != EmbeddingClient(inner) {
+		t.Fatalf("expected the client to be returned unchanged without prefixes")
+	}
+
+	client := WithInstructionPrefixes(inner, "query: ", "passage: ")
+	if _, err := client.GenerateEmbeddings([]string{"func Save()", "passage: already"}); err != nil {
+		t.Fatalf("documents: %v", err)
+	}
+	if _, err := client.GenerateQueryEmbeddings([]string{"where are users saved"}); err != nil {
+		t.Fatalf("queries: %v", err)
+	}
+	if inner.documents[0] != "passage: func Save()" || inner.documents[1] != "passage: already" {
+		t.Fatalf("unexpected documents %q", inner.documents)
+	}
+	if len(inner.queries) != 1 || inner.queries[0] != "query: where are users saved" {
+		t.Fatalf("unexpected queries %q", inner.queries)
+	}
+}
+
+type staticClient struct {
+	vector []float32
+}
+
+func (c staticClient) GenerateEmbeddings(texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i := range out {
+		out[i] = append([]float32(nil), c.vector...)
+	}
+	return out, nil
+}
+
+func (c staticClient) GenerateQueryEmbeddings(texts []string) ([][]float32, error) {
+	return c.GenerateEmbeddings(texts)
+}
+
+func (c staticClient) Close() error { return nil }
+
+func TestWithOutputDimensionTruncatesAndNormalises(t *testing.T) {
+	client := WithOutputDimension(staticClient{vector: []float32{3, 4, 12}}, 2)
+	for _, generate := range []func([]string) ([][]float32, error){client.GenerateEmbeddings, client.GenerateQueryEmbeddings} {
+		vectors, err := generate([]string{"x"})
+		if err != nil {
+			t.Fatalf("generate: %v", err)
+		}
+		if len(vectors[0]) != 2 || math.Abs(float64(vectors[0][0])-0.6) > 1e-6 || math.Abs(float64(vectors[0][1])-0.8) > 1e-6 {
+			t.Fatalf("unexpected truncated vector %v", vectors[0])
+		}
+	}
+
+	if _, err := WithOutputDimension(staticClient{vector: []float32{1}}, 2).GenerateEmbeddings([]string{"x"}); err == nil {
+		t.Fatalf("expected vectors shorter than the output dimension to be rejected")
+	}
+}
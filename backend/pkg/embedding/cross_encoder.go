@@ -0,0 +1,214 @@
+/*
+  File: cross_encoder.go
+  Purpose: ONNX cross-encoder used to rerank search candidates by (query, passage) relevance.
+  Author: CodeTextor project
+  Notes: Models are BERT-style sequence classifiers (ms-marco MiniLM, bge-reranker) exported to ONNX
+         with a single relevance logit (or two-class logits). Scores are mapped to 0-1.
+*/
+
+package embedding
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"CodeTextor/backend/pkg/models"
+
+	"github.com/sugarme/tokenizer"
+	"github.com/sugarme/tokenizer/pretrained"
+	onnx "github.com/yalue/onnxruntime_go"
+)
+
+// Reranker scores passages against a query; higher scores mean more relevant passages.
+type Reranker interface {
+	Score(query string, passages []string) ([]float32, error)
+	Close() error
+}
+
+// ONNXCrossEncoder scores (query, passage) pairs with an ONNX cross-encoder model.
+type ONNXCrossEncoder struct {
+	session          *onnx.DynamicAdvancedSession
+	tokenizer        *tokenizer.Tokenizer
+	padID            int
+	padTypeID        int
+	padToken         string
+	padDirection     tokenizer.PaddingDirection
+	maxSeqLen        int
+	inputNames       []string
+	outputNames      []string
+	expectTokenTypes bool
+	mu               sync.Mutex
+}
+
+// NewONNXCrossEncoder loads a downloaded reranker model and its tokenizer.
+func NewONNXCrossEncoder(meta *models.EmbeddingModelInfo) (*ONNXCrossEncoder, error) {
+	if meta == nil {
+		return nil, fmt.Errorf("reranker metadata is required")
+	}
+	if strings.TrimSpace(meta.LocalPath) == "" {
+		return nil, fmt.Errorf("reranker %s is missing a local ONNX path", meta.ID)
+	}
+	if strings.TrimSpace(meta.TokenizerLocalPath) == "" {
+		return nil, fmt.Errorf("reranker %s is missing a tokenizer.json path", meta.ID)
+	}
+	if err := ensureONNXRuntimeInitialized(); err != nil {
+		return nil, err
+	}
+
+	tk, err := pretrained.FromFile(meta.TokenizerLocalPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tokenizer for %s: %w", meta.ID, err)
+	}
+
+	maxSeq := meta.MaxSequenceLength
+	if maxSeq <= 0 {
+		maxSeq = 512
+	}
+	encoder := &ONNXCrossEncoder{
+		tokenizer:    tk,
+		padToken:     "[PAD]",
+		padDirection: tokenizer.Right,
+		maxSeqLen:    maxSeq,
+	}
+	if padParams := tk.GetPadding(); padParams != nil {
+		encoder.padID = padParams.PadId
+		encoder.padTypeID = padParams.PadTypeId
+		if padParams.PadToken != "" {
+			encoder.padToken = padParams.PadToken
+		}
+		encoder.padDirection = padParams.Direction
+	}
+
+	inputInfo, outputInfo, err := onnx.GetInputOutputInfo(meta.LocalPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect ONNX file %s: %w", meta.LocalPath, err)
+	}
+	if len(inputInfo) == 0 || len(outputInfo) == 0 {
+		return nil, fmt.Errorf("ONNX model %s is missing inputs or outputs", meta.LocalPath)
+	}
+	for _, info := range inputInfo {
+		encoder.inputNames = append(encoder.inputNames, info.Name)
+	}
+	for _, info := range outputInfo {
+		encoder.outputNames = append(encoder.outputNames, info.Name)
+	}
+	encoder.expectTokenTypes = hasTokenTypeInput(encoder.inputNames)
+
+	session, err := newONNXSessionWithOptionalCUDA(meta.LocalPath, encoder.inputNames, encoder.outputNames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ONNX session: %w", err)
+	}
+	encoder.session = session
+	return encoder, nil
+}
+
+// Score returns one relevance score in [0, 1] per passage, in input order.
+func (e *ONNXCrossEncoder) Score(query string, passages []string) ([]float32, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.session == nil {
+		return nil, errors.New("cross-encoder session is closed")
+	}
+
+	scores := make([]float32, len(passages))
+	for i, passage := range passages {
+		score, err := e.scorePair(query, passage)
+		if err != nil {
+			return nil, err
+		}
+		scores[i] = score
+	}
+	return scores, nil
+}
+
+// Close releases ONNX runtime resources.
+func (e *ONNXCrossEncoder) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.session != nil {
+		if err := e.session.Destroy(); err != nil {
+			return err
+		}
+		e.session = nil
+	}
+	return nil
+}
+
+func (e *ONNXCrossEncoder) scorePair(query, passage string) (float32, error) {
+	// Tokens are at least one character; trimming long passages up front keeps tokenization cheap.
+	if limit := e.maxSeqLen * 8; len(passage) > limit {
+		passage = strings.ToValidUTF8(passage[:limit], "")
+	}
+	encoding, err := e.tokenizer.EncodePair(query, passage, true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode query/passage pair: %w", err)
+	}
+	if encoding == nil {
+		return 0, errors.New("tokenizer returned nil encoding")
+	}
+	normalizeEncodingSlices(encoding, e.padTypeID, e.padToken)
+
+	if encoding.Len() > e.maxSeqLen {
+		truncated, err := encoding.Truncate(e.maxSeqLen, 0)
+		if err != nil {
+			return 0, fmt.Errorf("failed to truncate encoding: %w", err)
+		}
+		encoding = truncated
+	}
+	if encoding.Len() < e.maxSeqLen {
+		encoding = encoding.Pad(e.maxSeqLen, e.padID, e.padTypeID, e.padToken, e.padDirection)
+	}
+
+	ids := clampSlice(encoding.GetIds(), e.maxSeqLen)
+	attMask := clampSlice(encoding.GetAttentionMask(), e.maxSeqLen)
+	tokenTypeIDs := make([]int, e.maxSeqLen)
+	if e.expectTokenTypes {
+		copy(tokenTypeIDs, clampSlice(encoding.GetTypeIds(), e.maxSeqLen))
+	}
+
+	inputTensors, cleanupInputs, err := buildONNXInputTensors(e.inputNames, e.maxSeqLen, e.expectTokenTypes, ids, attMask, tokenTypeIDs)
+	if cleanupInputs != nil {
+		defer cleanupInputs()
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	outputValues := make([]onnx.Value, len(e.outputNames))
+	err = e.session.Run(inputTensors, outputValues)
+	defer func() {
+		for _, out := range outputValues {
+			if out != nil {
+				out.Destroy()
+			}
+		}
+	}()
+	if err != nil {
+		return 0, fmt.Errorf("failed to run ONNX session: %w", err)
+	}
+	if len(outputValues) == 0 {
+		return 0, fmt.Errorf("model returned no outputs")
+	}
+	tensor, ok := outputValues[0].(*onnx.Tensor[float32])
+	if !ok {
+		return 0, fmt.Errorf("unexpected output tensor type %T", outputValues[0])
+	}
+	return relevanceFromLogits(tensor.GetData())
+}
+
+// relevanceFromLogits maps classifier logits to a 0-1 relevance score: sigmoid for a single
+// logit, softmax probability of the positive (last) class for two-class heads.
+func relevanceFromLogits(logits []float32) (float32, error) {
+	switch len(logits) {
+	case 1:
+		return float32(1 / (1 + math.Exp(-float64(logits[0])))), nil
+	case 2:
+		diff := float64(logits[1] - logits[0])
+		return float32(1 / (1 + math.Exp(-diff))), nil
+	default:
+		return 0, fmt.Errorf("unexpected reranker output size %d", len(logits))
+	}
+}
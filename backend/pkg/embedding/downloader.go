@@ -3,12 +3,17 @@ package embedding
 import (
 	"archive/tar"
 	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"CodeTextor/backend/pkg/models"
 	"CodeTextor/backend/pkg/utils"
@@ -25,12 +30,37 @@ type DownloadProgress struct {
 // DownloadProgressCallback receives progress updates for a download.
 type DownloadProgressCallback func(DownloadProgress)
 
+const (
+	downloadDefaultMaxRetries = 3
+	downloadInitialBackoff    = 2 * time.Second
+	// downloadResponseTimeout bounds the wait for response headers and downloadIdleTimeout the
+	// wait for the next body bytes; a stalled server then fails the attempt, which is retried
+	// and resumed, instead of hanging the download forever. Large files have no overall limit.
+	downloadResponseTimeout = 30 * time.Second
+	downloadIdleTimeout     = 60 * time.Second
+	// partialDownloadSuffix marks files still being downloaded; they are resumed with HTTP Range
+	// requests and renamed into place once complete and verified.
+	partialDownloadSuffix = ".part"
+)
+
 // Downloader handles fetching embedding model files locally.
-type Downloader struct{}
+type Downloader struct {
+	client      *http.Client
+	maxRetries  int
+	backoff     time.Duration
+	idleTimeout time.Duration
+}
 
 // NewDownloader creates a new Downloader instance.
 func NewDownloader() *Downloader {
-	return &Downloader{}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = downloadResponseTimeout
+	return &Downloader{
+		client:      &http.Client{Transport: transport},
+		maxRetries:  downloadDefaultMaxRetries,
+		backoff:     downloadInitialBackoff,
+		idleTimeout: downloadIdleTimeout,
+	}
 }
 
 // EnsureLocal copies or downloads the model artifacts for the provided metadata.
@@ -69,15 +99,17 @@ func (d *Downloader) EnsureLocal(meta *models.EmbeddingModelInfo, progress Downl
 
 	onnxReady := false
 	stageModel := "model"
-	if fileExists(targetPath) {
+	if fileExists(targetPath) && VerifySHA256(targetPath, meta.SHA256) == nil {
 		onnxReady = true
 		meta.LocalPath = targetPath
 	} else if meta.SourceURI != "" {
-		if err := d.retrieve(meta, meta.SourceURI, targetPath, stageModel, progress); err != nil {
+		if err := d.retrieve(meta, meta.SourceURI, targetPath, stageModel, meta.SHA256, progress); err != nil {
 			return nil, err
 		}
 		meta.LocalPath = targetPath
 		onnxReady = true
+	} else if fileExists(targetPath) {
+		return nil, VerifySHA256(targetPath, meta.SHA256)
 	} else {
 		return nil, fmt.Errorf("model %s has no usable source path for ONNX file", meta.ID)
 	}
@@ -88,8 +120,8 @@ func (d *Downloader) EnsureLocal(meta *models.EmbeddingModelInfo, progress Downl
 		if tokenizerPath == "" {
 			tokenizerPath = filepath.Join(targetDir, "tokenizer.json")
 		}
-		if !fileExists(tokenizerPath) {
-			if err := d.retrieve(meta, meta.TokenizerURI, tokenizerPath, "tokenizer", progress); err != nil {
+		if !fileExists(tokenizerPath) || VerifySHA256(tokenizerPath, meta.TokenizerSHA256) != nil {
+			if err := d.retrieve(meta, meta.TokenizerURI, tokenizerPath, "tokenizer", meta.TokenizerSHA256, progress); err != nil {
 				return nil, fmt.Errorf("failed to download tokenizer for %s: %w", meta.ID, err)
 			}
 		}
@@ -108,13 +140,21 @@ func (d *Downloader) EnsureLocal(meta *models.EmbeddingModelInfo, progress Downl
 	return meta, nil
 }
 
-func (d *Downloader) retrieve(meta *models.EmbeddingModelInfo, source, destination, stage string, progress DownloadProgressCallback) error {
+func (d *Downloader) retrieve(meta *models.EmbeddingModelInfo, source, destination, stage, expectedSHA256 string, progress DownloadProgressCallback) error {
 	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
-		return downloadFileWithProgress(meta.ID, source, destination, stage, progress)
+		return d.downloadFileWithProgress(meta.ID, source, destination, stage, expectedSHA256, progress)
 	}
 	// Treat as local file path
-	return copyFileWithProgress(meta.ID, source, destination, stage, progress)
+	if err := copyFileWithProgress(meta.ID, source, destination, stage, progress); err != nil {
+		return err
+	}
+	if err := VerifySHA256(destination, expectedSHA256); err != nil {
+		_ = os.Remove(destination)
+		return err
+	}
+	return nil
 }
+
 func fileExists(path string) bool {
 	info, err := os.Stat(path)
 	if err != nil {
@@ -123,31 +163,100 @@ func fileExists(path string) bool {
 	return !info.IsDir()
 }
 
-func downloadFileWithProgress(modelID, url, destination, stage string, progress DownloadProgressCallback) error {
-	resp, err := http.Get(url) // #nosec G107 -- user-provided URL expected
+// downloadFileWithProgress downloads url into destination through a ".part" file. Interrupted
+// transfers and 5xx/408/429 responses are retried up to d.maxRetries times with exponential
+// backoff, resuming from the bytes already on disk. The finished file must match
+// expectedSHA256 (when set) before it is moved into place.
+func (d *Downloader) downloadFileWithProgress(modelID, url, destination, stage, expectedSHA256 string, progress DownloadProgressCallback) error {
+	partPath := destination + partialDownloadSuffix
+	err := retryHTTP(d.maxRetries, d.backoff, func() (bool, error) {
+		resumed, retryable, err := d.fetchPart(modelID, url, partPath, stage, progress)
+		if err != nil {
+			return retryable, err
+		}
+		if err := VerifySHA256(partPath, expectedSHA256); err != nil {
+			_ = os.Remove(partPath)
+			// A stale partial file may belong to an older artifact, so a resumed download
+			// that fails verification is worth one more attempt from scratch.
+			return resumed, err
+		}
+		return false, nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to download %s: %w", url, err)
+		return err
 	}
-	defer resp.Body.Close()
+	if err := os.Rename(partPath, destination); err != nil {
+		return fmt.Errorf("failed to move %s into place: %w", destination, err)
+	}
+	return nil
+}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("failed to download %s: status %s", url, resp.Status)
+// fetchPart downloads url into partPath, resuming after the bytes already present. It reports
+// whether the transfer resumed a previous partial file and whether a failure is retryable.
+func (d *Downloader) fetchPart(modelID, url, partPath, stage string, progress DownloadProgressCallback) (bool, bool, error) {
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
 	}
 
-	out, err := os.Create(destination)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil) // #nosec G107 -- user-provided URL expected
 	if err != nil {
-		return fmt.Errorf("failed to create %s: %w", destination, err)
+		return false, false, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return false, true, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	resumed := false
+	flags := os.O_CREATE | os.O_WRONLY
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		if start := contentRangeStart(resp.Header.Get("Content-Range")); start != offset {
+			_ = os.Remove(partPath)
+			return false, true, fmt.Errorf("server resumed %s at byte %d instead of %d", url, start, offset)
+		}
+		flags |= os.O_APPEND
+		resumed = true
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// Nothing left to fetch: the partial file already holds the whole artifact.
+		return true, false, nil
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		// The server ignored the Range header (or there was nothing to resume).
+		flags |= os.O_TRUNC
+		offset = 0
+	default:
+		retryable := resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return false, retryable, fmt.Errorf("failed to download %s: status %s", url, resp.Status)
+	}
+
+	out, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return resumed, false, fmt.Errorf("failed to create %s: %w", partPath, err)
 	}
 	defer out.Close()
 
-	total := resp.ContentLength
-	var downloaded int64
+	total := int64(-1)
+	if resp.ContentLength >= 0 {
+		total = offset + resp.ContentLength
+	}
+	downloaded := offset
 	buf := make([]byte, 128*1024)
+	// Cancelling the request unblocks a read that waits longer than idleTimeout.
+	idle := time.AfterFunc(d.idleTimeout, cancel)
+	defer idle.Stop()
 	for {
+		idle.Reset(d.idleTimeout)
 		n, readErr := resp.Body.Read(buf)
 		if n > 0 {
 			if _, writeErr := out.Write(buf[:n]); writeErr != nil {
-				return writeErr
+				return resumed, false, writeErr
 			}
 			downloaded += int64(n)
 			reportProgress(progress, modelID, stage, downloaded, total)
@@ -156,13 +265,63 @@ func downloadFileWithProgress(modelID, url, destination, stage string, progress
 			if readErr == io.EOF {
 				break
 			}
-			return readErr
+			// Keep what arrived; the next attempt resumes from here.
+			if ctx.Err() != nil {
+				return resumed, true, fmt.Errorf("download of %s stalled for %s after %d bytes", url, d.idleTimeout, downloaded)
+			}
+			return resumed, true, fmt.Errorf("download of %s interrupted after %d bytes: %w", url, downloaded, readErr)
 		}
 	}
-	reportProgress(progress, modelID, stage, total, total)
+	reportProgress(progress, modelID, stage, downloaded, total)
+	return resumed, false, nil
+}
+
+// contentRangeStart parses the first byte position of a "bytes start-end/size" header.
+func contentRangeStart(header string) int64 {
+	spec, ok := strings.CutPrefix(strings.TrimSpace(header), "bytes ")
+	if !ok {
+		return -1
+	}
+	first, _, _ := strings.Cut(spec, "-")
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return start
+}
+
+// VerifySHA256 checks that the file at path has the given hex-encoded SHA-256 digest.
+// An empty expected digest skips the check.
+func VerifySHA256(path, expected string) error {
+	expected = strings.ToLower(strings.TrimSpace(expected))
+	if expected == "" {
+		return nil
+	}
+	actual, err := FileSHA256(path)
+	if err != nil {
+		return err
+	}
+	if actual != expected {
+		return fmt.Errorf("checksum mismatch for %s: expected SHA-256 %s, got %s", filepath.Base(path), expected, actual)
+	}
 	return nil
 }
 
+// FileSHA256 returns the hex-encoded SHA-256 digest of the file at path.
+func FileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s for verification: %w", path, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
 func copyFileWithProgress(modelID, source, destination, stage string, progress DownloadProgressCallback) error {
 	in, err := os.Open(source)
 	if err != nil {
@@ -221,10 +380,10 @@ func (d *Downloader) ensureFastEmbedModel(meta *models.EmbeddingModelInfo, progr
 	if err := os.MkdirAll(cacheDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create fastembed cache dir: %w", err)
 	}
-	errDownload := downloadFastEmbedArchive(meta.ID, string(modelID), cacheDir, progress)
+	errDownload := d.downloadFastEmbedArchive(meta.ID, string(modelID), cacheDir, progress)
 	if errDownload != nil {
 		if base := fastEmbedHuggingFaceBase(meta); base != "" {
-			if err := downloadFastEmbedFromHuggingFace(meta.ID, base, targetDir, progress); err != nil {
+			if err := d.downloadFastEmbedFromHuggingFace(meta.ID, base, targetDir, progress); err != nil {
 				return nil, fmt.Errorf("failed to download %s: %v (fallback failed: %v)", meta.ID, errDownload, err)
 			}
 		} else {
@@ -236,7 +395,7 @@ func (d *Downloader) ensureFastEmbedModel(meta *models.EmbeddingModelInfo, progr
 	return meta, nil
 }
 
-func downloadFastEmbedArchive(modelID, model string, cacheDir string, progress DownloadProgressCallback) error {
+func (d *Downloader) downloadFastEmbedArchive(modelID, model string, cacheDir string, progress DownloadProgressCallback) error {
 	url := fmt.Sprintf("https://storage.googleapis.com/qdrant-fastembed/%s.tar.gz", model)
 	tempFile, err := os.CreateTemp(cacheDir, fmt.Sprintf("%s-*.tar.gz", model))
 	if err != nil {
@@ -244,7 +403,7 @@ func downloadFastEmbedArchive(modelID, model string, cacheDir string, progress D
 	}
 	tempPath := tempFile.Name()
 	tempFile.Close()
-	if err := downloadFileWithProgress(modelID, url, tempPath, "fastembed:model", progress); err != nil {
+	if err := d.downloadFileWithProgress(modelID, url, tempPath, "fastembed:model", "", progress); err != nil {
 		_ = os.Remove(tempPath)
 		return err
 	}
@@ -283,6 +442,9 @@ func untarArchive(r io.Reader, target string) error {
 		}
 
 		path := filepath.Join(target, header.Name)
+		if path != filepath.Clean(target) && !strings.HasPrefix(path, filepath.Clean(target)+string(os.PathSeparator)) {
+			return fmt.Errorf("archive entry %s escapes the target directory", header.Name)
+		}
 		switch header.Typeflag {
 		case tar.TypeDir:
 			if err := os.MkdirAll(path, 0755); err != nil {
@@ -306,7 +468,7 @@ func untarArchive(r io.Reader, target string) error {
 	return nil
 }
 
-func downloadFastEmbedFromHuggingFace(modelID, base, targetDir string, progress DownloadProgressCallback) error {
+func (d *Downloader) downloadFastEmbedFromHuggingFace(modelID, base, targetDir string, progress DownloadProgressCallback) error {
 	files := map[string]string{
 		"config.json":               "config.json",
 		"tokenizer.json":            "tokenizer.json",
@@ -325,7 +487,7 @@ func downloadFastEmbedFromHuggingFace(modelID, base, targetDir string, progress
 	for local, remote := range files {
 		url := fmt.Sprintf("%s/%s", base, remote)
 		stage := fmt.Sprintf("fastembed:%s", local)
-		if err := downloadFileWithProgress(modelID, url, filepath.Join(targetDir, local), stage, progress); err != nil {
+		if err := d.downloadFileWithProgress(modelID, url, filepath.Join(targetDir, local), stage, "", progress); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,198 @@
+package embedding
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func newTestDownloader() *Downloader {
+	d := NewDownloader()
+	d.backoff = 0
+	return d
+}
+
+func TestDownloaderResumesPartialFile(t *testing.T) {
+	payload := bytes.Repeat([]byte("0123456789abcdef"), 4096)
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "model.onnx", time.Time{}, bytes.NewReader(payload))
+	}))
+	defer server.Close()
+
+	dest := filepath.Join(t.TempDir(), "model.onnx")
+	if err := os.WriteFile(dest+partialDownloadSuffix, payload[:1000], 0644); err != nil {
+		t.Fatalf("write partial: %v", err)
+	}
+
+	var last DownloadProgress
+	err := newTestDownloader().downloadFileWithProgress("m", server.URL+"/model.onnx", dest, "model", sha256Hex(payload), func(p DownloadProgress) {
+		last = p
+	})
+	if err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	if len(ranges) != 1 || ranges[0] != "bytes=1000-" {
+		t.Fatalf("expected a single resumed request, got ranges %q", ranges)
+	}
+	got, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatalf("read result: %v", err)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Fatalf("downloaded file differs from payload (%d vs %d bytes)", len(got), len(payload))
+	}
+	if fileExists(dest + partialDownloadSuffix) {
+		t.Fatalf("partial file should be renamed into place")
+	}
+	if last.Total != int64(len(payload)) || last.Downloaded != int64(len(payload)) {
+		t.Fatalf("unexpected final progress %+v", last)
+	}
+}
+
+func TestDownloaderRetriesServerErrors(t *testing.T) {
+	payload := []byte("tokenizer contents")
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= 2 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write(payload)
+	}))
+	defer server.Close()
+
+	dest := filepath.Join(t.TempDir(), "tokenizer.json")
+	if err := newTestDownloader().downloadFileWithProgress("m", server.URL, dest, "tokenizer", "", nil); err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls.Load())
+	}
+	if got, _ := os.ReadFile(dest); !bytes.Equal(got, payload) {
+		t.Fatalf("unexpected contents %q", got)
+	}
+}
+
+func TestDownloaderDoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	dest := filepath.Join(t.TempDir(), "model.onnx")
+	if err := newTestDownloader().downloadFileWithProgress("m", server.URL, dest, "model", "", nil); err == nil {
+		t.Fatalf("expected 404 to fail")
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected a single attempt, got %d", calls.Load())
+	}
+}
+
+func TestDownloaderRejectsChecksumMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("corrupted"))
+	}))
+	defer server.Close()
+
+	dest := filepath.Join(t.TempDir(), "model.onnx")
+	err := newTestDownloader().downloadFileWithProgress("m", server.URL, dest, "model", sha256Hex([]byte("expected")), nil)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+	if fileExists(dest) || fileExists(dest+partialDownloadSuffix) {
+		t.Fatalf("corrupted download must not be kept")
+	}
+}
+
+func TestDownloaderRestartsStalePartialFile(t *testing.T) {
+	payload := []byte("fresh model bytes, all of them")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "model.onnx", time.Time{}, bytes.NewReader(payload))
+	}))
+	defer server.Close()
+
+	dest := filepath.Join(t.TempDir(), "model.onnx")
+	// The partial file holds bytes of an older artifact, so the resumed result fails
+	// verification and the download has to start over.
+	if err := os.WriteFile(dest+partialDownloadSuffix, []byte("stale!"), 0644); err != nil {
+		t.Fatalf("write partial: %v", err)
+	}
+	if err := newTestDownloader().downloadFileWithProgress("m", server.URL, dest, "model", sha256Hex(payload), nil); err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	if got, _ := os.ReadFile(dest); !bytes.Equal(got, payload) {
+		t.Fatalf("unexpected contents %q", got)
+	}
+}
+
+func TestDownloaderRetriesStalledResponses(t *testing.T) {
+	payload := bytes.Repeat([]byte("stalled model bytes "), 512)
+	var calls atomic.Int32
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		switch calls.Add(1) {
+		case 1:
+			// No response headers until the client gives up.
+			<-r.Context().Done()
+		case 2:
+			// Half of the body, then silence.
+			w.Header().Set("Content-Length", strconv.Itoa(len(payload)))
+			_, _ = w.Write(payload[:len(payload)/2])
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		default:
+			http.ServeContent(w, r, "model.onnx", time.Time{}, bytes.NewReader(payload))
+		}
+	}))
+	defer server.Close()
+
+	d := newTestDownloader()
+	d.client.Transport.(*http.Transport).ResponseHeaderTimeout = 100 * time.Millisecond
+	d.idleTimeout = 100 * time.Millisecond
+	dest := filepath.Join(t.TempDir(), "model.onnx")
+	if err := d.downloadFileWithProgress("m", server.URL, dest, "model", sha256Hex(payload), nil); err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	if len(ranges) != 3 || ranges[2] != fmt.Sprintf("bytes=%d-", len(payload)/2) {
+		t.Fatalf("expected two stalled attempts and a resumed one, got ranges %q", ranges)
+	}
+	if got, _ := os.ReadFile(dest); !bytes.Equal(got, payload) {
+		t.Fatalf("downloaded file differs from payload (%d vs %d bytes)", len(got), len(payload))
+	}
+}
+
+func TestVerifySHA256(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(path, []byte("abc"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := VerifySHA256(path, ""); err != nil {
+		t.Fatalf("empty digest should skip verification: %v", err)
+	}
+	if err := VerifySHA256(path, strings.ToUpper(sha256Hex([]byte("abc")))); err != nil {
+		t.Fatalf("matching digest rejected: %v", err)
+	}
+	if err := VerifySHA256(path, sha256Hex([]byte("abd"))); err == nil {
+		t.Fatalf("expected mismatch")
+	}
+}
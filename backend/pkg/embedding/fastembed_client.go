@@ -87,6 +87,23 @@ func (c *FastEmbedClient) GenerateEmbeddings(texts []string) ([][]float32, error
 	return result, nil
 }
 
+// GenerateQueryEmbeddings uses fastembed's query embedding, which applies the model's own
+// query instruction.
+func (c *FastEmbedClient) GenerateQueryEmbeddings(texts []string) ([][]float32, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := make([][]float32, len(texts))
+	for i, text := range texts {
+		vec, err := c.model.QueryEmbed(text)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = vec
+	}
+	return result, nil
+}
+
 // Close releases any resources held by the fastembed runtime.
 func (c *FastEmbedClient) Close() error {
 	c.mu.Lock()
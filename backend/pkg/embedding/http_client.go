@@ -0,0 +1,91 @@
+package embedding
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// httpInitialBackoff is the first retry delay for HTTP embedding backends; it doubles per attempt.
+const httpInitialBackoff = 500 * time.Millisecond
+
+// retryHTTP runs attempt until it succeeds, reports a non-retryable error, or maxRetries
+// retries are used up.
+func retryHTTP(maxRetries int, backoff time.Duration, attempt func() (bool, error)) error {
+	delay := backoff
+	var lastErr error
+	for i := 0; i <= maxRetries; i++ {
+		if i > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+		retryable, err := attempt()
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retryable {
+			break
+		}
+	}
+	return lastErr
+}
+
+// postJSON sends payload and decodes a 200 response into out. Network errors, 429 and 5xx are
+// reported as retryable; errorMessage extracts a server-specific message from error bodies.
+func postJSON(client *http.Client, endpoint, apiKey string, payload, out any, errorMessage func([]byte) string) (bool, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode embedding request: %w", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to build embedding request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("embedding request to %s failed: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return true, fmt.Errorf("failed to read embedding response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		message := strings.TrimSpace(string(respBody))
+		if errorMessage != nil {
+			if parsed := errorMessage(respBody); parsed != "" {
+				message = parsed
+			}
+		}
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retryable, fmt.Errorf("embedding server returned %s: %s", resp.Status, message)
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return false, fmt.Errorf("invalid embedding response: %w", err)
+	}
+	return false, nil
+}
+
+// checkDimension rejects vectors whose length differs from the configured model dimension.
+func checkDimension(vectors [][]float32, dimension int, model string) error {
+	if dimension <= 0 {
+		return nil
+	}
+	for _, vec := range vectors {
+		if len(vec) != dimension {
+			return fmt.Errorf("embedding server returned %d dimensions, model %s expects %d", len(vec), model, dimension)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package embedding
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sugarme/tokenizer/pretrained"
+)
+
+// importMaxSequenceLength caps the sequence length read from imported configs; the ONNX client
+// pads every input to this length, so long-context values would slow down indexing.
+const importMaxSequenceLength = 512
+
+// LocalModelFiles describes the artifacts found in an offline model import.
+type LocalModelFiles struct {
+	ModelPath     string
+	TokenizerPath string
+	// FastEmbed is true when the files come from a fastembed archive (model_optimized.onnx).
+	FastEmbed bool
+	// MaxSequenceLength is read from tokenizer_config.json or config.json (0 when unknown).
+	MaxSequenceLength int
+}
+
+// IsModelArchive reports whether path names a gzipped tarball that can be imported.
+func IsModelArchive(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz")
+}
+
+// ImportLocalModel copies an ONNX model and its tokenizer.json from a directory or a .tar.gz
+// archive into targetDir as model.onnx and tokenizer.json. Archives are extracted into a
+// temporary directory next to targetDir first.
+func (d *Downloader) ImportLocalModel(modelID, source, targetDir string, progress DownloadProgressCallback) (*LocalModelFiles, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import source: %w", err)
+	}
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create model directory: %w", err)
+	}
+
+	root := source
+	if !info.IsDir() {
+		if !IsModelArchive(source) {
+			return nil, fmt.Errorf("%s is neither a directory nor a .tar.gz archive", source)
+		}
+		staging, err := os.MkdirTemp(filepath.Dir(targetDir), ".import-*")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create staging directory: %w", err)
+		}
+		defer os.RemoveAll(staging)
+		archive, err := os.Open(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", source, err)
+		}
+		err = untarArchive(archive, staging)
+		archive.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract %s: %w", source, err)
+		}
+		root = staging
+	}
+
+	found, err := DetectModelFiles(root)
+	if err != nil {
+		return nil, err
+	}
+
+	imported := *found
+	imported.ModelPath = filepath.Join(targetDir, "model.onnx")
+	imported.TokenizerPath = filepath.Join(targetDir, "tokenizer.json")
+	if err := copyIfDifferent(modelID, found.ModelPath, imported.ModelPath, "import:model", progress); err != nil {
+		return nil, err
+	}
+	if err := copyIfDifferent(modelID, found.TokenizerPath, imported.TokenizerPath, "import:tokenizer", progress); err != nil {
+		return nil, err
+	}
+	return &imported, nil
+}
+
+func copyIfDifferent(modelID, source, destination, stage string, progress DownloadProgressCallback) error {
+	if filepath.Clean(source) == filepath.Clean(destination) {
+		return nil
+	}
+	return copyFileWithProgress(modelID, source, destination, stage, progress)
+}
+
+// DetectModelFiles locates the ONNX model and tokenizer.json inside dir. It understands plain
+// exports (model.onnx + tokenizer.json), Hugging Face repositories (onnx/model.onnx) and
+// fastembed archives, which wrap model_optimized.onnx in a single top-level directory.
+func DetectModelFiles(dir string) (*LocalModelFiles, error) {
+	dir = descendSingleDirectory(dir)
+
+	modelPath, err := findONNXModel(dir)
+	if err != nil {
+		return nil, err
+	}
+	tokenizerPath := filepath.Join(dir, "tokenizer.json")
+	if !fileExists(tokenizerPath) {
+		return nil, fmt.Errorf("no tokenizer.json found in %s", dir)
+	}
+	return &LocalModelFiles{
+		ModelPath:         modelPath,
+		TokenizerPath:     tokenizerPath,
+		FastEmbed:         filepath.Base(modelPath) == "model_optimized.onnx",
+		MaxSequenceLength: readMaxSequenceLength(dir),
+	}, nil
+}
+
+// descendSingleDirectory follows directories that contain nothing but one subdirectory, the way
+// archives usually wrap their files.
+func descendSingleDirectory(dir string) string {
+	for {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return dir
+		}
+		var only string
+		count := 0
+		for _, entry := range entries {
+			name := entry.Name()
+			if strings.HasPrefix(name, ".") || name == "__MACOSX" {
+				continue
+			}
+			count++
+			if entry.IsDir() {
+				only = name
+			}
+		}
+		if count != 1 || only == "" {
+			return dir
+		}
+		dir = filepath.Join(dir, only)
+	}
+}
+
+func findONNXModel(dir string) (string, error) {
+	for _, name := range []string{"model.onnx", "model_optimized.onnx", filepath.Join("onnx", "model.onnx")} {
+		if candidate := filepath.Join(dir, name); fileExists(candidate) {
+			return candidate, nil
+		}
+	}
+	var candidates []string
+	for _, sub := range []string{dir, filepath.Join(dir, "onnx")} {
+		matches, _ := filepath.Glob(filepath.Join(sub, "*.onnx"))
+		candidates = append(candidates, matches...)
+	}
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("no .onnx model found in %s", dir)
+	case 1:
+		return candidates[0], nil
+	default:
+		names := make([]string, len(candidates))
+		for i, candidate := range candidates {
+			names[i], _ = filepath.Rel(dir, candidate)
+		}
+		sort.Strings(names)
+		return "", fmt.Errorf("found several ONNX files in %s (%s); rename the one to import to model.onnx", dir, strings.Join(names, ", "))
+	}
+}
+
+// readMaxSequenceLength returns the model's context length from tokenizer_config.json
+// (model_max_length) or config.json (max_position_embeddings), capped at importMaxSequenceLength.
+func readMaxSequenceLength(dir string) int {
+	lookups := []struct {
+		file string
+		key  string
+	}{
+		{"tokenizer_config.json", "model_max_length"},
+		{"config.json", "max_position_embeddings"},
+	}
+	for _, lookup := range lookups {
+		data, err := os.ReadFile(filepath.Join(dir, lookup.file))
+		if err != nil {
+			continue
+		}
+		var values map[string]any
+		if err := json.Unmarshal(data, &values); err != nil {
+			continue
+		}
+		if value, ok := values[lookup.key].(float64); ok && value > 0 {
+			if value > importMaxSequenceLength {
+				return importMaxSequenceLength
+			}
+			return int(value)
+		}
+	}
+	return 0
+}
+
+// VerifyTokenizer loads a tokenizer.json and encodes a sample so broken or unsupported
+// tokenizers are rejected before a model is registered.
+func VerifyTokenizer(path string) error {
+	tk, err := pretrained.FromFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to load tokenizer %s: %w", path, err)
+	}
+	encoding, err := tk.EncodeSingle("func main() { return }", true)
+	if err != nil {
+		return fmt.Errorf("tokenizer %s failed to encode a sample: %w", path, err)
+	}
+	if encoding == nil || len(encoding.GetIds()) == 0 {
+		return fmt.Errorf("tokenizer %s produced no tokens for a sample", path)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package embedding
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+}
+
+func writeTarGz(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	out, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create archive: %v", err)
+	}
+	defer out.Close()
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("tar header: %v", err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatalf("tar write: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("tar close: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("gzip close: %v", err)
+	}
+}
+
+func TestDetectModelFilesLayouts(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string
+		model     string
+		fastEmbed bool
+		maxSeq    int
+	}{
+		{
+			name:   "plain export",
+			files:  map[string]string{"model.onnx": "m", "tokenizer.json": "{}", "config.json": `{"max_position_embeddings": 256}`},
+			model:  "model.onnx",
+			maxSeq: 256,
+		},
+		{
+			name:  "hugging face repository",
+			files: map[string]string{"onnx/model.onnx": "m", "onnx/model_quantized.onnx": "q", "tokenizer.json": "{}"},
+			model: filepath.Join("onnx", "model.onnx"),
+		},
+		{
+			name: "fastembed archive",
+			files: map[string]string{
+				"fast-bge-small-en-v1.5/model_optimized.onnx":  "m",
+				"fast-bge-small-en-v1.5/tokenizer.json":        "{}",
+				"fast-bge-small-en-v1.5/tokenizer_config.json": `{"model_max_length": 1000000000000000019884624838656}`,
+			},
+			model:     filepath.Join("fast-bge-small-en-v1.5", "model_optimized.onnx"),
+			fastEmbed: true,
+			maxSeq:    importMaxSequenceLength,
+		},
+		{
+			name:  "single custom name",
+			files: map[string]string{"encoder.onnx": "m", "tokenizer.json": "{}"},
+			model: "encoder.onnx",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFiles(t, root, tt.files)
+			found, err := DetectModelFiles(root)
+			if err != nil {
+				t.Fatalf("detect: %v", err)
+			}
+			if found.ModelPath != filepath.Join(root, tt.model) {
+				t.Fatalf("model path %s, want %s", found.ModelPath, tt.model)
+			}
+			if filepath.Base(found.TokenizerPath) != "tokenizer.json" {
+				t.Fatalf("unexpected tokenizer path %s", found.TokenizerPath)
+			}
+			if found.FastEmbed != tt.fastEmbed || found.MaxSequenceLength != tt.maxSeq {
+				t.Fatalf("got fastEmbed=%v maxSeq=%d", found.FastEmbed, found.MaxSequenceLength)
+			}
+		})
+	}
+}
+
+func TestDetectModelFilesErrors(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"model.onnx": "m"})
+	if _, err := DetectModelFiles(root); err == nil || !strings.Contains(err.Error(), "tokenizer.json") {
+		t.Fatalf("expected missing tokenizer error, got %v", err)
+	}
+
+	root = t.TempDir()
+	writeFiles(t, root, map[string]string{"a.onnx": "a", "b.onnx": "b", "tokenizer.json": "{}"})
+	if _, err := DetectModelFiles(root); err == nil || !strings.Contains(err.Error(), "a.onnx, b.onnx") {
+		t.Fatalf("expected ambiguous model error, got %v", err)
+	}
+}
+
+func TestImportLocalModelFromArchive(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "fast-bge-small-en-v1.5.tar.gz")
+	writeTarGz(t, archive, map[string]string{
+		"fast-bge-small-en-v1.5/model_optimized.onnx": "onnx bytes",
+		"fast-bge-small-en-v1.5/tokenizer.json":       "{\"tokenizer\": true}",
+	})
+	targetDir := filepath.Join(t.TempDir(), "local-bge")
+
+	files, err := NewDownloader().ImportLocalModel("local/bge", archive, targetDir, nil)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if !files.FastEmbed {
+		t.Fatalf("expected fastembed layout to be detected")
+	}
+	if got, _ := os.ReadFile(files.ModelPath); string(got) != "onnx bytes" || files.ModelPath != filepath.Join(targetDir, "model.onnx") {
+		t.Fatalf("model not copied into place: %s %q", files.ModelPath, got)
+	}
+	if got, _ := os.ReadFile(files.TokenizerPath); string(got) != "{\"tokenizer\": true}" {
+		t.Fatalf("tokenizer not copied: %q", got)
+	}
+	leftovers, _ := filepath.Glob(filepath.Join(filepath.Dir(targetDir), ".import-*"))
+	if len(leftovers) != 0 {
+		t.Fatalf("staging directory not removed: %v", leftovers)
+	}
+}
+
+func TestImportLocalModelRejectsEscapingArchive(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "evil.tgz")
+	writeTarGz(t, archive, map[string]string{"../outside.onnx": "x"})
+	_, err := NewDownloader().ImportLocalModel("local/evil", archive, filepath.Join(t.TempDir(), "evil"), nil)
+	if err == nil || !strings.Contains(err.Error(), "escapes") {
+		t.Fatalf("expected path traversal to be rejected, got %v", err)
+	}
+}
+
+func TestVerifyTokenizerRejectsInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokenizer.json")
+	if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := VerifyTokenizer(path); err == nil {
+		t.Fatalf("expected invalid tokenizer to be rejected")
+	}
+}
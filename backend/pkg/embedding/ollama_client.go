@@ -0,0 +1,201 @@
+package embedding
+
+import (
+	"CodeTextor/backend/pkg/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	ollamaDefaultBaseURL   = "http://localhost:11434"
+	ollamaDefaultBatchSize = 32
+	ollamaDiscoveryTimeout = 2 * time.Second
+	// ollamaDefaultTimeout is generous because the first call loads the model into memory.
+	ollamaDefaultTimeout = 120 * time.Second
+)
+
+// OllamaEmbeddingClient calls a local Ollama server's /api/embed endpoint.
+type OllamaEmbeddingClient struct {
+	endpoint   string
+	model      string
+	dimension  int
+	batchSize  int
+	maxRetries int
+	backoff    time.Duration
+	httpClient *http.Client
+}
+
+// OllamaModel is a locally pulled model reported by /api/tags.
+type OllamaModel struct {
+	Name    string `json:"name"`
+	Size    int64  `json:"size"`
+	Details struct {
+		Family        string   `json:"family"`
+		Families      []string `json:"families"`
+		ParameterSize string   `json:"parameter_size"`
+	} `json:"details"`
+}
+
+type ollamaEmbedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type ollamaEmbedResponse struct {
+	Embeddings [][]float32 `json:"embeddings"`
+}
+
+// DefaultOllamaBaseURL honours OLLAMA_HOST (as the Ollama CLI does) and falls back to localhost:11434.
+func DefaultOllamaBaseURL() string {
+	host := strings.TrimSpace(os.Getenv("OLLAMA_HOST"))
+	if host == "" {
+		return ollamaDefaultBaseURL
+	}
+	if !strings.HasPrefix(host, "http://") && !strings.HasPrefix(host, "https://") {
+		host = "http://" + host
+	}
+	return strings.TrimRight(host, "/")
+}
+
+func ollamaBaseURL(meta *models.EmbeddingModelInfo) string {
+	if meta != nil && strings.TrimSpace(meta.BaseURL) != "" {
+		return strings.TrimRight(strings.TrimSpace(meta.BaseURL), "/")
+	}
+	return DefaultOllamaBaseURL()
+}
+
+// NewOllamaEmbeddingClient builds a client for meta.RemoteModel (or the id without its
+// "ollama/" prefix). A zero Dimension skips the dimension check; see ProbeDimension.
+func NewOllamaEmbeddingClient(meta *models.EmbeddingModelInfo) (*OllamaEmbeddingClient, error) {
+	if meta == nil {
+		return nil, fmt.Errorf("embedding model metadata is required")
+	}
+	model := strings.TrimSpace(meta.RemoteModel)
+	if model == "" {
+		model = strings.TrimPrefix(meta.ID, "ollama/")
+	}
+	if model == "" {
+		return nil, fmt.Errorf("ollama model name is required")
+	}
+
+	client := &OllamaEmbeddingClient{
+		endpoint:   ollamaBaseURL(meta) + "/api/embed",
+		model:      model,
+		dimension:  meta.Dimension,
+		batchSize:  meta.BatchSize,
+		maxRetries: meta.MaxRetries,
+		backoff:    httpInitialBackoff,
+		httpClient: &http.Client{Timeout: ollamaDefaultTimeout},
+	}
+	if client.batchSize <= 0 {
+		client.batchSize = ollamaDefaultBatchSize
+	}
+	if client.maxRetries <= 0 {
+		client.maxRetries = openAIDefaultMaxRetries
+	}
+	if meta.TimeoutSeconds > 0 {
+		client.httpClient.Timeout = time.Duration(meta.TimeoutSeconds) * time.Second
+	}
+	return client, nil
+}
+
+// GenerateEmbeddings embeds texts in batches, preserving input order.
+func (c *OllamaEmbeddingClient) GenerateEmbeddings(texts []string) ([][]float32, error) {
+	results := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += c.batchSize {
+		end := min(start+c.batchSize, len(texts))
+		batch := texts[start:end]
+		var vectors [][]float32
+		err := retryHTTP(c.maxRetries, c.backoff, func() (bool, error) {
+			var parsed ollamaEmbedResponse
+			retryable, err := postJSON(c.httpClient, c.endpoint, "", ollamaEmbedRequest{Model: c.model, Input: batch}, &parsed, ollamaErrorMessage)
+			vectors = parsed.Embeddings
+			return retryable, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(vectors) != len(batch) {
+			return nil, fmt.Errorf("ollama returned %d vectors for %d inputs", len(vectors), len(batch))
+		}
+		if err := checkDimension(vectors, c.dimension, c.model); err != nil {
+			return nil, err
+		}
+		results = append(results, vectors...)
+	}
+	return results, nil
+}
+
+// GenerateQueryEmbeddings embeds queries like documents; instruction prefixes are added by
+// WithInstructionPrefixes.
+func (c *OllamaEmbeddingClient) GenerateQueryEmbeddings(texts []string) ([][]float32, error) {
+	return c.GenerateEmbeddings(texts)
+}
+
+// ProbeDimension embeds a short text once and returns the vector length.
+func (c *OllamaEmbeddingClient) ProbeDimension() (int, error) {
+	vectors, err := c.GenerateEmbeddings([]string{"dimension probe"})
+	if err != nil {
+		return 0, fmt.Errorf("failed to probe %s: %w", c.model, err)
+	}
+	if len(vectors) == 0 || len(vectors[0]) == 0 {
+		return 0, fmt.Errorf("ollama returned an empty embedding for %s", c.model)
+	}
+	return len(vectors[0]), nil
+}
+
+// Close releases idle HTTP connections; the client holds no other resources.
+func (c *OllamaEmbeddingClient) Close() error {
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
+
+func ollamaErrorMessage(body []byte) string {
+	var failure struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &failure) == nil {
+		return failure.Error
+	}
+	return ""
+}
+
+// ListOllamaModels returns the models pulled into the Ollama server at baseURL. The short
+// timeout keeps catalog listing fast when Ollama is not running.
+func ListOllamaModels(baseURL string) ([]OllamaModel, error) {
+	client := &http.Client{Timeout: ollamaDiscoveryTimeout}
+	resp, err := client.Get(strings.TrimRight(baseURL, "/") + "/api/tags")
+	if err != nil {
+		return nil, fmt.Errorf("ollama is not reachable at %s: %w", baseURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ollama returned %s for /api/tags", resp.Status)
+	}
+	var parsed struct {
+		Models []OllamaModel `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("invalid /api/tags response: %w", err)
+	}
+	return parsed.Models, nil
+}
+
+// IsEmbeddingModel guesses from the tag metadata whether the model produces embeddings:
+// embedding models are BERT-family encoders or carry "embed" in their name.
+func (m OllamaModel) IsEmbeddingModel() bool {
+	if strings.Contains(strings.ToLower(m.Name), "embed") {
+		return true
+	}
+	families := append([]string{m.Details.Family}, m.Details.Families...)
+	for _, family := range families {
+		if strings.Contains(strings.ToLower(family), "bert") {
+			return true
+		}
+	}
+	return false
+}
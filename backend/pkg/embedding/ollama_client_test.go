@@ -0,0 +1,106 @@
+package embedding
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"CodeTextor/backend/pkg/models"
+)
+
+func newOllamaStandIn(t *testing.T, dimension int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags":
+			_, _ = w.Write([]byte(`{"models":[
+				{"name":"nomic-embed-text:latest","size":274302450,"details":{"family":"nomic-bert","parameter_size":"137M"}},
+				{"name":"llama3.2:latest","size":2019393189,"details":{"family":"llama"}},
+				{"name":"mxbai-embed-large:latest","details":{"family":"bert"}}
+			]}`))
+		case "/api/embed":
+			var req ollamaEmbedRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, `{"error":"bad request"}`, http.StatusBadRequest)
+				return
+			}
+			if req.Model != "nomic-embed-text:latest" {
+				http.Error(w, `{"error":"model not found"}`, http.StatusNotFound)
+				return
+			}
+			embeddings := make([][]float32, len(req.Input))
+			for i := range embeddings {
+				embeddings[i] = make([]float32, dimension)
+				embeddings[i][0] = float32(i)
+			}
+			_ = json.NewEncoder(w).Encode(ollamaEmbedResponse{Embeddings: embeddings})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestOllamaEmbeddingClientProbesAndEmbeds(t *testing.T) {
+	server := newOllamaStandIn(t, 768)
+	defer server.Close()
+
+	client, err := NewOllamaEmbeddingClient(&models.EmbeddingModelInfo{
+		ID:          "ollama/nomic-embed-text",
+		Backend:     "ollama",
+		BaseURL:     server.URL,
+		RemoteModel: "nomic-embed-text:latest",
+		BatchSize:   2,
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	dimension, err := client.ProbeDimension()
+	if err != nil || dimension != 768 {
+		t.Fatalf("expected 768 dimensions, got %d (%v)", dimension, err)
+	}
+
+	vectors, err := client.GenerateEmbeddings([]string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if len(vectors) != 3 || vectors[1][0] != 1 || vectors[2][0] != 0 {
+		t.Fatalf("unexpected vectors across batches")
+	}
+
+	client.model = "missing"
+	client.maxRetries = 0
+	if _, err := client.GenerateEmbeddings([]string{"a"}); err == nil {
+		t.Fatalf("expected an unknown model to fail")
+	}
+}
+
+func TestListOllamaModelsFiltersEmbeddingModels(t *testing.T) {
+	server := newOllamaStandIn(t, 4)
+	defer server.Close()
+
+	pulled, err := ListOllamaModels(server.URL)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	var names []string
+	for _, model := range pulled {
+		if model.IsEmbeddingModel() {
+			names = append(names, model.Name)
+		}
+	}
+	if len(names) != 2 || names[0] != "nomic-embed-text:latest" || names[1] != "mxbai-embed-large:latest" {
+		t.Fatalf("unexpected embedding models %v", names)
+	}
+}
+
+func TestDefaultOllamaBaseURL(t *testing.T) {
+	t.Setenv("OLLAMA_HOST", "")
+	if got := DefaultOllamaBaseURL(); got != "http://localhost:11434" {
+		t.Fatalf("unexpected default %q", got)
+	}
+	t.Setenv("OLLAMA_HOST", "127.0.0.1:9999")
+	if got := DefaultOllamaBaseURL(); got != "http://127.0.0.1:9999" {
+		t.Fatalf("unexpected OLLAMA_HOST mapping %q", got)
+	}
+}
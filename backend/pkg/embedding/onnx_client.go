@@ -134,6 +134,24 @@ func (c *ONNXEmbeddingClient) GenerateEmbeddings(texts []string) ([][]float32, e
 	return results, nil
 }
 
+// GenerateQueryEmbeddings embeds queries like documents; instruction prefixes are added by
+// WithInstructionPrefixes.
+func (c *ONNXEmbeddingClient) GenerateQueryEmbeddings(texts []string) ([][]float32, error) {
+	return c.GenerateEmbeddings(texts)
+}
+
+// ProbeDimension embeds a short text and returns the vector length the model produces.
+func (c *ONNXEmbeddingClient) ProbeDimension() (int, error) {
+	vectors, err := c.GenerateEmbeddings([]string{"dimension probe"})
+	if err != nil {
+		return 0, fmt.Errorf("failed to probe embedding dimension: %w", err)
+	}
+	if len(vectors) == 0 || len(vectors[0]) == 0 {
+		return 0, fmt.Errorf("model returned an empty embedding")
+	}
+	return len(vectors[0]), nil
+}
+
 // Close releases ONNX runtime resources.
 func (c *ONNXEmbeddingClient) Close() error {
 	c.mu.Lock()
@@ -225,19 +243,25 @@ func (c *ONNXEmbeddingClient) embedSingle(text string) ([]float32, error) {
 }
 
 func (c *ONNXEmbeddingClient) buildInputTensors(ids []int, attMask []int, tokenTypeIDs []int) ([]onnx.Value, func(), error) {
-	shape := onnx.NewShape(1, int64(c.maxSeqLen))
-	idTensor, err := onnx.NewTensor(shape, toInt64(ids, c.maxSeqLen))
+	return buildONNXInputTensors(c.inputNames, c.maxSeqLen, c.expectTokenTypes, ids, attMask, tokenTypeIDs)
+}
+
+// buildONNXInputTensors creates the [1, maxSeqLen] int64 tensors in the order the model declares
+// its inputs. Shared by the embedding client and the cross-encoder reranker.
+func buildONNXInputTensors(inputNames []string, maxSeqLen int, expectTokenTypes bool, ids []int, attMask []int, tokenTypeIDs []int) ([]onnx.Value, func(), error) {
+	shape := onnx.NewShape(1, int64(maxSeqLen))
+	idTensor, err := onnx.NewTensor(shape, toInt64(ids, maxSeqLen))
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to build input_ids tensor: %w", err)
 	}
-	attTensor, err := onnx.NewTensor(shape, toInt64(attMask, c.maxSeqLen))
+	attTensor, err := onnx.NewTensor(shape, toInt64(attMask, maxSeqLen))
 	if err != nil {
 		idTensor.Destroy()
 		return nil, nil, fmt.Errorf("failed to build attention_mask tensor: %w", err)
 	}
 	var tokenTensor *onnx.Tensor[int64]
-	if c.expectTokenTypes {
-		tokenTensor, err = onnx.NewTensor(shape, toInt64(tokenTypeIDs, c.maxSeqLen))
+	if expectTokenTypes {
+		tokenTensor, err = onnx.NewTensor(shape, toInt64(tokenTypeIDs, maxSeqLen))
 		if err != nil {
 			idTensor.Destroy()
 			attTensor.Destroy()
@@ -253,8 +277,8 @@ func (c *ONNXEmbeddingClient) buildInputTensors(ids []int, attMask []int, tokenT
 		}
 	}
 
-	values := make([]onnx.Value, 0, len(c.inputNames))
-	for _, name := range c.inputNames {
+	values := make([]onnx.Value, 0, len(inputNames))
+	for _, name := range inputNames {
 		switch strings.ToLower(name) {
 		case "input_ids":
 			values = append(values, idTensor)
@@ -407,6 +431,11 @@ func normalizeVector(vec []float32) {
 }
 
 func (c *ONNXEmbeddingClient) normalizeEncoding(enc *tokenizer.Encoding) {
+	normalizeEncodingSlices(enc, c.padTypeID, c.padToken)
+}
+
+// normalizeEncodingSlices brings every auxiliary slice of enc to len(enc.Ids).
+func normalizeEncodingSlices(enc *tokenizer.Encoding, padTypeID int, padToken string) {
 	if enc == nil {
 		return
 	}
@@ -415,8 +444,8 @@ func (c *ONNXEmbeddingClient) normalizeEncoding(enc *tokenizer.Encoding) {
 		return
 	}
 
-	enc.TypeIds = padOrTrimInt(enc.TypeIds, targetLen, c.padTypeID)
-	enc.Tokens = padOrTrimString(enc.Tokens, targetLen, c.padToken)
+	enc.TypeIds = padOrTrimInt(enc.TypeIds, targetLen, padTypeID)
+	enc.Tokens = padOrTrimString(enc.Tokens, targetLen, padToken)
 	enc.SpecialTokenMask = padOrTrimInt(enc.SpecialTokenMask, targetLen, 0)
 	enc.AttentionMask = padOrTrimInt(enc.AttentionMask, targetLen, 1)
 	enc.Offsets = padOrTrimOffsets(enc.Offsets, targetLen)
@@ -0,0 +1,163 @@
+package embedding
+
+import (
+	"CodeTextor/backend/pkg/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	openAIDefaultBatchSize  = 32
+	openAIDefaultMaxRetries = 3
+	openAIDefaultTimeout    = 60 * time.Second
+)
+
+// OpenAIEmbeddingClient calls an OpenAI-compatible /v1/embeddings endpoint (llama.cpp server,
+// vLLM, text-embeddings-inference, LocalAI, ...).
+type OpenAIEmbeddingClient struct {
+	endpoint   string
+	model      string
+	apiKey     string
+	dimension  int
+	batchSize  int
+	maxRetries int
+	backoff    time.Duration
+	httpClient *http.Client
+}
+
+type openAIEmbeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type openAIEmbeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// NewOpenAIEmbeddingClient builds a client from the model's BaseURL, RemoteModel, APIKey,
+// BatchSize, MaxRetries and TimeoutSeconds settings.
+func NewOpenAIEmbeddingClient(meta *models.EmbeddingModelInfo) (*OpenAIEmbeddingClient, error) {
+	if meta == nil {
+		return nil, fmt.Errorf("embedding model metadata is required")
+	}
+	endpoint, err := openAIEmbeddingsEndpoint(meta.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("embedding model %s: %w", meta.ID, err)
+	}
+	model := strings.TrimSpace(meta.RemoteModel)
+	if model == "" {
+		model = meta.ID
+	}
+
+	client := &OpenAIEmbeddingClient{
+		endpoint:   endpoint,
+		model:      model,
+		apiKey:     strings.TrimSpace(meta.APIKey),
+		dimension:  meta.Dimension,
+		batchSize:  meta.BatchSize,
+		maxRetries: meta.MaxRetries,
+		backoff:    httpInitialBackoff,
+		httpClient: &http.Client{Timeout: openAIDefaultTimeout},
+	}
+	if client.batchSize <= 0 {
+		client.batchSize = openAIDefaultBatchSize
+	}
+	if client.maxRetries <= 0 {
+		client.maxRetries = openAIDefaultMaxRetries
+	}
+	if meta.TimeoutSeconds > 0 {
+		client.httpClient.Timeout = time.Duration(meta.TimeoutSeconds) * time.Second
+	}
+	return client, nil
+}
+
+// openAIEmbeddingsEndpoint accepts a server root with or without the /v1 suffix.
+func openAIEmbeddingsEndpoint(baseURL string) (string, error) {
+	base := strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if base == "" {
+		return "", fmt.Errorf("base URL is required for the openai backend")
+	}
+	if !strings.HasPrefix(base, "http://") && !strings.HasPrefix(base, "https://") {
+		return "", fmt.Errorf("base URL %q must start with http:// or https://", baseURL)
+	}
+	base = strings.TrimSuffix(base, "/embeddings")
+	if !strings.HasSuffix(base, "/v1") {
+		base += "/v1"
+	}
+	return base + "/embeddings", nil
+}
+
+// GenerateEmbeddings embeds texts in batches, preserving input order.
+func (c *OpenAIEmbeddingClient) GenerateEmbeddings(texts []string) ([][]float32, error) {
+	results := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += c.batchSize {
+		end := min(start+c.batchSize, len(texts))
+		vectors, err := c.embedBatchWithRetry(texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, vectors...)
+	}
+	return results, nil
+}
+
+// GenerateQueryEmbeddings embeds queries like documents; instruction prefixes are added by
+// WithInstructionPrefixes.
+func (c *OpenAIEmbeddingClient) GenerateQueryEmbeddings(texts []string) ([][]float32, error) {
+	return c.GenerateEmbeddings(texts)
+}
+
+// Close releases idle HTTP connections; the client holds no other resources.
+func (c *OpenAIEmbeddingClient) Close() error {
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
+
+func (c *OpenAIEmbeddingClient) embedBatchWithRetry(batch []string) ([][]float32, error) {
+	var vectors [][]float32
+	err := retryHTTP(c.maxRetries, c.backoff, func() (bool, error) {
+		var retryable bool
+		var err error
+		vectors, retryable, err = c.embedBatch(batch)
+		return retryable, err
+	})
+	return vectors, err
+}
+
+// embedBatch performs one request. The bool reports whether the failure is worth retrying.
+func (c *OpenAIEmbeddingClient) embedBatch(batch []string) ([][]float32, bool, error) {
+	var parsed openAIEmbeddingResponse
+	retryable, err := postJSON(c.httpClient, c.endpoint, c.apiKey, openAIEmbeddingRequest{Model: c.model, Input: batch}, &parsed, func(body []byte) string {
+		var failure openAIEmbeddingResponse
+		if json.Unmarshal(body, &failure) == nil && failure.Error != nil {
+			return failure.Error.Message
+		}
+		return ""
+	})
+	if err != nil {
+		return nil, retryable, err
+	}
+	if len(parsed.Data) != len(batch) {
+		return nil, false, fmt.Errorf("embedding server returned %d vectors for %d inputs", len(parsed.Data), len(batch))
+	}
+
+	sort.SliceStable(parsed.Data, func(i, j int) bool { return parsed.Data[i].Index < parsed.Data[j].Index })
+	vectors := make([][]float32, len(parsed.Data))
+	for i, item := range parsed.Data {
+		vectors[i] = item.Embedding
+	}
+	if err := checkDimension(vectors, c.dimension, c.model); err != nil {
+		return nil, false, err
+	}
+	return vectors, false, nil
+}
@@ -0,0 +1,103 @@
+package embedding
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"CodeTextor/backend/pkg/models"
+)
+
+func TestOpenAIEmbeddingClientBatchesAndRetries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/embeddings" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("unexpected authorization header %q", got)
+		}
+		if calls.Add(1) == 1 {
+			http.Error(w, `{"error":{"message":"warming up"}}`, http.StatusServiceUnavailable)
+			return
+		}
+		var req openAIEmbeddingRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if req.Model != "nomic-embed-text" {
+			t.Errorf("unexpected model %q", req.Model)
+		}
+		type item struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		}
+		data := make([]item, len(req.Input))
+		for i, text := range req.Input {
+			// Reverse order checks that results are re-sorted by index.
+			data[len(req.Input)-1-i] = item{Index: i, Embedding: []float32{float32(len(text)), 1}}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	defer server.Close()
+
+	client, err := NewOpenAIEmbeddingClient(&models.EmbeddingModelInfo{
+		ID:          "local/nomic",
+		Backend:     "openai",
+		Dimension:   2,
+		BaseURL:     server.URL,
+		RemoteModel: "nomic-embed-text",
+		APIKey:      "secret",
+		BatchSize:   2,
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	client.backoff = 0
+
+	vectors, err := client.GenerateEmbeddings([]string{"a", "bb", "ccc"})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if len(vectors) != 3 || vectors[0][0] != 1 || vectors[1][0] != 2 || vectors[2][0] != 3 {
+		t.Fatalf("unexpected vectors %v", vectors)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("expected one retry plus two batches, got %d calls", calls.Load())
+	}
+}
+
+func TestOpenAIEmbeddingClientRejectsWrongDimension(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[{"index":0,"embedding":[1,2,3]}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewOpenAIEmbeddingClient(&models.EmbeddingModelInfo{ID: "m", Dimension: 2, BaseURL: server.URL + "/v1/"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	if _, err := client.GenerateEmbeddings([]string{"x"}); err == nil {
+		t.Fatalf("expected a dimension mismatch error")
+	}
+}
+
+func TestOpenAIEmbeddingsEndpoint(t *testing.T) {
+	for input, want := range map[string]string{
+		"http://localhost:8080":          "http://localhost:8080/v1/embeddings",
+		"http://localhost:8080/v1/":      "http://localhost:8080/v1/embeddings",
+		"https://host/api/v1/embeddings": "https://host/api/v1/embeddings",
+		" http://127.0.0.1:11434/v1 ":    "http://127.0.0.1:11434/v1/embeddings",
+	} {
+		got, err := openAIEmbeddingsEndpoint(input)
+		if err != nil || got != want {
+			t.Fatalf("endpoint(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := openAIEmbeddingsEndpoint("localhost:8080"); err == nil {
+		t.Fatalf("expected a base URL without scheme to be rejected")
+	}
+}
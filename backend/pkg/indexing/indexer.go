@@ -38,10 +38,13 @@ type Indexer struct {
 	debounceTimers   map[string]*time.Timer
 	eventEmitter     func(string, interface{})
 	embeddingModelID string
+	// additionalClients embed chunks for the project's additional models, keyed by model ID.
+	additionalClients map[string]embedding.EmbeddingClient
 }
 
-// NewIndexer creates a new indexer for a project.
-func NewIndexer(project *models.Project, vectorStore *store.VectorStore, eventEmitter func(string, interface{}), client embedding.EmbeddingClient) (*Indexer, error) {
+// NewIndexer creates a new indexer for a project. additional holds clients for the project's
+// additional embedding models, keyed by model ID; it may be nil.
+func NewIndexer(project *models.Project, vectorStore *store.VectorStore, eventEmitter func(string, interface{}), client embedding.EmbeddingClient, additional map[string]embedding.EmbeddingClient) (*Indexer, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	// Create chunk config from project settings
@@ -67,19 +70,20 @@ func NewIndexer(project *models.Project, vectorStore *store.VectorStore, eventEm
 	}
 
 	return &Indexer{
-		project:          project,
-		progress:         &models.IndexingProgress{Status: models.IndexingStatusIdle},
-		stopChan:         make(chan struct{}),
-		ctx:              ctx,
-		cancel:           cancel,
-		semaphore:        make(chan struct{}, 10), // Limit to 10 concurrent operations
-		embeddingClient:  client,
-		vectorStore:      vectorStore,
-		parser:           chunker.NewParser(chunkConfig),
-		semanticChunker:  chunker.NewSemanticChunker(chunkConfig),
-		debounceTimers:   make(map[string]*time.Timer),
-		eventEmitter:     eventEmitter,
-		embeddingModelID: modelID,
+		project:           project,
+		progress:          &models.IndexingProgress{Status: models.IndexingStatusIdle},
+		stopChan:          make(chan struct{}),
+		ctx:               ctx,
+		cancel:            cancel,
+		semaphore:         make(chan struct{}, 10), // Limit to 10 concurrent operations
+		embeddingClient:   client,
+		vectorStore:       vectorStore,
+		parser:            chunker.NewParser(chunkConfig),
+		semanticChunker:   chunker.NewSemanticChunker(chunkConfig),
+		debounceTimers:    make(map[string]*time.Timer),
+		eventEmitter:      eventEmitter,
+		embeddingModelID:  modelID,
+		additionalClients: additional,
 	}, nil
 }
 
@@ -91,6 +95,7 @@ func (i *Indexer) Run(filePreviews []*models.FilePreview) {
 	i.progress.ProcessedFiles = 0
 	i.progress.CurrentFile = ""
 	i.progress.Error = ""
+	i.emitStatus()
 
 	log.Printf("Starting indexing for project %s: %d files to process", i.project.Name, i.progress.TotalFiles)
 
@@ -234,6 +239,7 @@ func (i *Indexer) Run(filePreviews []*models.FilePreview) {
 					log.Printf("Failed to save chunk %d for file %s: %v", idx, file.RelativePath, err)
 				}
 			}
+			i.storeAdditionalEmbeddings(dbChunks, chunkContents)
 
 			// Save file metadata
 			fileRecord := &models.File{
@@ -262,6 +268,7 @@ func (i *Indexer) Run(filePreviews []*models.FilePreview) {
 	}
 
 	wg.Wait()
+	i.backfillAdditionalEmbeddings()
 
 	log.Printf("Initial indexing completed for project %s", i.project.Name)
 
@@ -272,6 +279,7 @@ func (i *Indexer) Run(filePreviews []*models.FilePreview) {
 			log.Printf("Failed to create file watcher for project %s: %v", i.project.Name, err)
 			i.progress.Status = models.IndexingStatusError
 			i.progress.Error = fmt.Sprintf("Failed to start file watcher: %v", err)
+			i.emitStatus()
 			return
 		}
 		i.watcher = watcher
@@ -310,6 +318,7 @@ func (i *Indexer) Run(filePreviews []*models.FilePreview) {
 		}
 
 		i.progress.Status = models.IndexingStatusIdle // Back to idle after initial scan
+		i.emitStatus()
 
 		for {
 			select {
@@ -338,12 +347,14 @@ func (i *Indexer) Run(filePreviews []*models.FilePreview) {
 				log.Printf("File watcher error for project %s: %v", i.project.Name, err)
 				i.progress.Status = models.IndexingStatusError
 				i.progress.Error = fmt.Sprintf("File watcher error: %v", err)
+				i.emitStatus()
 				return
 			}
 		}
 	} else {
 		i.progress.Status = models.IndexingStatusCompleted // If no continuous indexing, just complete
 		i.progress.CurrentFile = ""
+		i.emitStatus()
 	}
 }
 
@@ -460,7 +471,9 @@ func (i *Indexer) debounceFileUpdate(filePath string) {
 	// Create new timer that will trigger full index update
 	i.debounceTimers[filePath] = time.AfterFunc(debounceDelay, func() {
 		log.Printf("Processing index update for %s (after debounce)", filePath)
-		i.updateFileIndex(filePath)
+		if _, err := i.updateFileIndex(filePath); err != nil {
+			log.Printf("Failed to re-index %s: %v", filePath, err)
+		}
 
 		// Clean up the timer
 		i.debounceMu.Lock()
@@ -470,13 +483,14 @@ func (i *Indexer) debounceFileUpdate(filePath string) {
 }
 
 // updateFileIndex re-indexes a single file (chunks + outline) when it changes.
-// This is called by the file watcher when a file is modified.
-func (i *Indexer) updateFileIndex(filePath string) {
+// This is called by the file watcher when a file is modified and by explicit reindex requests.
+// It reports whether the file was re-indexed (false when its content is unchanged).
+func (i *Indexer) updateFileIndex(filePath string) (bool, error) {
 	if i.vectorStore == nil || i.parser == nil || i.semanticChunker == nil {
-		return
+		return false, fmt.Errorf("indexer is not initialized")
 	}
 	if filePath == "" {
-		return
+		return false, fmt.Errorf("file path cannot be empty")
 	}
 
 	absPath := filepath.Clean(filePath)
@@ -495,15 +509,13 @@ func (i *Indexer) updateFileIndex(filePath string) {
 	// Read file content
 	source, err := os.ReadFile(absPath)
 	if err != nil {
-		log.Printf("Failed to read file for re-indexing %s: %v", absPath, err)
-		return
+		return false, fmt.Errorf("failed to read file for re-indexing %s: %w", absPath, err)
 	}
 
 	// Get file info for last modified timestamp
 	fileInfo, err := os.Stat(absPath)
 	if err != nil {
-		log.Printf("Failed to stat file %s: %v", absPath, err)
-		return
+		return false, fmt.Errorf("failed to stat file %s: %w", absPath, err)
 	}
 
 	// Check if file has changed
@@ -512,7 +524,7 @@ func (i *Indexer) updateFileIndex(filePath string) {
 	if err == nil && existingFile != nil {
 		if existingFile.Hash == fileHash && existingFile.LastModified == fileInfo.ModTime().Unix() {
 			log.Printf("Skipping unchanged file %s", relativePath)
-			return
+			return false, nil
 		}
 	}
 
@@ -531,8 +543,7 @@ func (i *Indexer) updateFileIndex(filePath string) {
 		// Use semantic chunking for supported files
 		semanticChunks, err := i.semanticChunker.ChunkFile(relativePath, source)
 		if err != nil {
-			log.Printf("Failed to semantically chunk file %s: %v", absPath, err)
-			return
+			return false, fmt.Errorf("failed to semantically chunk file %s: %w", absPath, err)
 		}
 
 		// Extract enriched content for embedding and prepare DB chunks
@@ -568,8 +579,7 @@ func (i *Indexer) updateFileIndex(filePath string) {
 		// Fallback to simple line-based chunking
 		simpleChunks, err := utils.ChunkFile(absPath, i.project.Config.ChunkSizeMax)
 		if err != nil {
-			log.Printf("Failed to chunk file %s: %v", absPath, err)
-			return
+			return false, fmt.Errorf("failed to chunk file %s: %w", absPath, err)
 		}
 
 		chunkContents = make([]string, len(simpleChunks))
@@ -594,8 +604,7 @@ func (i *Indexer) updateFileIndex(filePath string) {
 	// Generate embeddings for chunks
 	embeddings, err := i.embeddingClient.GenerateEmbeddings(chunkContents)
 	if err != nil {
-		log.Printf("Failed to generate embeddings for file %s: %v", absPath, err)
-		return
+		return false, fmt.Errorf("failed to generate embeddings for file %s: %w", absPath, err)
 	}
 
 	// Save chunks to database with embeddings
@@ -608,6 +617,7 @@ func (i *Indexer) updateFileIndex(filePath string) {
 			log.Printf("Failed to save chunk %d for file %s: %v", idx, relativePath, err)
 		}
 	}
+	i.storeAdditionalEmbeddings(dbChunks, chunkContents)
 
 	// Save file metadata
 	fileRecord := &models.File{
@@ -624,6 +634,7 @@ func (i *Indexer) updateFileIndex(filePath string) {
 
 	// Also update the outline
 	i.storeOutlineForFile(absPath)
+	return true, nil
 }
 
 func (i *Indexer) storeOutlineForFile(filePath string) {
@@ -750,3 +761,19 @@ func (i *Indexer) emitFileUpdate(filePath string) {
 	}
 	i.eventEmitter("project:fileIndexed", payload)
 }
+
+// emitStatus reports indexing run transitions (started, finished, failed) to listeners.
+func (i *Indexer) emitStatus() {
+	if i.eventEmitter == nil {
+		return
+	}
+	payload := map[string]interface{}{
+		"projectId":      i.project.ID,
+		"status":         string(i.progress.Status),
+		"totalFiles":     i.progress.TotalFiles,
+		"processedFiles": i.progress.ProcessedFiles,
+		"error":          i.progress.Error,
+		"timestamp":      time.Now().Unix(),
+	}
+	i.eventEmitter("project:indexingStatus", payload)
+}
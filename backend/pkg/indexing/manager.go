@@ -4,21 +4,45 @@ import (
 	"CodeTextor/backend/internal/store"
 	"CodeTextor/backend/pkg/embedding"
 	"CodeTextor/backend/pkg/models"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"sync"
+	"time"
 )
 
 // Manager manages the lifecycle of indexing jobs for all projects.
 type Manager struct {
 	projectIndexers map[string]*Indexer
+	migrations      map[string]*migration
+	evaluations     map[string]context.CancelFunc
 	progressMap     sync.Map // Safely stores map[string]*models.IndexingProgress
 	mu              sync.Mutex
 	eventEmitter    func(string, interface{})
 }
 
+// migrationStatusInterval is the minimum time between two progress events of a migration.
+const migrationStatusInterval = 500 * time.Millisecond
+
+// migration is a background re-embedding job started by StartMigration.
+type migration struct {
+	progress *models.IndexingProgress
+	cancel   context.CancelFunc
+	// lastEmit is when ReportMigrationProgress last emitted, guarded by Manager.mu.
+	lastEmit time.Time
+}
+
+// MigrationFunc re-embeds a project's chunks, updating progress as it goes. It must return
+// promptly once ctx is cancelled.
+type MigrationFunc func(ctx context.Context, progress *models.IndexingProgress) error
+
 // NewManager creates a new IndexerManager.
 func NewManager(eventEmitter func(string, interface{})) *Manager {
 	return &Manager{
 		projectIndexers: make(map[string]*Indexer),
+		migrations:      make(map[string]*migration),
+		evaluations:     make(map[string]context.CancelFunc),
 		eventEmitter:    eventEmitter,
 	}
 }
@@ -26,7 +50,7 @@ func NewManager(eventEmitter func(string, interface{})) *Manager {
 // StartIndexer starts a new indexing job for a given project.
 // If an indexer is already running for the project, the existing one will be stopped first.
 // This method ensures that only one indexer runs per project at a time.
-func (m *Manager) StartIndexer(project *models.Project, files []*models.FilePreview, vectorStore *store.VectorStore, client embedding.EmbeddingClient, onComplete func(models.IndexingStatus)) error {
+func (m *Manager) StartIndexer(project *models.Project, files []*models.FilePreview, vectorStore *store.VectorStore, client embedding.EmbeddingClient, additional map[string]embedding.EmbeddingClient, onComplete func(models.IndexingStatus)) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -41,11 +65,14 @@ func (m *Manager) StartIndexer(project *models.Project, files []*models.FilePrev
 	}
 
 	// Create and register the new indexer
-	newIndexer, err := NewIndexer(project, vectorStore, m.eventEmitter, client)
+	newIndexer, err := NewIndexer(project, vectorStore, m.eventEmitter, client, additional)
 	if err != nil {
 		return err
 	}
 	m.projectIndexers[project.ID] = newIndexer
+	// Report the run as started right away so pollers never observe the idle state of a
+	// run that has not been scheduled yet.
+	newIndexer.progress.Status = models.IndexingStatusIndexing
 	m.progressMap.Store(project.ID, newIndexer.progress)
 
 	// Start the indexer in a goroutine
@@ -79,8 +106,209 @@ func (m *Manager) StopIndexer(projectID string) {
 	}
 }
 
-// GetIndexingProgress retrieves the current indexing progress for a project.
+// ReindexFiles synchronously re-indexes the given absolute file paths. The running indexer for
+// the project is reused when there is one; otherwise a short-lived indexer is created.
+func (m *Manager) ReindexFiles(project *models.Project, paths []string, vectorStore *store.VectorStore, client embedding.EmbeddingClient, additional map[string]embedding.EmbeddingClient) ([]models.FileReindexResult, error) {
+	m.mu.Lock()
+	indexer, running := m.projectIndexers[project.ID]
+	m.mu.Unlock()
+
+	if !running {
+		var err error
+		indexer, err = NewIndexer(project, vectorStore, m.eventEmitter, client, additional)
+		if err != nil {
+			return nil, err
+		}
+		defer indexer.Stop()
+	}
+
+	results := make([]models.FileReindexResult, 0, len(paths))
+	for _, path := range paths {
+		result := models.FileReindexResult{Path: path, Status: models.FileReindexIndexed}
+		changed, err := indexer.updateFileIndex(path)
+		switch {
+		case err != nil:
+			result.Status = models.FileReindexFailed
+			result.Error = err.Error()
+		case !changed:
+			result.Status = models.FileReindexUnchanged
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// IsIndexerRunning reports whether an indexer (initial run or file watcher) is registered.
+func (m *Manager) IsIndexerRunning(projectID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, exists := m.projectIndexers[projectID]
+	return exists
+}
+
+// StartMigration runs an embedding model migration for a project in the background. Only one
+// migration runs per project, and none starts while a full indexing run is in progress. While
+// it runs GetIndexingProgress reports its progress with status "migrating"; afterwards the
+// outcome stays visible unless an indexer has taken over the project.
+func (m *Manager) StartMigration(projectID, modelID string, run MigrationFunc) error {
+	return m.startMigration(projectID, &models.IndexingProgress{Status: models.IndexingStatusMigrating, MigrationModel: modelID}, run)
+}
+
+// StartModelBackfill embeds the existing chunks with newly added models in the background. It
+// shares the migration slot, so it reports status "migrating" with MigrationBackfill set and
+// cannot overlap a model switch.
+func (m *Manager) StartModelBackfill(projectID string, modelIDs []string, run MigrationFunc) error {
+	return m.startMigration(projectID, &models.IndexingProgress{
+		Status:            models.IndexingStatusMigrating,
+		MigrationModel:    strings.Join(modelIDs, ", "),
+		MigrationBackfill: true,
+	}, run)
+}
+
+func (m *Manager) startMigration(projectID string, progress *models.IndexingProgress, run MigrationFunc) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.migrations[projectID]; exists {
+		return fmt.Errorf("an embedding model migration is already running for project %s", projectID)
+	}
+	if indexer, exists := m.projectIndexers[projectID]; exists && indexer.progress.Status == models.IndexingStatusIndexing {
+		return fmt.Errorf("wait for the indexing run of project %s to finish before switching models", projectID)
+	}
+	if _, exists := m.evaluations[projectID]; exists {
+		return fmt.Errorf("wait for the evaluation of project %s to finish before switching models", projectID)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &migration{progress: progress, cancel: cancel, lastEmit: time.Now()}
+	m.migrations[projectID] = job
+	m.emitMigrationStatus(projectID, job.progress)
+
+	go func() {
+		defer cancel()
+		err := run(ctx, job.progress)
+		switch {
+		case errors.Is(err, context.Canceled) || (err != nil && ctx.Err() != nil):
+			job.progress.Status = models.IndexingStatusIdle
+		case err != nil:
+			job.progress.Status = models.IndexingStatusError
+			job.progress.Error = err.Error()
+		default:
+			job.progress.Status = models.IndexingStatusCompleted
+		}
+
+		m.mu.Lock()
+		delete(m.migrations, projectID)
+		if _, exists := m.projectIndexers[projectID]; !exists {
+			m.progressMap.Store(projectID, job.progress)
+		}
+		m.mu.Unlock()
+		m.emitMigrationStatus(projectID, job.progress)
+	}()
+	return nil
+}
+
+// StartEvaluation reserves a project for a retrieval evaluation, which embeds chunks with the
+// evaluated models much like a backfill. It fails while a migration, backfill or another
+// evaluation of the project is running, and blocks those from starting until release is
+// called. The returned context is cancelled by release, by StopEvaluation or with ctx.
+func (m *Manager) StartEvaluation(ctx context.Context, projectID string) (context.Context, func(), error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.migrations[projectID]; exists {
+		return nil, nil, fmt.Errorf("wait for the embedding job of project %s to finish before evaluating", projectID)
+	}
+	if _, exists := m.evaluations[projectID]; exists {
+		return nil, nil, fmt.Errorf("an evaluation is already running for project %s", projectID)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	m.evaluations[projectID] = cancel
+	release := func() {
+		cancel()
+		m.mu.Lock()
+		delete(m.evaluations, projectID)
+		m.mu.Unlock()
+	}
+	return ctx, sync.OnceFunc(release), nil
+}
+
+// StopEvaluation cancels the running evaluation of a project, if any. The evaluation releases
+// the project once it has removed its temporary vectors.
+func (m *Manager) StopEvaluation(projectID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cancel, exists := m.evaluations[projectID]
+	if exists {
+		cancel()
+	}
+	return exists
+}
+
+// StopMigration cancels the running embedding model migration of a project, if any. The
+// previous embeddings stay in place.
+func (m *Manager) StopMigration(projectID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, exists := m.migrations[projectID]
+	if exists {
+		job.cancel()
+	}
+	return exists
+}
+
+// ReportMigrationProgress emits the progress of the project's running migration after a batch,
+// at most once per migrationStatusInterval. It does nothing unless progress belongs to that
+// migration, so other callers of EmbedMissingChunks can share the same reporting hook.
+func (m *Manager) ReportMigrationProgress(projectID string, progress *models.IndexingProgress) {
+	m.mu.Lock()
+	job, exists := m.migrations[projectID]
+	if !exists || job.progress != progress || time.Since(job.lastEmit) < migrationStatusInterval {
+		m.mu.Unlock()
+		return
+	}
+	job.lastEmit = time.Now()
+	m.mu.Unlock()
+	m.emitMigrationStatus(projectID, progress)
+}
+
+// IsMigrating reports whether an embedding model migration is running for a project.
+func (m *Manager) IsMigrating(projectID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, exists := m.migrations[projectID]
+	return exists
+}
+
+func (m *Manager) emitMigrationStatus(projectID string, progress *models.IndexingProgress) {
+	if m.eventEmitter == nil {
+		return
+	}
+	m.eventEmitter("project:indexingStatus", map[string]interface{}{
+		"projectId":         projectID,
+		"status":            string(progress.Status),
+		"migrationModel":    progress.MigrationModel,
+		"migrationBackfill": progress.MigrationBackfill,
+		"totalChunks":       progress.TotalChunks,
+		"processedChunks":   progress.ProcessedChunks,
+		"error":             progress.Error,
+		"timestamp":         time.Now().Unix(),
+	})
+}
+
+// GetIndexingProgress retrieves the current indexing progress for a project. A running
+// embedding model migration takes precedence over the indexer's progress.
 func (m *Manager) GetIndexingProgress(projectID string) (*models.IndexingProgress, bool) {
+	m.mu.Lock()
+	job, migrating := m.migrations[projectID]
+	m.mu.Unlock()
+	if migrating {
+		return job.progress, true
+	}
+
 	progress, found := m.progressMap.Load(projectID)
 	if !found {
 		return nil, false
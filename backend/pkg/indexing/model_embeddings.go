@@ -0,0 +1,87 @@
+package indexing
+
+import (
+	"CodeTextor/backend/internal/store"
+	"CodeTextor/backend/pkg/embedding"
+	"CodeTextor/backend/pkg/models"
+	"context"
+	"fmt"
+	"log"
+)
+
+// additionalEmbeddingBatchSize is how many chunks are embedded per request when backfilling.
+const additionalEmbeddingBatchSize = 32
+
+// EmbedMissingChunks embeds every chunk that has no vector from modelID in chunk_embeddings,
+// batchSize chunks per request, until none is left or ctx is cancelled. progress, when set,
+// receives the covered and total chunk counts before each batch.
+func EmbedMissingChunks(ctx context.Context, vectorStore *store.VectorStore, client embedding.EmbeddingClient, modelID string, batchSize int, progress func(covered, total int)) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if progress != nil {
+			covered, total, err := vectorStore.ModelEmbeddingCoverage(modelID)
+			if err != nil {
+				return err
+			}
+			progress(covered, total)
+		}
+
+		chunks, err := vectorStore.ChunksMissingModelEmbedding(modelID, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(chunks) == 0 {
+			return nil
+		}
+		texts := make([]string, len(chunks))
+		for idx, chunk := range chunks {
+			texts[idx] = chunk.Content
+		}
+		if err := storeModelEmbeddings(vectorStore, client, modelID, chunks, texts); err != nil {
+			return err
+		}
+	}
+}
+
+// storeModelEmbeddings embeds texts with client and stores the vectors for the matching chunks.
+func storeModelEmbeddings(vectorStore *store.VectorStore, client embedding.EmbeddingClient, modelID string, chunks []*models.Chunk, texts []string) error {
+	vectors, err := client.GenerateEmbeddings(texts)
+	if err != nil {
+		return fmt.Errorf("failed to embed chunks with %s: %w", modelID, err)
+	}
+	if len(vectors) != len(chunks) {
+		return fmt.Errorf("%s returned %d embeddings for %d chunks", modelID, len(vectors), len(chunks))
+	}
+	batch := make(map[string][]float32, len(chunks))
+	for idx, chunk := range chunks {
+		if chunk.ID != "" {
+			batch[chunk.ID] = vectors[idx]
+		}
+	}
+	return vectorStore.SaveModelEmbeddings(modelID, batch)
+}
+
+// storeAdditionalEmbeddings embeds freshly inserted chunks with the project's additional models.
+// Failures are logged; the next indexing run fills the gaps.
+func (i *Indexer) storeAdditionalEmbeddings(chunks []*models.Chunk, texts []string) {
+	if len(chunks) == 0 {
+		return
+	}
+	for modelID, client := range i.additionalClients {
+		if err := storeModelEmbeddings(i.vectorStore, client, modelID, chunks, texts); err != nil {
+			log.Printf("Failed to store %s embeddings for %s: %v", modelID, chunks[0].FilePath, err)
+		}
+	}
+}
+
+// backfillAdditionalEmbeddings covers chunks that predate an additional model or whose
+// embedding failed earlier.
+func (i *Indexer) backfillAdditionalEmbeddings() {
+	for modelID, client := range i.additionalClients {
+		if err := EmbedMissingChunks(i.ctx, i.vectorStore, client, modelID, additionalEmbeddingBatchSize, nil); err != nil {
+			log.Printf("Failed to backfill %s embeddings for project %s: %v", modelID, i.project.Name, err)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+/*
+  File: audit.go
+  Purpose: Persisted MCP audit log with per-client telemetry, retention and JSONL export.
+  Author: CodeTextor project
+*/
+
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"CodeTextor/backend/pkg/models"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// auditPruneInterval is the number of inserts between retention passes.
+	auditPruneInterval = 200
+	// auditMaxArgumentBytes caps the stored argument payload per entry.
+	auditMaxArgumentBytes = 4096
+)
+
+// auditedMethods lists the MCP methods recorded in the audit log.
+var auditedMethods = map[string]bool{
+	"tools/call":     true,
+	"prompts/get":    true,
+	"resources/read": true,
+}
+
+// auditMiddleware records every tool call, prompt request and resource read.
+func (m *Manager) auditMiddleware(boundProjectID string) sdkmcp.Middleware {
+	return func(next sdkmcp.MethodHandler) sdkmcp.MethodHandler {
+		return func(ctx context.Context, method string, req sdkmcp.Request) (sdkmcp.Result, error) {
+			if !auditedMethods[method] {
+				return next(ctx, method, req)
+			}
+			start := time.Now()
+			result, err := next(ctx, method, req)
+			m.recordAudit(buildAuditEntry(boundProjectID, method, req, result, err, start))
+			return result, err
+		}
+	}
+}
+
+func buildAuditEntry(boundProjectID, method string, req sdkmcp.Request, result sdkmcp.Result, callErr error, start time.Time) *models.MCPAuditEntry {
+	entry := &models.MCPAuditEntry{
+		Timestamp:  start.UnixMilli(),
+		Method:     method,
+		ProjectID:  strings.TrimSpace(boundProjectID),
+		DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+
+	if session, ok := req.GetSession().(*sdkmcp.ServerSession); ok && session != nil {
+		entry.SessionID = session.ID()
+		if params := session.InitializeParams(); params != nil && params.ClientInfo != nil {
+			entry.ClientName = params.ClientInfo.Name
+			entry.ClientVersion = params.ClientInfo.Version
+		}
+	}
+	if token := requestToken(req); token != nil {
+		entry.TokenName = token.Name
+	}
+
+	var arguments any
+	switch params := req.GetParams().(type) {
+	case *sdkmcp.CallToolParamsRaw:
+		entry.Name = params.Name
+		arguments = params.Arguments
+		if entry.ProjectID == "" {
+			var scoped struct {
+				ProjectID string `json:"projectId"`
+			}
+			if json.Unmarshal(params.Arguments, &scoped) == nil {
+				entry.ProjectID = scoped.ProjectID
+			}
+		}
+	case *sdkmcp.GetPromptParams:
+		entry.Name = params.Name
+		arguments = params.Arguments
+		if entry.ProjectID == "" {
+			entry.ProjectID = params.Arguments["projectId"]
+		}
+	case *sdkmcp.ReadResourceParams:
+		entry.Name = params.URI
+		if projectID, _, _, err := parseResourceURI(params.URI); err == nil && entry.ProjectID == "" {
+			entry.ProjectID = projectID
+		}
+	}
+	if arguments != nil {
+		if encoded, err := json.Marshal(arguments); err == nil && string(encoded) != "null" {
+			entry.Arguments = truncateAuditText(string(encoded))
+		}
+	}
+
+	if result != nil {
+		if encoded, err := json.Marshal(result); err == nil {
+			entry.ResultBytes = len(encoded)
+		}
+	}
+	switch {
+	case callErr != nil:
+		entry.Error = callErr.Error()
+	case isToolError(result):
+		entry.Error = toolErrorText(result.(*sdkmcp.CallToolResult))
+	}
+	return entry
+}
+
+func isToolError(result sdkmcp.Result) bool {
+	toolResult, ok := result.(*sdkmcp.CallToolResult)
+	return ok && toolResult != nil && toolResult.IsError
+}
+
+func toolErrorText(result *sdkmcp.CallToolResult) string {
+	for _, content := range result.Content {
+		if text, ok := content.(*sdkmcp.TextContent); ok && text.Text != "" {
+			return truncateAuditText(text.Text)
+		}
+	}
+	return "tool returned an error"
+}
+
+func truncateAuditText(value string) string {
+	if len(value) <= auditMaxArgumentBytes {
+		return value
+	}
+	return truncateUTF8(value, auditMaxArgumentBytes) + "…"
+}
+
+// truncateUTF8 returns the longest prefix of value that fits in maxBytes without splitting a
+// multi-byte character.
+func truncateUTF8(value string, maxBytes int) string {
+	if len(value) <= maxBytes {
+		return value
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+	return value[:cut]
+}
+
+// recordAudit persists an entry and periodically applies the retention limits.
+func (m *Manager) recordAudit(entry *models.MCPAuditEntry) {
+	if err := m.configStore.InsertMCPAuditEntry(entry); err != nil {
+		log.Printf("Failed to write MCP audit entry: %v", err)
+		return
+	}
+	if atomic.AddInt64(&m.auditInserts, 1)%auditPruneInterval == 0 {
+		m.pruneAuditLog()
+	}
+}
+
+// pruneAuditLog enforces the configured retention window and entry cap.
+func (m *Manager) pruneAuditLog() {
+	cfg := m.GetConfig()
+	var cutoff int64
+	if cfg.AuditRetentionDays > 0 {
+		cutoff = time.Now().AddDate(0, 0, -cfg.AuditRetentionDays).UnixMilli()
+	}
+	if _, err := m.configStore.PruneMCPAuditLog(cutoff, cfg.AuditMaxEntries); err != nil {
+		log.Printf("Failed to prune MCP audit log: %v", err)
+	}
+}
+
+// QueryAuditLog returns audit entries matching the query, newest first.
+func (m *Manager) QueryAuditLog(query models.MCPAuditQuery) ([]models.MCPAuditEntry, error) {
+	if query.Limit < 0 {
+		query.Limit = 0
+	}
+	entries, err := m.configStore.QueryMCPAuditLog(query)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]models.MCPAuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, *entry)
+	}
+	return result, nil
+}
+
+// GetClientStats aggregates the audit log per MCP client.
+func (m *Manager) GetClientStats() ([]models.MCPClientStats, error) {
+	stats, err := m.configStore.MCPClientStats()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]models.MCPClientStats, 0, len(stats))
+	for _, stat := range stats {
+		result = append(result, *stat)
+	}
+	return result, nil
+}
+
+// ExportAuditLog writes every entry matching the query to path as JSON Lines, newest first,
+// and returns the number of entries written. Limit and offset are ignored.
+func (m *Manager) ExportAuditLog(path string, query models.MCPAuditQuery) (int, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return 0, fmt.Errorf("export path cannot be empty")
+	}
+	query.Limit = -1
+	query.Offset = 0
+	entries, err := m.configStore.QueryMCPAuditLog(query)
+	if err != nil {
+		return 0, err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create export file: %w", err)
+	}
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			file.Close()
+			return 0, fmt.Errorf("failed to write audit entry: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return 0, fmt.Errorf("failed to write export file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return 0, fmt.Errorf("failed to close export file: %w", err)
+	}
+	return len(entries), nil
+}
+
+// ClearAuditLog deletes every audit entry.
+func (m *Manager) ClearAuditLog() error {
+	return m.configStore.ClearMCPAuditLog()
+}
@@ -0,0 +1,63 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"CodeTextor/backend/pkg/models"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestBuildAuditEntryForToolCall(t *testing.T) {
+	req := requestWithToken(&models.MCPAPIToken{Name: "laptop"}).(*sdkmcp.CallToolRequest)
+	req.Params = &sdkmcp.CallToolParamsRaw{
+		Name:      "outline",
+		Arguments: json.RawMessage(`{"path":"main.go","projectId":"alpha"}`),
+	}
+	result := &sdkmcp.CallToolResult{
+		IsError: true,
+		Content: []sdkmcp.Content{&sdkmcp.TextContent{Text: "file not indexed"}},
+	}
+
+	entry := buildAuditEntry("", "tools/call", req, result, nil, time.Now())
+	if entry.Name != "outline" || entry.ProjectID != "alpha" || entry.TokenName != "laptop" {
+		t.Errorf("unexpected entry metadata: %+v", entry)
+	}
+	if !strings.Contains(entry.Arguments, `"path":"main.go"`) {
+		t.Errorf("arguments not recorded: %s", entry.Arguments)
+	}
+	if entry.Error != "file not indexed" || entry.ResultBytes == 0 {
+		t.Errorf("unexpected result fields: error=%q bytes=%d", entry.Error, entry.ResultBytes)
+	}
+}
+
+func TestBuildAuditEntryPrefersBoundProjectAndCallError(t *testing.T) {
+	req := &sdkmcp.GetPromptRequest{Params: &sdkmcp.GetPromptParams{
+		Name:      "explainFile",
+		Arguments: map[string]string{"path": "a.go", "projectId": "other"},
+	}}
+
+	entry := buildAuditEntry("alpha", "prompts/get", req, nil, errors.New("boom"), time.Now())
+	if entry.ProjectID != "alpha" || entry.Name != "explainFile" || entry.Error != "boom" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+}
+
+func TestTruncateAuditTextKeepsRunesWhole(t *testing.T) {
+	value := strings.Repeat("a", auditMaxArgumentBytes-1) + "é" + "tail"
+	got := truncateAuditText(value)
+	if !utf8.ValidString(got) {
+		t.Fatalf("truncated text is not valid UTF-8: %q", got[len(got)-8:])
+	}
+	if want := strings.Repeat("a", auditMaxArgumentBytes-1) + "…"; got != want {
+		t.Fatalf("expected the split rune to be dropped, got ...%q", got[len(got)-8:])
+	}
+	if short := "héllo"; truncateAuditText(short) != short {
+		t.Fatalf("short text should be kept as is")
+	}
+}
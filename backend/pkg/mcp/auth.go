@@ -0,0 +1,285 @@
+/*
+  File: auth.go
+  Purpose: Bearer-token authentication and per-token project/tool scopes for the MCP server.
+  Author: CodeTextor project
+  Notes: Only SHA-256 hashes of token secrets are persisted in the ConfigStore.
+*/
+
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"CodeTextor/backend/pkg/models"
+
+	"github.com/google/uuid"
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	tokenSecretPrefix = "ctx_"
+	tokenPrefixLength = len(tokenSecretPrefix) + 6
+
+	// tokenTouchInterval throttles last-used bookkeeping so every request does not write to SQLite.
+	tokenTouchInterval = time.Minute
+
+	tokenInfoKey = "codetextor.token"
+)
+
+// tokenExpiration is reported to the SDK bearer middleware, which requires one; tokens
+// stay valid until they are revoked.
+var tokenExpiration = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+// CreateToken issues a new API token scoped to the given projects and tools.
+// Empty scopes grant access to every project or tool. The secret is only returned here.
+func (m *Manager) CreateToken(name string, projects, tools []string) (models.MCPAPITokenSecret, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return models.MCPAPITokenSecret{}, fmt.Errorf("token name cannot be empty")
+	}
+	projects = normalizeScope(projects)
+	for _, projectID := range projects {
+		if _, err := m.projectService.GetProject(projectID); err != nil {
+			return models.MCPAPITokenSecret{}, fmt.Errorf("unknown project %s: %w", projectID, err)
+		}
+	}
+	tools = normalizeScope(tools)
+	m.toolsMu.RLock()
+	for _, tool := range tools {
+		if _, ok := m.tools[tool]; !ok {
+			m.toolsMu.RUnlock()
+			return models.MCPAPITokenSecret{}, fmt.Errorf("tool %s not found", tool)
+		}
+	}
+	m.toolsMu.RUnlock()
+
+	secret, err := generateTokenSecret()
+	if err != nil {
+		return models.MCPAPITokenSecret{}, err
+	}
+	token := models.MCPAPIToken{
+		ID:        uuid.NewString(),
+		Name:      name,
+		Prefix:    secret[:tokenPrefixLength],
+		Projects:  projects,
+		Tools:     tools,
+		CreatedAt: time.Now().Unix(),
+	}
+	if err := m.configStore.CreateMCPToken(&token, hashTokenSecret(secret)); err != nil {
+		return models.MCPAPITokenSecret{}, err
+	}
+	return models.MCPAPITokenSecret{Token: token, Secret: secret}, nil
+}
+
+// ListTokens returns the metadata of every issued API token.
+func (m *Manager) ListTokens() ([]models.MCPAPIToken, error) {
+	tokens, err := m.configStore.ListMCPTokens()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]models.MCPAPIToken, 0, len(tokens))
+	for _, token := range tokens {
+		result = append(result, *token)
+	}
+	return result, nil
+}
+
+// RevokeToken deletes an API token; requests using it are rejected immediately.
+func (m *Manager) RevokeToken(id string) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return fmt.Errorf("token id cannot be empty")
+	}
+	return m.configStore.DeleteMCPToken(id)
+}
+
+func generateTokenSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return tokenSecretPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashTokenSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func normalizeScope(values []string) []string {
+	scope := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value != "" && !slices.Contains(scope, value) {
+			scope = append(scope, value)
+		}
+	}
+	return scope
+}
+
+// verifyToken resolves a bearer secret to its stored token for the SDK auth middleware.
+func (m *Manager) verifyToken(_ context.Context, secret string, _ *http.Request) (*auth.TokenInfo, error) {
+	token, err := m.configStore.GetMCPTokenByHash(hashTokenSecret(secret))
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, fmt.Errorf("%w: unknown or revoked token", auth.ErrInvalidToken)
+	}
+
+	now := time.Now()
+	if now.Sub(time.Unix(token.LastUsedAt, 0)) >= tokenTouchInterval {
+		if err := m.configStore.TouchMCPToken(token.ID, now.Unix()); err == nil {
+			token.LastUsedAt = now.Unix()
+		}
+	}
+	return &auth.TokenInfo{
+		Scopes:     token.Tools,
+		Expiration: tokenExpiration,
+		Extra:      map[string]any{tokenInfoKey: token},
+	}, nil
+}
+
+func tokenFromInfo(info *auth.TokenInfo) *models.MCPAPIToken {
+	if info == nil {
+		return nil
+	}
+	token, _ := info.Extra[tokenInfoKey].(*models.MCPAPIToken)
+	return token
+}
+
+// requestToken returns the API token that authenticated an MCP request, if any.
+func requestToken(req sdkmcp.Request) *models.MCPAPIToken {
+	if req == nil {
+		return nil
+	}
+	extra := req.GetExtra()
+	if extra == nil {
+		return nil
+	}
+	return tokenFromInfo(extra.TokenInfo)
+}
+
+func tokenAllowsProject(token *models.MCPAPIToken, projectID string) bool {
+	return token == nil || len(token.Projects) == 0 || slices.Contains(token.Projects, projectID)
+}
+
+func tokenAllowsTool(token *models.MCPAPIToken, name string) bool {
+	return token == nil || len(token.Tools) == 0 || slices.Contains(token.Tools, name)
+}
+
+// resourceTools maps each resource kind to the tool serving the same data; a tool-scoped token
+// may only read resources whose tool it allows.
+var resourceTools = map[string]string{
+	resourceKindFile:    "nodeSource",
+	resourceKindOutline: "outline",
+}
+
+// tokenAllowsResource reports whether a token may read resources of kind. An empty kind stands
+// for listing, which is allowed when any resource kind is.
+func tokenAllowsResource(token *models.MCPAPIToken, kind string) bool {
+	if kind != "" {
+		return tokenAllowsTool(token, resourceTools[kind])
+	}
+	for _, tool := range resourceTools {
+		if tokenAllowsTool(token, tool) {
+			return true
+		}
+	}
+	return false
+}
+
+// authHandler wraps the MCP handler with bearer-token verification and project scoping.
+func (m *Manager) authHandler(next http.Handler) http.Handler {
+	verified := auth.RequireBearerToken(m.verifyToken, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The unbound endpoint filters projects per call; bound paths are checked up front.
+		token := tokenFromInfo(auth.TokenInfoFromContext(r.Context()))
+		if projectID := extractProjectIDFromPath(r.URL.Path); projectID != "" && !tokenAllowsProject(token, projectID) {
+			http.Error(w, fmt.Sprintf("token is not allowed to access project %s", projectID), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	}))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.GetConfig().AllowAnonymous {
+			next.ServeHTTP(w, r)
+			return
+		}
+		verified.ServeHTTP(w, r)
+	})
+}
+
+// toolScopeMiddleware hides and rejects tools, prompts and resources outside the caller's token
+// scope.
+func (m *Manager) toolScopeMiddleware(next sdkmcp.MethodHandler) sdkmcp.MethodHandler {
+	return func(ctx context.Context, method string, req sdkmcp.Request) (sdkmcp.Result, error) {
+		token := requestToken(req)
+		if token == nil || len(token.Tools) == 0 {
+			return next(ctx, method, req)
+		}
+
+		switch method {
+		case "resources/list", "resources/templates/list":
+			if !tokenAllowsResource(token, "") {
+				return nil, fmt.Errorf("token %s is not allowed to access resources", token.Name)
+			}
+		case "resources/read", "resources/subscribe":
+			var uri string
+			switch params := req.GetParams().(type) {
+			case *sdkmcp.ReadResourceParams:
+				uri = params.URI
+			case *sdkmcp.SubscribeParams:
+				uri = params.URI
+			}
+			if _, kind, _, err := parseResourceURI(uri); err == nil && !tokenAllowsResource(token, kind) {
+				return nil, fmt.Errorf("token %s is not allowed to read resource %s", token.Name, uri)
+			}
+		}
+
+		switch params := req.GetParams().(type) {
+		case *sdkmcp.CallToolParamsRaw:
+			if !tokenAllowsTool(token, params.Name) {
+				return nil, fmt.Errorf("token %s is not allowed to call tool %s", token.Name, params.Name)
+			}
+		case *sdkmcp.GetPromptParams:
+			if !tokenAllowsTool(token, params.Name) {
+				return nil, fmt.Errorf("token %s is not allowed to use prompt %s", token.Name, params.Name)
+			}
+		}
+
+		result, err := next(ctx, method, req)
+		if err != nil {
+			return result, err
+		}
+		switch listed := result.(type) {
+		case *sdkmcp.ListToolsResult:
+			allowed := make([]*sdkmcp.Tool, 0, len(listed.Tools))
+			for _, tool := range listed.Tools {
+				if tokenAllowsTool(token, tool.Name) {
+					allowed = append(allowed, tool)
+				}
+			}
+			listed.Tools = allowed
+		case *sdkmcp.ListPromptsResult:
+			allowed := make([]*sdkmcp.Prompt, 0, len(listed.Prompts))
+			for _, prompt := range listed.Prompts {
+				if tokenAllowsTool(token, prompt.Name) {
+					allowed = append(allowed, prompt)
+				}
+			}
+			listed.Prompts = allowed
+		}
+		return result, nil
+	}
+}
@@ -0,0 +1,101 @@
+package mcp
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"CodeTextor/backend/pkg/models"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestGenerateTokenSecretIsUniqueAndHashed(t *testing.T) {
+	first, err := generateTokenSecret()
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+	second, err := generateTokenSecret()
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+	if first == second {
+		t.Fatal("expected distinct secrets")
+	}
+	if !strings.HasPrefix(first, tokenSecretPrefix) {
+		t.Errorf("secret %q is missing prefix %q", first, tokenSecretPrefix)
+	}
+	if hash := hashTokenSecret(first); hash == first || len(hash) != 64 {
+		t.Errorf("unexpected hash %q", hash)
+	}
+}
+
+func TestNormalizeScope(t *testing.T) {
+	got := normalizeScope([]string{" search ", "", "outline", "search"})
+	if want := []string{"search", "outline"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("normalizeScope = %v, want %v", got, want)
+	}
+}
+
+func TestTokenScopes(t *testing.T) {
+	unrestricted := &models.MCPAPIToken{}
+	scoped := &models.MCPAPIToken{Projects: []string{"alpha"}, Tools: []string{"search"}}
+
+	if !tokenAllowsProject(unrestricted, "beta") || !tokenAllowsTool(unrestricted, "outline") {
+		t.Error("empty scopes should allow everything")
+	}
+	if !tokenAllowsProject(scoped, "alpha") || tokenAllowsProject(scoped, "beta") {
+		t.Error("project scope not enforced")
+	}
+	if !tokenAllowsTool(scoped, "search") || tokenAllowsTool(scoped, "nodeSource") {
+		t.Error("tool scope not enforced")
+	}
+}
+
+func TestToolScopeMiddlewareRestrictsResources(t *testing.T) {
+	m := &Manager{}
+	handler := m.toolScopeMiddleware(func(context.Context, string, sdkmcp.Request) (sdkmcp.Result, error) {
+		return &sdkmcp.ReadResourceResult{}, nil
+	})
+	extra := func(token *models.MCPAPIToken) *sdkmcp.RequestExtra {
+		return &sdkmcp.RequestExtra{TokenInfo: &auth.TokenInfo{Extra: map[string]any{tokenInfoKey: token}}}
+	}
+	read := func(token *models.MCPAPIToken, uri string) error {
+		_, err := handler(context.Background(), "resources/read", &sdkmcp.ReadResourceRequest{
+			Params: &sdkmcp.ReadResourceParams{URI: uri},
+			Extra:  extra(token),
+		})
+		return err
+	}
+	list := func(token *models.MCPAPIToken) error {
+		_, err := handler(context.Background(), "resources/list", &sdkmcp.ListResourcesRequest{
+			Params: &sdkmcp.ListResourcesParams{},
+			Extra:  extra(token),
+		})
+		return err
+	}
+	fileURI := resourceURI("alpha", resourceKindFile, "main.go")
+	outlineURI := resourceURI("alpha", resourceKindOutline, "main.go")
+
+	searchOnly := &models.MCPAPIToken{Name: "search", Tools: []string{"search"}}
+	if list(searchOnly) == nil || read(searchOnly, fileURI) == nil || read(searchOnly, outlineURI) == nil {
+		t.Fatal("a search-only token should not list or read resources")
+	}
+
+	outlineOnly := &models.MCPAPIToken{Name: "outline", Tools: []string{"outline"}}
+	if err := list(outlineOnly); err != nil {
+		t.Fatalf("an outline token should list resources: %v", err)
+	}
+	if err := read(outlineOnly, outlineURI); err != nil {
+		t.Fatalf("an outline token should read outlines: %v", err)
+	}
+	if read(outlineOnly, fileURI) == nil {
+		t.Fatal("an outline token should not read file contents")
+	}
+
+	if err := read(&models.MCPAPIToken{Name: "all"}, fileURI); err != nil {
+		t.Fatalf("an unrestricted token should read files: %v", err)
+	}
+}
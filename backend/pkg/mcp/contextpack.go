@@ -0,0 +1,460 @@
+/*
+  File: contextpack.go
+  Purpose: contextPack MCP tool assembling a token-budgeted bundle of snippets, referenced
+           type signatures and file outlines for a task in a single call.
+  Author: CodeTextor project
+  Notes: Token counts use the same 4-characters-per-token heuristic as the chunker.
+*/
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"CodeTextor/backend/pkg/models"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	contextPackDefaultBudget = 4000
+	contextPackMinBudget     = 256
+	contextPackMaxBudget     = 32000
+	contextPackDefaultHits   = 12
+
+	// contextPackSnippetShare is the part of the budget snippets may use before signatures
+	// and outlines are added; leftover budget is filled with the remaining snippets.
+	contextPackSnippetShare = 0.75
+	// contextPackMaxIdentifiers caps the identifiers looked up as referenced types.
+	contextPackMaxIdentifiers = 200
+	contextPackOutlineDepth   = 2
+
+	contextPackKindSnippet   = "snippet"
+	contextPackKindSignature = "signature"
+	contextPackKindOutline   = "outline"
+)
+
+// contextPackTypeKinds are the symbol kinds whose signatures are added for referenced names.
+var contextPackTypeKinds = []string{"class", "struct", "interface", "enum", "type_alias"}
+
+var identifierPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+type contextPackInput struct {
+	Task        string `json:"task" jsonschema_description:"Description of the task the bundle should support"`
+	TokenBudget int    `json:"tokenBudget,omitempty" jsonschema_description:"Approximate token budget for the bundle (256-32000, default 4000)"`
+	K           int    `json:"k,omitempty" jsonschema_description:"Search hits to consider (1-50, default 12)"`
+	ProjectID   string `json:"projectId,omitempty" jsonschema_description:"Project id (required on the unbound /mcp endpoint)"`
+}
+
+type contextPackItem struct {
+	Kind       string  `json:"kind"`
+	Citation   string  `json:"citation"`
+	FilePath   string  `json:"filePath"`
+	StartLine  int     `json:"startLine,omitempty"`
+	EndLine    int     `json:"endLine,omitempty"`
+	Symbol     string  `json:"symbol,omitempty"`
+	SymbolKind string  `json:"symbolKind,omitempty"`
+	ChunkID    string  `json:"chunkId,omitempty"`
+	Score      float64 `json:"score,omitempty"`
+	Tokens     int     `json:"tokens"`
+	Content    string  `json:"content"`
+}
+
+type contextPackOutput struct {
+	ProjectID       string            `json:"projectId"`
+	Task            string            `json:"task"`
+	TokenBudget     int               `json:"tokenBudget"`
+	EstimatedTokens int               `json:"estimatedTokens"`
+	Items           []contextPackItem `json:"items"`
+	Omitted         int               `json:"omitted,omitempty"`
+}
+
+// estimateTokens mirrors the chunker heuristic (1 token ≈ 4 characters).
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+func clampContextBudget(budget int) int {
+	if budget <= 0 {
+		return contextPackDefaultBudget
+	}
+	if budget < contextPackMinBudget {
+		return contextPackMinBudget
+	}
+	if budget > contextPackMaxBudget {
+		return contextPackMaxBudget
+	}
+	return budget
+}
+
+// contextPackBuilder caches per-file data while a bundle is assembled.
+type contextPackBuilder struct {
+	m         *Manager
+	projectID string
+	lines     map[string][]string
+	outlines  map[string][]*models.OutlineNode
+}
+
+func (b *contextPackBuilder) fileLines(path string) []string {
+	if lines, ok := b.lines[path]; ok {
+		return lines
+	}
+	var lines []string
+	if source, err := b.m.projectService.ReadFileContent(b.projectID, path); err == nil {
+		lines = strings.Split(source, "\n")
+	}
+	b.lines[path] = lines
+	return lines
+}
+
+func (b *contextPackBuilder) fileOutline(path string) []*models.OutlineNode {
+	if nodes, ok := b.outlines[path]; ok {
+		return nodes
+	}
+	nodes, err := b.m.projectService.GetFileOutline(b.projectID, path)
+	if err != nil {
+		nodes = nil
+	}
+	b.outlines[path] = nodes
+	return nodes
+}
+
+// sliceLines returns the 1-based inclusive line range, or false when it is unavailable.
+func sliceLines(lines []string, start, end int) (string, bool) {
+	if len(lines) == 0 || start < 1 || end < start || start > len(lines) {
+		return "", false
+	}
+	if end > len(lines) {
+		end = len(lines)
+	}
+	return strings.Join(lines[start-1:end], "\n"), true
+}
+
+// enclosingNode returns the innermost outline node covering the line range.
+func enclosingNode(nodes []*models.OutlineNode, start, end int) *models.OutlineNode {
+	for _, node := range nodes {
+		if int(node.StartLine) <= start && int(node.EndLine) >= end {
+			if inner := enclosingNode(node.Children, start, end); inner != nil {
+				return inner
+			}
+			return node
+		}
+	}
+	return nil
+}
+
+// snippetForHit expands a search hit to its enclosing symbol when that fits maxTokens.
+func (b *contextPackBuilder) snippetForHit(hit *models.Chunk, maxTokens int) contextPackItem {
+	item := contextPackItem{
+		Kind:       contextPackKindSnippet,
+		FilePath:   hit.FilePath,
+		StartLine:  hit.LineStart,
+		EndLine:    hit.LineEnd,
+		Symbol:     hit.SymbolName,
+		SymbolKind: hit.SymbolKind,
+		ChunkID:    hit.ID,
+		Score:      hit.Similarity,
+	}
+	if node := enclosingNode(b.fileOutline(hit.FilePath), hit.LineStart, hit.LineEnd); node != nil {
+		start, end := int(node.StartLine), int(node.EndLine)
+		if content, ok := sliceLines(b.fileLines(hit.FilePath), start, end); ok && estimateTokens(content) <= maxTokens {
+			item.StartLine, item.EndLine = start, end
+			item.Symbol, item.SymbolKind = node.Name, node.Kind
+			item.Content = content
+		}
+	}
+	if item.Content == "" {
+		if content, ok := sliceLines(b.fileLines(hit.FilePath), item.StartLine, item.EndLine); ok {
+			item.Content = content
+		} else if source := strings.TrimSpace(hit.SourceCode); source != "" {
+			item.Content = source
+		} else {
+			item.Content = hit.Content
+		}
+	}
+	return item
+}
+
+// mergeSnippets folds overlapping or adjacent snippets of the same file into one, keeping the
+// best-ranked position, metadata and score. The input must be ordered by rank. Snippets are
+// swept in (file, start line) order so a snippet bridging two blocks joins all three.
+func (b *contextPackBuilder) mergeSnippets(snippets []contextPackItem) []contextPackItem {
+	type rankedSnippet struct {
+		item contextPackItem
+		rank int
+	}
+	byLine := make([]rankedSnippet, len(snippets))
+	for idx, snippet := range snippets {
+		byLine[idx] = rankedSnippet{item: snippet, rank: idx}
+	}
+	sort.SliceStable(byLine, func(i, j int) bool {
+		if byLine[i].item.FilePath != byLine[j].item.FilePath {
+			return byLine[i].item.FilePath < byLine[j].item.FilePath
+		}
+		return byLine[i].item.StartLine < byLine[j].item.StartLine
+	})
+
+	merged := make([]rankedSnippet, 0, len(byLine))
+	for _, next := range byLine {
+		if n := len(merged); n > 0 {
+			current := &merged[n-1]
+			if current.item.FilePath == next.item.FilePath && next.item.StartLine <= current.item.EndLine+1 {
+				start, end := current.item.StartLine, max(current.item.EndLine, next.item.EndLine)
+				content, ok := current.item.Content, true
+				if end != current.item.EndLine {
+					content, ok = sliceLines(b.fileLines(current.item.FilePath), start, end)
+				}
+				if ok {
+					score := max(current.item.Score, next.item.Score)
+					if next.rank < current.rank {
+						current.item, current.rank = next.item, next.rank
+					}
+					current.item.StartLine, current.item.EndLine = start, end
+					current.item.Content, current.item.Score = content, score
+					continue
+				}
+			}
+		}
+		merged = append(merged, next)
+	}
+
+	sort.Slice(merged, func(i, j int) bool { return merged[i].rank < merged[j].rank })
+	result := make([]contextPackItem, len(merged))
+	for idx, snippet := range merged {
+		result[idx] = snippet.item
+	}
+	return result
+}
+
+// referencedIdentifiers collects identifiers used in the snippets, most frequent first,
+// excluding the symbols the snippets already declare.
+func referencedIdentifiers(snippets []contextPackItem) []string {
+	declared := make(map[string]bool)
+	for _, snippet := range snippets {
+		declared[snippet.Symbol] = true
+	}
+	counts := make(map[string]int)
+	for _, snippet := range snippets {
+		for _, ident := range identifierPattern.FindAllString(snippet.Content, -1) {
+			if len(ident) > 2 && !declared[ident] {
+				counts[ident]++
+			}
+		}
+	}
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > contextPackMaxIdentifiers {
+		names = names[:contextPackMaxIdentifiers]
+	}
+	return names
+}
+
+// signatureItems returns one signature per referenced type declared outside the snippets.
+func (b *contextPackBuilder) signatureItems(snippets []contextPackItem) []contextPackItem {
+	names := referencedIdentifiers(snippets)
+	if len(names) == 0 {
+		return nil
+	}
+	chunks, err := b.m.projectService.FindSymbolChunks(b.projectID, names, contextPackTypeKinds)
+	if err != nil {
+		return nil
+	}
+	rank := make(map[string]int, len(names))
+	for idx, name := range names {
+		rank[name] = idx
+	}
+	sort.SliceStable(chunks, func(i, j int) bool { return rank[chunks[i].SymbolName] < rank[chunks[j].SymbolName] })
+
+	seen := make(map[string]bool)
+	items := make([]contextPackItem, 0, len(chunks))
+	for _, chunk := range chunks {
+		if seen[chunk.SymbolName] || coveredBySnippet(snippets, chunk) {
+			continue
+		}
+		signature := strings.TrimSpace(chunk.Signature)
+		if signature == "" {
+			signature, _, _ = strings.Cut(strings.TrimSpace(chunk.SourceCode), "\n")
+		}
+		if signature == "" {
+			continue
+		}
+		seen[chunk.SymbolName] = true
+		items = append(items, contextPackItem{
+			Kind:       contextPackKindSignature,
+			FilePath:   chunk.FilePath,
+			StartLine:  chunk.LineStart,
+			EndLine:    chunk.LineEnd,
+			Symbol:     chunk.SymbolName,
+			SymbolKind: chunk.SymbolKind,
+			ChunkID:    chunk.ID,
+			Content:    signature,
+		})
+	}
+	return items
+}
+
+func coveredBySnippet(snippets []contextPackItem, chunk *models.Chunk) bool {
+	for _, snippet := range snippets {
+		if snippet.FilePath == chunk.FilePath && snippet.StartLine <= chunk.LineStart && snippet.EndLine >= chunk.LineEnd {
+			return true
+		}
+	}
+	return false
+}
+
+// outlineItems returns a shallow outline per file touched by the snippets, in rank order.
+func (b *contextPackBuilder) outlineItems(snippets []contextPackItem) []contextPackItem {
+	seen := make(map[string]bool)
+	items := make([]contextPackItem, 0)
+	for _, snippet := range snippets {
+		if seen[snippet.FilePath] {
+			continue
+		}
+		seen[snippet.FilePath] = true
+		nodes := b.fileOutline(snippet.FilePath)
+		if len(nodes) == 0 {
+			continue
+		}
+		var text strings.Builder
+		writeOutline(&text, limitOutlineDepth(nodes, contextPackOutlineDepth), 0)
+		items = append(items, contextPackItem{
+			Kind:     contextPackKindOutline,
+			FilePath: snippet.FilePath,
+			Content:  strings.TrimRight(text.String(), "\n"),
+		})
+	}
+	return items
+}
+
+func withCitation(item contextPackItem) contextPackItem {
+	item.Citation = item.FilePath
+	if item.StartLine > 0 {
+		item.Citation = fmt.Sprintf("%s:%d-%d", item.FilePath, item.StartLine, item.EndLine)
+	}
+	item.Tokens = estimateTokens(item.Citation) + estimateTokens(item.Content)
+	return item
+}
+
+// fitContextPack selects items within the budget: snippets up to the snippet share, then
+// signatures and outlines, then any remaining snippets. The best snippet is always kept,
+// truncated if needed. Items are returned grouped by kind in rank order.
+func fitContextPack(budget int, snippets, signatures, outlines []contextPackItem) ([]contextPackItem, int, int) {
+	used := 0
+	taken := make(map[*contextPackItem]bool)
+	take := func(item *contextPackItem, limit int) bool {
+		if used+item.Tokens > limit {
+			return false
+		}
+		used += item.Tokens
+		taken[item] = true
+		return true
+	}
+
+	if len(snippets) > 0 && snippets[0].Tokens > budget {
+		first := &snippets[0]
+		maxChars := (budget - estimateTokens(first.Citation)) * 4
+		if maxChars > 0 && maxChars < len(first.Content) {
+			first.Content = strings.ToValidUTF8(first.Content[:maxChars], "")
+		}
+		first.Tokens = estimateTokens(first.Citation) + estimateTokens(first.Content)
+	}
+	snippetLimit := int(float64(budget) * contextPackSnippetShare)
+	for idx := range snippets {
+		limit := snippetLimit
+		if idx == 0 {
+			limit = budget
+		}
+		take(&snippets[idx], limit)
+	}
+	for idx := range signatures {
+		take(&signatures[idx], budget)
+	}
+	for idx := range outlines {
+		take(&outlines[idx], budget)
+	}
+	for idx := range snippets {
+		if !taken[&snippets[idx]] {
+			take(&snippets[idx], budget)
+		}
+	}
+
+	selected := make([]contextPackItem, 0, len(taken))
+	omitted := 0
+	for _, group := range [][]contextPackItem{snippets, signatures, outlines} {
+		for idx := range group {
+			if taken[&group[idx]] {
+				selected = append(selected, group[idx])
+			} else {
+				omitted++
+			}
+		}
+	}
+	return selected, used, omitted
+}
+
+func (m *Manager) handleContextPack(boundProjectID string) sdkmcp.ToolHandlerFor[contextPackInput, contextPackOutput] {
+	return func(_ context.Context, req *sdkmcp.CallToolRequest, input contextPackInput) (*sdkmcp.CallToolResult, contextPackOutput, error) {
+		projectID, err := m.resolveProjectID(req, boundProjectID, input.ProjectID)
+		if err != nil {
+			return nil, contextPackOutput{}, err
+		}
+		task := strings.TrimSpace(input.Task)
+		if task == "" {
+			return nil, contextPackOutput{}, fmt.Errorf("task cannot be empty")
+		}
+		budget := clampContextBudget(input.TokenBudget)
+		k := input.K
+		if k <= 0 {
+			k = contextPackDefaultHits
+		}
+		if k > promptMaxResults {
+			k = promptMaxResults
+		}
+
+		resp, err := m.projectService.Search(projectID, task, k)
+		if err != nil {
+			return nil, contextPackOutput{}, err
+		}
+
+		builder := &contextPackBuilder{
+			m:         m,
+			projectID: projectID,
+			lines:     make(map[string][]string),
+			outlines:  make(map[string][]*models.OutlineNode),
+		}
+		snippets := make([]contextPackItem, 0, len(resp.Chunks))
+		for _, hit := range resp.Chunks {
+			snippets = append(snippets, builder.snippetForHit(hit, budget/3))
+		}
+		snippets = builder.mergeSnippets(snippets)
+		signatures := builder.signatureItems(snippets)
+		outlines := builder.outlineItems(snippets)
+		for _, group := range [][]contextPackItem{snippets, signatures, outlines} {
+			for idx := range group {
+				group[idx] = withCitation(group[idx])
+			}
+		}
+
+		items, used, omitted := fitContextPack(budget, snippets, signatures, outlines)
+		return nil, contextPackOutput{
+			ProjectID:       projectID,
+			Task:            task,
+			TokenBudget:     budget,
+			EstimatedTokens: used,
+			Items:           items,
+			Omitted:         omitted,
+		}, nil
+	}
+}
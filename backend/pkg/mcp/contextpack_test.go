@@ -0,0 +1,142 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"CodeTextor/backend/pkg/models"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const contextPackSource = `package store
+
+type Record struct {
+	ID   string
+	Name string
+}
+
+func Save(r Record) error {
+	if r.ID == "" {
+		return errEmpty
+	}
+	return write(r)
+}
+`
+
+// contextPackProjectService serves a single Go file for contextPack tests.
+type contextPackProjectService struct {
+	*fakeProjectService
+}
+
+func (c *contextPackProjectService) Search(projectID string, _ string, _ int) (*models.SearchResponse, error) {
+	chunks := []*models.Chunk{
+		{ID: "c1", ProjectID: projectID, FilePath: "store.go", LineStart: 9, LineEnd: 11, Similarity: 0.9},
+		{ID: "c2", ProjectID: projectID, FilePath: "store.go", LineStart: 12, LineEnd: 13, Similarity: 0.8},
+	}
+	return &models.SearchResponse{Chunks: chunks, TotalResults: len(chunks)}, nil
+}
+
+func (c *contextPackProjectService) ReadFileContent(string, string) (string, error) {
+	return contextPackSource, nil
+}
+
+func (c *contextPackProjectService) GetFileOutline(string, string) ([]*models.OutlineNode, error) {
+	return []*models.OutlineNode{
+		{ID: "n1", Name: "Record", Kind: "struct", FilePath: "store.go", StartLine: 3, EndLine: 6},
+		{ID: "n2", Name: "Save", Kind: "function", FilePath: "store.go", StartLine: 8, EndLine: 13},
+	}, nil
+}
+
+func (c *contextPackProjectService) FindSymbolChunks(_ string, names, _ []string) ([]*models.Chunk, error) {
+	for _, name := range names {
+		if name == "Record" {
+			return []*models.Chunk{{ID: "t1", FilePath: "types.go", SymbolName: "Record", SymbolKind: "struct", LineStart: 1, LineEnd: 4, SourceCode: "type Record struct {\n\tID string\n}"}}, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestContextPackExpandsAndDedupesHits(t *testing.T) {
+	m := &Manager{projectService: &contextPackProjectService{fakeProjectService: newFakeProjectService()}}
+
+	_, output, err := m.handleContextPack("alpha")(context.Background(), &sdkmcp.CallToolRequest{}, contextPackInput{Task: "save a record"})
+	if err != nil {
+		t.Fatalf("contextPack failed: %v", err)
+	}
+
+	var snippets, signatures, outlines []contextPackItem
+	for _, item := range output.Items {
+		switch item.Kind {
+		case contextPackKindSnippet:
+			snippets = append(snippets, item)
+		case contextPackKindSignature:
+			signatures = append(signatures, item)
+		case contextPackKindOutline:
+			outlines = append(outlines, item)
+		}
+	}
+	if len(snippets) != 1 || snippets[0].Symbol != "Save" || snippets[0].Citation != "store.go:8-13" {
+		t.Fatalf("expected both hits merged into the enclosing Save function, got %+v", snippets)
+	}
+	if !strings.HasPrefix(snippets[0].Content, "func Save(r Record) error {") {
+		t.Fatalf("unexpected snippet content: %q", snippets[0].Content)
+	}
+	if len(signatures) != 1 || signatures[0].Content != "type Record struct {" || signatures[0].Citation != "types.go:1-4" {
+		t.Fatalf("expected the Record signature, got %+v", signatures)
+	}
+	if len(outlines) != 1 || !strings.Contains(outlines[0].Content, "function Save") {
+		t.Fatalf("expected the store.go outline, got %+v", outlines)
+	}
+	if output.EstimatedTokens > output.TokenBudget || output.Omitted != 0 {
+		t.Fatalf("unexpected budget accounting: %+v", output)
+	}
+}
+
+func TestMergeSnippetsJoinsChainedRanges(t *testing.T) {
+	builder := &contextPackBuilder{
+		m:         &Manager{projectService: &contextPackProjectService{fakeProjectService: newFakeProjectService()}},
+		projectID: "alpha",
+		lines:     make(map[string][]string),
+		outlines:  make(map[string][]*models.OutlineNode),
+	}
+	// The lowest-ranked snippet bridges the two blocks ranked above it.
+	snippets := []contextPackItem{
+		{Kind: contextPackKindSnippet, FilePath: "store.go", StartLine: 3, EndLine: 6, Symbol: "Record", Score: 0.7},
+		{Kind: contextPackKindSnippet, FilePath: "other.go", StartLine: 1, EndLine: 2, Score: 0.6},
+		{Kind: contextPackKindSnippet, FilePath: "store.go", StartLine: 10, EndLine: 13, Symbol: "Save", Score: 0.9},
+		{Kind: contextPackKindSnippet, FilePath: "store.go", StartLine: 6, EndLine: 9, Score: 0.5},
+	}
+
+	merged := builder.mergeSnippets(snippets)
+	if len(merged) != 2 || merged[0].FilePath != "store.go" || merged[1].FilePath != "other.go" {
+		t.Fatalf("expected one store.go block ahead of other.go, got %+v", merged)
+	}
+	block := merged[0]
+	if block.StartLine != 3 || block.EndLine != 13 || block.Symbol != "Record" || block.Score != 0.9 {
+		t.Fatalf("expected lines 3-13 with the best-ranked metadata and best score, got %+v", block)
+	}
+	if !strings.HasPrefix(block.Content, "type Record struct {") || !strings.HasSuffix(block.Content, "return write(r)\n}") {
+		t.Fatalf("unexpected merged content: %q", block.Content)
+	}
+}
+
+func TestFitContextPackRespectsBudget(t *testing.T) {
+	snippets := []contextPackItem{
+		withCitation(contextPackItem{Kind: contextPackKindSnippet, FilePath: "a.go", StartLine: 1, EndLine: 100, Content: strings.Repeat("x", 4000)}),
+		withCitation(contextPackItem{Kind: contextPackKindSnippet, FilePath: "b.go", StartLine: 1, EndLine: 10, Content: strings.Repeat("y", 400)}),
+	}
+	outlines := []contextPackItem{withCitation(contextPackItem{Kind: contextPackKindOutline, FilePath: "a.go", Content: "- function A"})}
+
+	items, used, omitted := fitContextPack(300, snippets, nil, outlines)
+	if used > 300 {
+		t.Fatalf("budget exceeded: %d", used)
+	}
+	if len(items) == 0 || items[0].FilePath != "a.go" || items[0].Kind != contextPackKindSnippet {
+		t.Fatalf("expected the best snippet to be kept (truncated), got %+v", items)
+	}
+	if omitted == 0 {
+		t.Fatalf("expected some items to be omitted")
+	}
+}
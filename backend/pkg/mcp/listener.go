@@ -0,0 +1,273 @@
+/*
+  File: listener.go
+  Purpose: TCP, TLS and Unix-domain-socket listeners for the MCP HTTP server.
+  Author: CodeTextor project
+  Notes: Self-signed certificates live under <ConfigDir>/mcp-tls and are regenerated when expired.
+*/
+
+package mcp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"CodeTextor/backend/pkg/models"
+	"CodeTextor/backend/pkg/utils"
+)
+
+const (
+	tlsDirName         = "mcp-tls"
+	selfSignedCertFile = "cert.pem"
+	selfSignedKeyFile  = "key.pem"
+	selfSignedValidity = 365 * 24 * time.Hour
+	defaultSocketName  = "mcp.sock"
+)
+
+// openListener creates the network listener matching the configured protocol.
+func openListener(cfg models.MCPServerConfig) (net.Listener, error) {
+	switch cfg.Protocol {
+	case models.MCPProtocolHTTP:
+		return net.Listen("tcp", fmt.Sprintf("%s:%d", cfg.Host, cfg.Port))
+	case models.MCPProtocolHTTPS:
+		certificate, err := loadServerCertificate(cfg)
+		if err != nil {
+			return nil, err
+		}
+		listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", cfg.Host, cfg.Port))
+		if err != nil {
+			return nil, err
+		}
+		return tls.NewListener(listener, &tls.Config{
+			Certificates: []tls.Certificate{certificate},
+			MinVersion:   tls.VersionTLS12,
+		}), nil
+	case models.MCPProtocolUnix:
+		return listenUnix(cfg)
+	default:
+		return nil, fmt.Errorf("protocol %q is not supported yet", cfg.Protocol)
+	}
+}
+
+// validateListenerConfig checks protocol-specific settings before they are persisted.
+func validateListenerConfig(cfg models.MCPServerConfig) error {
+	switch cfg.Protocol {
+	case models.MCPProtocolHTTP, models.MCPProtocolHTTPS:
+		if strings.TrimSpace(cfg.Host) == "" {
+			return fmt.Errorf("host cannot be empty")
+		}
+		if cfg.Port <= 0 {
+			return fmt.Errorf("port must be positive")
+		}
+	}
+	if cfg.Protocol == models.MCPProtocolHTTPS {
+		certFile := strings.TrimSpace(cfg.TLSCertFile)
+		keyFile := strings.TrimSpace(cfg.TLSKeyFile)
+		if (certFile == "") != (keyFile == "") {
+			return fmt.Errorf("tls cert and key must be provided together")
+		}
+		if certFile != "" {
+			if _, err := tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+				return fmt.Errorf("invalid tls cert/key pair: %w", err)
+			}
+		}
+	}
+	if cfg.Protocol == models.MCPProtocolUnix {
+		if _, err := parseSocketMode(cfg.SocketMode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func loadServerCertificate(cfg models.MCPServerConfig) (tls.Certificate, error) {
+	certFile := strings.TrimSpace(cfg.TLSCertFile)
+	keyFile := strings.TrimSpace(cfg.TLSKeyFile)
+	if certFile == "" && keyFile == "" {
+		var err error
+		certFile, keyFile, err = ensureSelfSignedCertificate(cfg.Host)
+		if err != nil {
+			return tls.Certificate{}, err
+		}
+	}
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to load tls certificate: %w", err)
+	}
+	return certificate, nil
+}
+
+// ensureSelfSignedCertificate returns the stored self-signed pair, generating a new one when
+// it is missing, expired, or does not cover host.
+func ensureSelfSignedCertificate(host string) (string, string, error) {
+	configDir, err := utils.GetConfigDir()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	dir := filepath.Join(configDir, tlsDirName)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", "", fmt.Errorf("failed to create tls directory: %w", err)
+	}
+	certPath := filepath.Join(dir, selfSignedCertFile)
+	keyPath := filepath.Join(dir, selfSignedKeyFile)
+
+	if certificateUsable(certPath, keyPath, host) {
+		return certPath, keyPath, nil
+	}
+	certPEM, keyPEM, err := generateSelfSignedCertificate(host, time.Now())
+	if err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(keyPath, keyPEM, 0o600); err != nil {
+		return "", "", fmt.Errorf("failed to write tls key: %w", err)
+	}
+	if err := os.WriteFile(certPath, certPEM, 0o644); err != nil {
+		return "", "", fmt.Errorf("failed to write tls certificate: %w", err)
+	}
+	return certPath, keyPath, nil
+}
+
+func certificateUsable(certPath, keyPath, host string) bool {
+	pair, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil || len(pair.Certificate) == 0 {
+		return false
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return false
+	}
+	if time.Now().Add(24 * time.Hour).After(leaf.NotAfter) {
+		return false
+	}
+	return leaf.VerifyHostname(certificateHost(host)) == nil
+}
+
+// certificateHost maps wildcard bind addresses to the loopback name clients will dial.
+func certificateHost(host string) string {
+	host = strings.TrimSpace(host)
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		return "localhost"
+	}
+	return host
+}
+
+func generateSelfSignedCertificate(host string, now time.Time) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate tls key: %w", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate certificate serial: %w", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "CodeTextor MCP", Organization: []string{"CodeTextor"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(selfSignedValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	if name := certificateHost(host); name != "localhost" {
+		if ip := net.ParseIP(name); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, name)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode tls key: %w", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, nil
+}
+
+// resolveSocketPath returns the configured socket path or the default under the config dir.
+func resolveSocketPath(cfg models.MCPServerConfig) (string, error) {
+	if socketPath := strings.TrimSpace(cfg.SocketPath); socketPath != "" {
+		return socketPath, nil
+	}
+	configDir, err := utils.GetConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(configDir, defaultSocketName), nil
+}
+
+func parseSocketMode(value string) (os.FileMode, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		value = models.DefaultMCPServerConfig().SocketMode
+	}
+	mode, err := strconv.ParseUint(value, 8, 32)
+	if err != nil || mode > 0o777 {
+		return 0, fmt.Errorf("invalid socket mode %q: expected an octal permission like 0600", value)
+	}
+	return os.FileMode(mode), nil
+}
+
+// listenUnix binds the Unix socket, replacing a stale socket file left by a previous run.
+func listenUnix(cfg models.MCPServerConfig) (net.Listener, error) {
+	socketPath, err := resolveSocketPath(cfg)
+	if err != nil {
+		return nil, err
+	}
+	mode, err := parseSocketMode(cfg.SocketMode)
+	if err != nil {
+		return nil, err
+	}
+
+	if info, err := os.Lstat(socketPath); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", socketPath)
+		}
+		if conn, err := net.Dial("unix", socketPath); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("socket %s is already in use", socketPath)
+		}
+		if err := os.Remove(socketPath); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	// Create the socket owner-only, then widen it to the configured mode.
+	var listener net.Listener
+	err = withSocketUmask(func() error {
+		listener, err = net.Listen("unix", socketPath)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(socketPath, mode); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to set socket permissions: %w", err)
+	}
+	return listener, nil
+}
@@ -0,0 +1,108 @@
+package mcp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"CodeTextor/backend/pkg/models"
+)
+
+func TestGenerateSelfSignedCertificateCoversHost(t *testing.T) {
+	certPEM, keyPEM, err := generateSelfSignedCertificate("192.168.1.20", time.Now())
+	if err != nil {
+		t.Fatalf("generate certificate: %v", err)
+	}
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("load generated pair: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	for _, host := range []string{"localhost", "127.0.0.1", "192.168.1.20"} {
+		if err := leaf.VerifyHostname(host); err != nil {
+			t.Errorf("certificate does not cover %s: %v", host, err)
+		}
+	}
+}
+
+func TestParseSocketMode(t *testing.T) {
+	if mode, err := parseSocketMode(""); err != nil || mode != 0o600 {
+		t.Errorf("default mode = %o, %v", mode, err)
+	}
+	if mode, err := parseSocketMode("0660"); err != nil || mode != 0o660 {
+		t.Errorf("parsed mode = %o, %v", mode, err)
+	}
+	for _, invalid := range []string{"rw", "0999", "17777"} {
+		if _, err := parseSocketMode(invalid); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
+
+func TestListenUnixAppliesModeAndReplacesStaleSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix socket permissions are not enforced on windows")
+	}
+	socketPath := filepath.Join(t.TempDir(), "mcp.sock")
+	cfg := models.MCPServerConfig{Protocol: models.MCPProtocolUnix, SocketPath: socketPath, SocketMode: "0600"}
+
+	listener, err := listenUnix(cfg)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	info, err := os.Stat(socketPath)
+	if err != nil {
+		t.Fatalf("stat socket: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("socket mode = %o, want 600", perm)
+	}
+	if _, err := listenUnix(cfg); err == nil {
+		t.Error("expected error while the socket is in use")
+	}
+	listener.Close()
+
+	listener, err = listenUnix(cfg)
+	if err != nil {
+		t.Fatalf("relisten: %v", err)
+	}
+	listener.Close()
+}
+
+func TestWithSocketUmaskCreatesOwnerOnlyFiles(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("windows has no umask")
+	}
+	dir := t.TempDir()
+	create := func(name string) os.FileMode {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, nil, 0o666); err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("stat %s: %v", name, err)
+		}
+		return info.Mode().Perm()
+	}
+
+	before := create("before")
+	var inside os.FileMode
+	if err := withSocketUmask(func() error { inside = create("inside"); return nil }); err != nil {
+		t.Fatalf("withSocketUmask: %v", err)
+	}
+	if inside != 0o600 {
+		t.Errorf("file created under the socket umask has mode %o, want 600", inside)
+	}
+	if after := create("after"); after != before {
+		t.Errorf("umask not restored: mode %o after, %o before", after, before)
+	}
+}
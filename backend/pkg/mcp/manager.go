@@ -34,8 +34,15 @@ const (
 	serverTitle      = "CodeTextor project context server"
 	serverVersion    = "0.1.0"
 
+	// enabledToolsKey persists opt-in tools (disabled by default) that the user switched on.
+	enabledToolsKey = "mcp_enabled_tools"
+
 	statusEventName = "mcp:status"
 	toolsEventName  = "mcp:tools"
+
+	// serverWriteTimeout bounds each response; event streams and waiting tool calls are
+	// exempted or extended by newTransportHandler.
+	serverWriteTimeout = 60 * time.Second
 )
 
 // Manager coordinates the MCP server lifecycle and tool registration.
@@ -47,7 +54,7 @@ type Manager struct {
 	configMu sync.RWMutex
 
 	server   *sdkmcp.Server
-	handler  *sdkmcp.StreamableHTTPHandler
+	handler  http.Handler
 	httpSrv  *http.Server
 	listener net.Listener
 
@@ -61,10 +68,15 @@ type Manager struct {
 	toolsMu        sync.RWMutex
 	tools          map[string]*toolState
 	disabledTools  map[string]bool
+	enabledTools   map[string]bool
 	totalRequests  int64
 	totalDuration  time.Duration
 	metricsMu      sync.Mutex
 	activeHTTPConn int64
+	auditInserts   int64
+	limiter        *rateLimiter
+	// sessionTokens maps each *sdkmcp.ServerSession to the token it authenticated with.
+	sessionTokens sync.Map
 
 	eventEmitter       func(string, interface{})
 	statusTickerCancel context.CancelFunc
@@ -72,10 +84,13 @@ type Manager struct {
 
 type toolState struct {
 	name        string
+	kind        string
 	description string
 	enabled     bool
 	register    func(*sdkmcp.Server, string)
 	callCount   int64
+	// optIn tools are disabled until explicitly enabled through ToggleTool.
+	optIn bool
 }
 
 // NewManager creates a manager bound to the given project service.
@@ -94,17 +109,20 @@ func NewManager(projectService services.ProjectServiceAPI, emitter func(string,
 		configStore:    configStore,
 		tools:          make(map[string]*toolState),
 		disabledTools:  make(map[string]bool),
+		enabledTools:   make(map[string]bool),
 		eventEmitter:   emitter,
+		limiter:        newRateLimiter(),
 	}
 	if err := m.loadConfig(); err != nil {
 		configStore.Close()
 		return nil, err
 	}
-	if err := m.loadDisabledTools(); err != nil {
+	if err := m.loadToolStates(); err != nil {
 		configStore.Close()
 		return nil, err
 	}
 	m.initTools()
+	projectService.AddEventListener(m.handleProjectEvent)
 	return m, nil
 }
 
@@ -129,37 +147,34 @@ func (m *Manager) Start(ctx context.Context) error {
 		m.configMu.Unlock()
 		return nil
 	}
-	if m.config.Protocol != models.MCPProtocolHTTP {
-		m.configMu.Unlock()
-		return fmt.Errorf("protocol %q is not supported yet", m.config.Protocol)
-	}
-
 	if err := m.buildServerLocked(); err != nil {
 		m.lastError.Store(err.Error())
 		m.configMu.Unlock()
 		return err
 	}
 
-	addr := fmt.Sprintf("%s:%d", m.config.Host, m.config.Port)
-	listener, err := net.Listen("tcp", addr)
+	listener, err := openListener(m.config)
 	if err != nil {
 		m.lastError.Store(err.Error())
+		m.configMu.Unlock()
 		return err
 	}
 	if m.config.MaxConnections > 0 {
 		listener = netutil.LimitListener(listener, m.config.MaxConnections)
 	}
 
+	// Request contexts derive from serveCtx so Stop also ends long-lived event streams.
+	serveCtx, cancel := context.WithCancel(context.Background())
 	m.listener = listener
 	m.httpSrv = &http.Server{
 		Handler:           m.handler,
+		BaseContext:       func(net.Listener) context.Context { return serveCtx },
 		ReadHeaderTimeout: 15 * time.Second,
-		WriteTimeout:      60 * time.Second,
+		WriteTimeout:      serverWriteTimeout,
 		IdleTimeout:       120 * time.Second,
 		ConnState:         m.handleConnState,
 	}
 
-	_, cancel := context.WithCancel(context.Background())
 	m.serverCancel = cancel
 	m.running = true
 	m.startTime = time.Now()
@@ -189,6 +204,8 @@ func (m *Manager) Start(ctx context.Context) error {
 		m.Stop(context.Background())
 	}()
 
+	go m.pruneAuditLog()
+
 	m.emitStatus()
 	m.emitTools()
 	m.startStatusTicker()
@@ -243,18 +260,35 @@ func (m *Manager) GetConfig() models.MCPServerConfig {
 
 // UpdateConfig persists the provided configuration.
 func (m *Manager) UpdateConfig(cfg models.MCPServerConfig) (models.MCPServerConfig, error) {
-	if strings.TrimSpace(cfg.Host) == "" {
-		return models.MCPServerConfig{}, fmt.Errorf("host cannot be empty")
-	}
-	if cfg.Port <= 0 {
-		return models.MCPServerConfig{}, fmt.Errorf("port must be positive")
-	}
 	if cfg.MaxConnections <= 0 {
 		cfg.MaxConnections = models.DefaultMCPServerConfig().MaxConnections
 	}
 	if cfg.Protocol == "" {
 		cfg.Protocol = models.MCPProtocolHTTP
 	}
+	if cfg.MaxConcurrentEmbeddingCalls < 0 {
+		cfg.MaxConcurrentEmbeddingCalls = 0
+	}
+	for name, limit := range cfg.ToolRateLimits {
+		if limit.RequestsPerMinute < 0 || limit.Burst < 0 {
+			return models.MCPServerConfig{}, fmt.Errorf("rate limit for %s cannot be negative", name)
+		}
+	}
+	if cfg.ClientRateLimit.RequestsPerMinute < 0 || cfg.ClientRateLimit.Burst < 0 {
+		return models.MCPServerConfig{}, fmt.Errorf("client rate limit cannot be negative")
+	}
+	if cfg.AuditRetentionDays < 0 {
+		cfg.AuditRetentionDays = 0
+	}
+	if cfg.AuditMaxEntries < 0 {
+		cfg.AuditMaxEntries = 0
+	}
+	if strings.TrimSpace(cfg.SocketMode) == "" {
+		cfg.SocketMode = models.DefaultMCPServerConfig().SocketMode
+	}
+	if err := validateListenerConfig(cfg); err != nil {
+		return models.MCPServerConfig{}, err
+	}
 
 	m.configMu.Lock()
 	defer m.configMu.Unlock()
@@ -303,18 +337,22 @@ func (m *Manager) GetTools() []models.MCPTool {
 	for _, state := range m.tools {
 		tools = append(tools, models.MCPTool{
 			Name:        state.name,
+			Kind:        state.kind,
 			Description: state.description,
 			Enabled:     state.enabled,
 			CallCount:   state.callCount,
 		})
 	}
 	sort.Slice(tools, func(i, j int) bool {
+		if tools[i].Kind != tools[j].Kind {
+			return tools[i].Kind > tools[j].Kind
+		}
 		return tools[i].Name < tools[j].Name
 	})
 	return tools
 }
 
-// ToggleTool flips the enabled state of a tool.
+// ToggleTool flips the enabled state of a tool or prompt.
 func (m *Manager) ToggleTool(name string) error {
 	m.toolsMu.Lock()
 	state, ok := m.tools[name]
@@ -324,8 +362,15 @@ func (m *Manager) ToggleTool(name string) error {
 	}
 
 	state.enabled = !state.enabled
-	m.disabledTools[name] = !state.enabled
-	if err := m.persistDisabledTools(); err != nil {
+	var err error
+	if state.optIn {
+		m.enabledTools[name] = state.enabled
+		err = persistToolList(m.configStore, enabledToolsKey, m.enabledTools)
+	} else {
+		m.disabledTools[name] = !state.enabled
+		err = persistToolList(m.configStore, disabledToolsKey, m.disabledTools)
+	}
+	if err != nil {
 		m.toolsMu.Unlock()
 		return err
 	}
@@ -333,24 +378,39 @@ func (m *Manager) ToggleTool(name string) error {
 
 	m.configMu.Lock()
 	if m.server != nil {
-		m.server.RemoveTools(name)
-		if state.enabled {
-			state.register(m.server, "")
-		}
+		applyToolState(m.server, state, "")
 	}
 	m.configMu.Unlock()
 
+	m.serverCache.Lock()
+	for projectID, srv := range m.boundServers {
+		applyToolState(srv, state, projectID)
+	}
+	m.serverCache.Unlock()
+
 	m.emitTools()
 	return nil
 }
 
+// applyToolState removes a tool or prompt from a live server and re-registers it when enabled.
+func applyToolState(s *sdkmcp.Server, state *toolState, boundProjectID string) {
+	if state.kind == toolKindPrompt {
+		s.RemovePrompts(state.name)
+	} else {
+		s.RemoveTools(state.name)
+	}
+	if state.enabled && state.register != nil {
+		state.register(s, boundProjectID)
+	}
+}
+
 func (m *Manager) buildServerLocked() error {
 	m.server = m.buildServer("")
 	m.boundServers = make(map[string]*sdkmcp.Server)
-	m.handler = sdkmcp.NewStreamableHTTPHandler(func(r *http.Request) *sdkmcp.Server {
+	m.handler = m.authHandler(newTransportHandler(func(r *http.Request) *sdkmcp.Server {
 		projectID := extractProjectIDFromPath(r.URL.Path)
 		return m.getServerForProject(projectID)
-	}, nil)
+	}))
 	return nil
 }
 
@@ -360,10 +420,10 @@ func extractProjectIDFromPath(path string) string {
 		return ""
 	}
 	parts := strings.Split(clean, "/")
-	if len(parts) >= 2 && strings.EqualFold(parts[0], "mcp") {
-		return parts[1]
-	}
-	if len(parts) == 1 && strings.EqualFold(parts[0], "mcp") {
+	if strings.EqualFold(parts[0], "mcp") || strings.EqualFold(parts[0], ssePathPrefix) {
+		if len(parts) >= 2 {
+			return parts[1]
+		}
 		return ""
 	}
 	return parts[0]
@@ -394,9 +454,18 @@ func (m *Manager) buildServer(boundProjectID string) *sdkmcp.Server {
 		Version: serverVersion,
 	}
 	opts := &sdkmcp.ServerOptions{
-		Instructions: m.buildServerInstructions(boundProjectID),
+		Instructions:       m.buildServerInstructions(boundProjectID),
+		SubscribeHandler:   m.handleSubscribe(boundProjectID),
+		UnsubscribeHandler: m.handleUnsubscribe,
 	}
 	s := sdkmcp.NewServer(impl, opts)
+	m.registerResources(s, boundProjectID)
+	// Later middleware wraps earlier ones, so calls flow session tracking -> audit -> token
+	// scope -> rate limits and the audit log also records rejected calls.
+	s.AddReceivingMiddleware(m.rateLimitMiddleware)
+	s.AddReceivingMiddleware(m.toolScopeMiddleware)
+	s.AddReceivingMiddleware(m.auditMiddleware(boundProjectID))
+	s.AddReceivingMiddleware(m.sessionTokenMiddleware)
 
 	m.toolsMu.RLock()
 	for _, state := range m.tools {
@@ -412,7 +481,7 @@ func (m *Manager) buildServer(boundProjectID string) *sdkmcp.Server {
 func describeForProject(base, projectID string) string {
 	projectID = strings.TrimSpace(projectID)
 	if projectID == "" {
-		return fmt.Sprintf("%s - call via /mcp/<projectId> to bind a project", base)
+		return fmt.Sprintf("%s - pass projectId (see listProjects) or call via /mcp/<projectId>", base)
 	}
 	return fmt.Sprintf("%s - project: %s", base, projectID)
 }
@@ -420,18 +489,24 @@ func describeForProject(base, projectID string) string {
 func (m *Manager) buildServerInstructions(boundProjectID string) string {
 	var b strings.Builder
 
-	b.WriteString("CodeTextor MCP serves read-only code context from the local index (Tree-sitter chunks + SQLite-vec embeddings) over streamable HTTP. ")
+	b.WriteString("CodeTextor MCP serves read-only code context from the local index (Tree-sitter chunks + SQLite-vec embeddings) over streamable HTTP (legacy SSE at /sse). ")
 	projectLabel := strings.TrimSpace(m.projectLabel(boundProjectID))
 	if projectLabel != "" {
 		b.WriteString(fmt.Sprintf("This session is bound to project %s. ", projectLabel))
 	} else {
-		b.WriteString("Call the endpoint as /mcp/<projectId> to bind requests to a project; tool calls without a projectId will fail. ")
+		b.WriteString("This session is not bound to a project: call listProjects first and pass projectId to outline, nodeSource and prompts; search without projectId queries every project and labels each result with its projectId. Alternatively call the endpoint as /mcp/<projectId> to bind a project. ")
 	}
 	b.WriteString("Use tools instead of asking for raw files to save tokens: start with search to find candidates, outline to map a file, then nodeSource to fetch the minimal snippet. Avoid requesting entire files; responses are short and read-only. ")
 	b.WriteString("Tools: search - semantic retrieval of indexed chunks (natural-language query, optional k to control results, default 8, max 50). ")
 	b.WriteString("outline - hierarchical outline for a file path relative to the project root; depth trims nested children to keep responses short. ")
 	b.WriteString("nodeSource - canonical code snippet and metadata for a chunk or outline node id returned by search/outline; use collapseBody to shorten large blocks. ")
-	b.WriteString("All tools are read-only; use them to ground model answers without modifying the codebase.")
+	b.WriteString("similar - \"more like this\": chunks closest to an existing chunk id or symbol (no query text); scope=otherFiles or otherPackages finds duplicates elsewhere. ")
+	b.WriteString("contextPack - one call that returns a token-budgeted bundle for a task (expanded snippets, referenced type signatures, outlines, each with a path:lines citation); prefer it over chaining search/nodeSource when starting a task. ")
+	b.WriteString("indexStatus - current indexing progress; pass wait=true to block until a running pass finishes before trusting search results. Call logging/setLevel to receive indexer notifications when files are re-indexed. ")
+	b.WriteString("Resources: codetextor://<projectId>/file/<path> returns raw file contents and codetextor://<projectId>/outline/<path> the JSON outline; subscribe to receive updates when a file is re-indexed. ")
+	b.WriteString("Prompts: explainFile, findFeature and reviewChanges return ready-made requests pre-filled with outlines and search hits from the project. ")
+	b.WriteString("reindexFiles and reindexProject (only listed when enabled in CodeTextor) refresh the index after you edit files; pass wait=true to block until the new index is ready. ")
+	b.WriteString("No tool modifies the codebase; use them to ground model answers.")
 	return b.String()
 }
 
@@ -459,16 +534,55 @@ func (m *Manager) initTools() {
 	m.tools = map[string]*toolState{
 		"search": {
 			name:        "search",
+			kind:        toolKindTool,
 			description: "Semantic search across indexed code chunks; start here to locate relevant code before requesting snippets",
 		},
 		"outline": {
 			name:        "outline",
+			kind:        toolKindTool,
 			description: "Hierarchical outline for a file path relative to the project root; use to narrow where to read",
 		},
 		"nodeSource": {
 			name:        "nodeSource",
+			kind:        toolKindTool,
 			description: "Return canonical source for a chunk or outline node id; use after search/outline instead of whole files",
 		},
+		"contextPack": {
+			name:        "contextPack",
+			kind:        toolKindTool,
+			description: "One-call context bundle for a task: search hits expanded to enclosing symbols, referenced type signatures and file outlines, deduplicated and fitted to a token budget with citations",
+		},
+		"similar": {
+			name:        "similar",
+			kind:        toolKindTool,
+			description: "Find code similar to an indexed chunk or symbol using its stored embedding; useful for spotting duplicates and refactoring candidates",
+		},
+		"listProjects": {
+			name:        "listProjects",
+			kind:        toolKindTool,
+			description: "List the projects reachable from this endpoint; pass their ids as projectId to the other tools",
+		},
+		"indexStatus": {
+			name:        "indexStatus",
+			kind:        toolKindTool,
+			description: "Current indexing progress; set wait to block until a running pass finishes so later searches see fresh results",
+		},
+		"reindexFiles": {
+			name:        "reindexFiles",
+			kind:        toolKindTool,
+			optIn:       true,
+			description: "Re-index specific files (paths relative to the project root) after editing them so search and outline see the changes",
+		},
+		"reindexProject": {
+			name:        "reindexProject",
+			kind:        toolKindTool,
+			optIn:       true,
+			description: "Clear and rebuild the whole project index; expensive, prefer reindexFiles for a few edited files",
+		},
+	}
+
+	for name, state := range promptDefinitions() {
+		m.tools[name] = state
 	}
 
 	for name, state := range m.tools {
@@ -506,12 +620,66 @@ func (m *Manager) initTools() {
 					Description: desc,
 				}, wrapTool(m, "nodeSource", m.handleNodeSource(boundProjectID)))
 			}
+		case "contextPack":
+			state.register = func(s *sdkmcp.Server, boundProjectID string) {
+				desc := describeForProject(state.description, m.projectLabel(boundProjectID))
+				sdkmcp.AddTool(s, &sdkmcp.Tool{
+					Name:        "contextPack",
+					Description: desc,
+				}, wrapTool(m, "contextPack", m.handleContextPack(boundProjectID)))
+			}
+		case "similar":
+			state.register = func(s *sdkmcp.Server, boundProjectID string) {
+				desc := describeForProject(state.description, m.projectLabel(boundProjectID))
+				sdkmcp.AddTool(s, &sdkmcp.Tool{
+					Name:        "similar",
+					Description: desc,
+				}, wrapTool(m, "similar", m.handleSimilar(boundProjectID)))
+			}
+		case "listProjects":
+			state.register = func(s *sdkmcp.Server, boundProjectID string) {
+				if strings.TrimSpace(boundProjectID) != "" {
+					return
+				}
+				sdkmcp.AddTool(s, &sdkmcp.Tool{
+					Name:        "listProjects",
+					Description: state.description,
+				}, wrapTool(m, "listProjects", m.handleListProjects(boundProjectID)))
+			}
+		case "indexStatus":
+			state.register = func(s *sdkmcp.Server, boundProjectID string) {
+				desc := describeForProject(state.description, m.projectLabel(boundProjectID))
+				sdkmcp.AddTool(s, &sdkmcp.Tool{
+					Name:        "indexStatus",
+					Description: desc,
+				}, wrapTool(m, "indexStatus", m.handleIndexStatus(boundProjectID)))
+			}
+		case "reindexFiles":
+			state.register = func(s *sdkmcp.Server, boundProjectID string) {
+				desc := describeForProject(state.description, m.projectLabel(boundProjectID))
+				sdkmcp.AddTool(s, &sdkmcp.Tool{
+					Name:        "reindexFiles",
+					Description: desc,
+				}, wrapTool(m, "reindexFiles", m.handleReindexFiles(boundProjectID)))
+			}
+		case "reindexProject":
+			state.register = func(s *sdkmcp.Server, boundProjectID string) {
+				desc := describeForProject(state.description, m.projectLabel(boundProjectID))
+				sdkmcp.AddTool(s, &sdkmcp.Tool{
+					Name:        "reindexProject",
+					Description: desc,
+				}, wrapTool(m, "reindexProject", m.handleReindexProject(boundProjectID)))
+			}
+		default:
+			if state.kind == toolKindPrompt {
+				state.register = m.promptRegistration(name, state)
+			}
 		}
 
-		if disabled := m.disabledTools[name]; disabled {
-			state.enabled = false
+		if state.optIn {
+			state.enabled = m.enabledTools[name]
 		} else {
-			state.enabled = true
+			state.enabled = !m.disabledTools[name]
 		}
 	}
 
@@ -579,57 +747,110 @@ func (m *Manager) loadConfig() error {
 	return nil
 }
 
-func (m *Manager) persistDisabledTools() error {
-	disabled := make([]string, 0, len(m.disabledTools))
-	for name, state := range m.disabledTools {
+// persistToolList stores the names flagged true in states under key.
+func persistToolList(configStore *store.ConfigStore, key string, states map[string]bool) error {
+	names := make([]string, 0, len(states))
+	for name, state := range states {
 		if state {
-			disabled = append(disabled, name)
+			names = append(names, name)
 		}
 	}
-	payload, err := json.Marshal(disabled)
+	payload, err := json.Marshal(names)
 	if err != nil {
 		return err
 	}
-	return m.configStore.SetValue(disabledToolsKey, string(payload))
+	return configStore.SetValue(key, string(payload))
 }
 
-func (m *Manager) loadDisabledTools() error {
-	value, ok, err := m.configStore.GetValue(disabledToolsKey)
+// loadToolList reads a tool name list stored by persistToolList.
+func loadToolList(configStore *store.ConfigStore, key string) (map[string]bool, error) {
+	value, ok, err := configStore.GetValue(key)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	states := make(map[string]bool)
 	if !ok || strings.TrimSpace(value) == "" {
-		m.disabledTools = make(map[string]bool)
-		return nil
+		return states, nil
 	}
 
 	var list []string
 	if err := json.Unmarshal([]byte(value), &list); err != nil {
-		return err
+		return nil, err
 	}
-	m.disabledTools = make(map[string]bool, len(list))
 	for _, name := range list {
-		m.disabledTools[name] = true
+		states[name] = true
+	}
+	return states, nil
+}
+
+func (m *Manager) loadToolStates() error {
+	disabled, err := loadToolList(m.configStore, disabledToolsKey)
+	if err != nil {
+		return err
+	}
+	enabled, err := loadToolList(m.configStore, enabledToolsKey)
+	if err != nil {
+		return err
 	}
+	m.disabledTools = disabled
+	m.enabledTools = enabled
 	return nil
 }
 
 // --- Tool handlers ---------------------------------------------------------
 
 type searchInput struct {
-	Query string `json:"query" jsonschema_description:"Natural language search across the indexed project"`
-	K     int    `json:"k,omitempty" jsonschema_description:"Max chunks to return (1-50, default 8)" jsonschema_extras:"minimum=1,maximum=50"`
+	Query     string `json:"query" jsonschema_description:"Natural language search across the indexed project"`
+	K         int    `json:"k,omitempty" jsonschema_description:"Max chunks to return (1-50, default 8)" jsonschema_extras:"minimum=1,maximum=50"`
+	ProjectID string `json:"projectId,omitempty" jsonschema_description:"Project to search on the unbound /mcp endpoint; omit to search every accessible project"`
+	// Diversification options, mirrored from models.SearchRequest.
+	MMR             bool    `json:"mmr,omitempty" jsonschema_description:"Diversify results with maximal marginal relevance so near-duplicate chunks do not crowd out others"`
+	MMRLambda       float64 `json:"mmrLambda,omitempty" jsonschema_description:"MMR trade-off between relevance (1.0) and diversity (0.0); default 0.7" jsonschema_extras:"minimum=0,maximum=1"`
+	MaxPerFile      int     `json:"maxPerFile,omitempty" jsonschema_description:"Maximum results from the same file (0 = unlimited)" jsonschema_extras:"minimum=0"`
+	MergeContiguous bool    `json:"mergeContiguous,omitempty" jsonschema_description:"Merge overlapping or adjacent hits from one file into a single line range"`
+	MinScore        float64 `json:"minScore,omitempty" jsonschema_description:"Drop chunks whose cosine similarity is below this value (0-1); totalResults counts the chunks above it" jsonschema_extras:"minimum=0,maximum=1"`
+	Cursor          string  `json:"cursor,omitempty" jsonschema_description:"nextCursor from a previous call with the same query and options, to fetch the following page (single-project searches only)"`
+	EmbeddingModel  string  `json:"embeddingModel,omitempty" jsonschema_description:"Search with one of the project's embedding models (its primary or an additional model); omit for the primary model"`
+	FuseModels      bool    `json:"fuseModels,omitempty" jsonschema_description:"Search with every embedding model of the project and fuse the rankings (reciprocal rank fusion)"`
+}
+
+// searchRequest converts tool input into a service search request for one project.
+func (in searchInput) searchRequest(projectID string, k int) models.SearchRequest {
+	return models.SearchRequest{
+		ProjectID:       projectID,
+		Query:           in.Query,
+		K:               k,
+		MMR:             in.MMR,
+		MMRLambda:       in.MMRLambda,
+		MaxPerFile:      in.MaxPerFile,
+		MergeContiguous: in.MergeContiguous,
+		MinScore:        in.MinScore,
+		Cursor:          in.Cursor,
+		EmbeddingModel:  in.EmbeddingModel,
+		FuseModels:      in.FuseModels,
+	}
 }
 
 type searchOutput struct {
-	Results      []*models.Chunk `json:"results"`
-	TotalResults int             `json:"totalResults"`
-	QueryTimeMs  int64           `json:"queryTimeMs"`
+	Results      []*models.Chunk   `json:"results"`
+	TotalResults int               `json:"totalResults"`
+	QueryTimeMs  int64             `json:"queryTimeMs"`
+	Projects     []string          `json:"projects,omitempty"`
+	Skipped      map[string]string `json:"skipped,omitempty"`
+	// Offset and NextCursor page through single-project results.
+	Offset     int    `json:"offset,omitempty"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	// Rerank reports the cross-encoder stage when the searched project enables it.
+	Rerank *models.RerankStats `json:"rerank,omitempty"`
+	// EmbeddingModels lists the models that ranked single-project results.
+	EmbeddingModels []string `json:"embeddingModels,omitempty"`
 }
 
 type outlineInput struct {
 	Path  string `json:"path" jsonschema_description:"File path relative to the project root (e.g. src/main.go)"`
 	Depth int    `json:"depth,omitempty" jsonschema_description:"Optional depth limit; 1 returns only top-level nodes"`
+	// ProjectID selects the project on the unbound /mcp endpoint.
+	ProjectID string `json:"projectId,omitempty" jsonschema_description:"Project id (required on the unbound /mcp endpoint)"`
 }
 
 type outlineOutput struct {
@@ -639,6 +860,7 @@ type outlineOutput struct {
 type nodeSourceInput struct {
 	ID           string `json:"id" jsonschema_description:"Chunk or outline node id returned by search/outline"`
 	CollapseBody bool   `json:"collapseBody,omitempty" jsonschema_description:"If true, shortens large node bodies"`
+	ProjectID    string `json:"projectId,omitempty" jsonschema_description:"Project id (required on the unbound /mcp endpoint)"`
 }
 
 type nodeSourceOutput struct {
@@ -652,20 +874,8 @@ type nodeSourceOutput struct {
 	SymbolKind string `json:"symbolKind,omitempty"`
 }
 
-func (m *Manager) resolveProjectID(boundProjectID string) (string, error) {
-	projectID := strings.TrimSpace(boundProjectID)
-	if projectID != "" {
-		return projectID, nil
-	}
-	return "", fmt.Errorf("projectId is required; call the MCP server via /mcp/<projectId>")
-}
-
 func (m *Manager) handleSearch(boundProjectID string) sdkmcp.ToolHandlerFor[searchInput, searchOutput] {
 	return func(ctx context.Context, req *sdkmcp.CallToolRequest, input searchInput) (*sdkmcp.CallToolResult, searchOutput, error) {
-		projectID, err := m.resolveProjectID(boundProjectID)
-		if err != nil {
-			return nil, searchOutput{}, err
-		}
 		k := input.K
 		if k <= 0 {
 			k = 8
@@ -673,21 +883,34 @@ func (m *Manager) handleSearch(boundProjectID string) sdkmcp.ToolHandlerFor[sear
 		if k > 50 {
 			k = 50
 		}
-		resp, err := m.projectService.Search(projectID, input.Query, k)
+		if strings.TrimSpace(boundProjectID) == "" && strings.TrimSpace(input.ProjectID) == "" {
+			output, err := m.searchAcrossProjects(req, input, k)
+			return nil, output, err
+		}
+		projectID, err := m.resolveProjectID(req, boundProjectID, input.ProjectID)
+		if err != nil {
+			return nil, searchOutput{}, err
+		}
+		resp, err := m.projectService.SearchWithOptions(input.searchRequest(projectID, k))
 		if err != nil {
 			return nil, searchOutput{}, err
 		}
 		return nil, searchOutput{
-			Results:      resp.Chunks,
-			TotalResults: resp.TotalResults,
-			QueryTimeMs:  resp.QueryTimeMs,
+			Results:         resp.Chunks,
+			TotalResults:    resp.TotalResults,
+			QueryTimeMs:     resp.QueryTimeMs,
+			Projects:        []string{projectID},
+			Offset:          resp.Offset,
+			NextCursor:      resp.NextCursor,
+			Rerank:          resp.Rerank,
+			EmbeddingModels: resp.EmbeddingModels,
 		}, nil
 	}
 }
 
 func (m *Manager) handleOutline(boundProjectID string) sdkmcp.ToolHandlerFor[outlineInput, outlineOutput] {
-	return func(_ context.Context, _ *sdkmcp.CallToolRequest, input outlineInput) (*sdkmcp.CallToolResult, outlineOutput, error) {
-		projectID, err := m.resolveProjectID(boundProjectID)
+	return func(_ context.Context, req *sdkmcp.CallToolRequest, input outlineInput) (*sdkmcp.CallToolResult, outlineOutput, error) {
+		projectID, err := m.resolveProjectID(req, boundProjectID, input.ProjectID)
 		if err != nil {
 			return nil, outlineOutput{}, err
 		}
@@ -706,8 +929,8 @@ func (m *Manager) handleOutline(boundProjectID string) sdkmcp.ToolHandlerFor[out
 }
 
 func (m *Manager) handleNodeSource(boundProjectID string) sdkmcp.ToolHandlerFor[nodeSourceInput, nodeSourceOutput] {
-	return func(_ context.Context, _ *sdkmcp.CallToolRequest, input nodeSourceInput) (*sdkmcp.CallToolResult, nodeSourceOutput, error) {
-		projectID, err := m.resolveProjectID(boundProjectID)
+	return func(_ context.Context, req *sdkmcp.CallToolRequest, input nodeSourceInput) (*sdkmcp.CallToolResult, nodeSourceOutput, error) {
+		projectID, err := m.resolveProjectID(req, boundProjectID, input.ProjectID)
 		if err != nil {
 			return nil, nodeSourceOutput{}, err
 		}
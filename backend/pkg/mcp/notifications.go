@@ -0,0 +1,208 @@
+/*
+  File: notifications.go
+  Purpose: Index-change notifications pushed to connected MCP sessions and the indexStatus tool.
+  Author: CodeTextor project
+  Notes: Log notifications are only delivered to sessions that opted in via logging/setLevel.
+*/
+
+package mcp
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"CodeTextor/backend/pkg/models"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	indexingStatusEventName = "project:indexingStatus"
+	indexerLoggerName       = "codetextor.indexer"
+
+	// indexStatusPollInterval is how often indexStatus re-reads progress while waiting.
+	indexStatusPollInterval = 500 * time.Millisecond
+	indexStatusDefaultWait  = 60
+	indexStatusMaxWait      = 600
+)
+
+type indexStatusInput struct {
+	ProjectID      string `json:"projectId,omitempty" jsonschema_description:"Project id (required on the unbound /mcp endpoint)"`
+	Wait           bool   `json:"wait,omitempty" jsonschema_description:"Block until the current indexing run finishes"`
+	TimeoutSeconds int    `json:"timeoutSeconds,omitempty" jsonschema_description:"Maximum wait in seconds (default 60, max 600)"`
+}
+
+type indexStatusOutput struct {
+	ProjectID string                  `json:"projectId"`
+	Progress  models.IndexingProgress `json:"progress"`
+	Fresh     bool                    `json:"fresh"`
+	TimedOut  bool                    `json:"timedOut,omitempty"`
+}
+
+// serversForProject returns the unbound server plus the server bound to projectID, if any.
+func (m *Manager) serversForProject(projectID string) []*sdkmcp.Server {
+	var servers []*sdkmcp.Server
+	m.configMu.RLock()
+	if m.server != nil {
+		servers = append(servers, m.server)
+	}
+	m.configMu.RUnlock()
+
+	m.serverCache.Lock()
+	if srv, ok := m.boundServers[projectID]; ok {
+		servers = append(servers, srv)
+	}
+	m.serverCache.Unlock()
+	return servers
+}
+
+// sessionTokenMiddleware remembers the token each session authenticated with, so notifications
+// can be limited to sessions whose token may see the project. Entries are dropped when the
+// session ends.
+func (m *Manager) sessionTokenMiddleware(next sdkmcp.MethodHandler) sdkmcp.MethodHandler {
+	return func(ctx context.Context, method string, req sdkmcp.Request) (sdkmcp.Result, error) {
+		if session, ok := req.GetSession().(*sdkmcp.ServerSession); ok && session != nil {
+			if _, loaded := m.sessionTokens.Swap(session, requestToken(req)); !loaded {
+				go func() {
+					_ = session.Wait()
+					m.sessionTokens.Delete(session)
+				}()
+			}
+		}
+		return next(ctx, method, req)
+	}
+}
+
+// sessionAllowsProject reports whether the token a session authenticated with may see
+// projectID. Sessions that have not sent a request yet cannot have set a log level and are
+// skipped.
+func (m *Manager) sessionAllowsProject(session *sdkmcp.ServerSession, projectID string) bool {
+	value, ok := m.sessionTokens.Load(session)
+	if !ok {
+		return false
+	}
+	token, _ := value.(*models.MCPAPIToken)
+	return tokenAllowsProject(token, projectID)
+}
+
+// logIndexEvent sends an indexer log notification to every session that can see projectID.
+func (m *Manager) logIndexEvent(projectID string, level sdkmcp.LoggingLevel, data map[string]any) {
+	ctx := context.Background()
+	for _, srv := range m.serversForProject(projectID) {
+		for session := range srv.Sessions() {
+			if !m.sessionAllowsProject(session, projectID) {
+				continue
+			}
+			_ = session.Log(ctx, &sdkmcp.LoggingMessageParams{
+				Level:  level,
+				Logger: indexerLoggerName,
+				Data:   data,
+			})
+		}
+	}
+}
+
+// handleIndexingStatus forwards indexing run transitions and migration progress as log notifications.
+func (m *Manager) handleIndexingStatus(payload map[string]interface{}) {
+	projectID, _ := payload["projectId"].(string)
+	status, _ := payload["status"].(string)
+	if projectID == "" || status == "" {
+		return
+	}
+	level := sdkmcp.LoggingLevel("info")
+	if status == string(models.IndexingStatusError) {
+		level = "error"
+	}
+	data := map[string]any{
+		"event":     "indexingStatus",
+		"projectId": projectID,
+		"status":    status,
+	}
+	for _, key := range []string{"totalFiles", "processedFiles", "migrationModel", "migrationBackfill", "totalChunks", "processedChunks", "error"} {
+		if value, ok := payload[key]; ok && value != "" {
+			data[key] = value
+		}
+	}
+	m.logIndexEvent(projectID, level, data)
+}
+
+// indexingFresh reports whether no indexing pass is in flight.
+func indexingFresh(progress models.IndexingProgress) bool {
+	return progress.Status != models.IndexingStatusIndexing
+}
+
+func (m *Manager) handleIndexStatus(boundProjectID string) sdkmcp.ToolHandlerFor[indexStatusInput, indexStatusOutput] {
+	return func(ctx context.Context, req *sdkmcp.CallToolRequest, input indexStatusInput) (*sdkmcp.CallToolResult, indexStatusOutput, error) {
+		projectID, err := m.resolveProjectID(req, boundProjectID, input.ProjectID)
+		if err != nil {
+			return nil, indexStatusOutput{}, err
+		}
+		progress, err := m.projectService.GetIndexingProgress(projectID)
+		if err != nil {
+			return nil, indexStatusOutput{}, err
+		}
+		output := indexStatusOutput{ProjectID: projectID, Progress: progress, Fresh: indexingFresh(progress)}
+		if !input.Wait || output.Fresh {
+			return nil, output, nil
+		}
+
+		if output.Progress, output.TimedOut, err = m.waitForIndex(ctx, req, projectID, input.TimeoutSeconds); err != nil {
+			return nil, indexStatusOutput{}, err
+		}
+		output.Fresh = !output.TimedOut
+		return nil, output, nil
+	}
+}
+
+// waitTimeout clamps a requested wait in seconds to the supported range.
+func waitTimeout(seconds int) time.Duration {
+	if seconds <= 0 {
+		seconds = indexStatusDefaultWait
+	}
+	if seconds > indexStatusMaxWait {
+		seconds = indexStatusMaxWait
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// waitForIndex polls the project's indexing progress until no pass is running or the timeout
+// elapses. When the request carries a progress token, progress notifications are sent meanwhile.
+func (m *Manager) waitForIndex(ctx context.Context, req *sdkmcp.CallToolRequest, projectID string, timeoutSeconds int) (models.IndexingProgress, bool, error) {
+	deadline := time.NewTimer(waitTimeout(timeoutSeconds))
+	defer deadline.Stop()
+	ticker := time.NewTicker(indexStatusPollInterval)
+	defer ticker.Stop()
+
+	var progressToken any
+	if req != nil && req.Params != nil {
+		progressToken = req.Params.GetProgressToken()
+	}
+	lastProcessed := -1
+	for {
+		progress, err := m.projectService.GetIndexingProgress(projectID)
+		if err != nil {
+			return models.IndexingProgress{}, false, err
+		}
+		if indexingFresh(progress) {
+			return progress, false, nil
+		}
+		if progressToken != nil && req.Session != nil && progress.ProcessedFiles != lastProcessed {
+			lastProcessed = progress.ProcessedFiles
+			_ = req.Session.NotifyProgress(ctx, &sdkmcp.ProgressNotificationParams{
+				ProgressToken: progressToken,
+				Progress:      float64(progress.ProcessedFiles),
+				Total:         float64(progress.TotalFiles),
+				Message:       strings.TrimSpace("indexing " + progress.CurrentFile),
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+			return models.IndexingProgress{}, false, ctx.Err()
+		case <-deadline.C:
+			return progress, true, nil
+		case <-ticker.C:
+		}
+	}
+}
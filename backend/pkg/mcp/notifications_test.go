@@ -0,0 +1,238 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"CodeTextor/backend/internal/store"
+	"CodeTextor/backend/pkg/models"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// progressProjectService replays a sequence of indexing progress snapshots.
+type progressProjectService struct {
+	*fakeProjectService
+	mu       sync.Mutex
+	sequence []models.IndexingProgress
+}
+
+func (p *progressProjectService) GetIndexingProgress(string) (models.IndexingProgress, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	next := p.sequence[0]
+	if len(p.sequence) > 1 {
+		p.sequence = p.sequence[1:]
+	}
+	return next, nil
+}
+
+func TestIndexStatusWaitsForRunToFinish(t *testing.T) {
+	svc := &progressProjectService{
+		fakeProjectService: newFakeProjectService(),
+		sequence: []models.IndexingProgress{
+			{Status: models.IndexingStatusIndexing, TotalFiles: 3, ProcessedFiles: 1},
+			{Status: models.IndexingStatusIndexing, TotalFiles: 3, ProcessedFiles: 2},
+			{Status: models.IndexingStatusCompleted, TotalFiles: 3, ProcessedFiles: 3},
+		},
+	}
+	m := &Manager{projectService: svc}
+	handler := m.handleIndexStatus("alpha")
+
+	_, output, err := handler(context.Background(), &sdkmcp.CallToolRequest{}, indexStatusInput{Wait: true, TimeoutSeconds: 5})
+	if err != nil {
+		t.Fatalf("indexStatus failed: %v", err)
+	}
+	if !output.Fresh || output.TimedOut {
+		t.Fatalf("expected a fresh index, got %+v", output)
+	}
+	if output.ProjectID != "alpha" || output.Progress.ProcessedFiles != 3 {
+		t.Fatalf("unexpected progress: %+v", output)
+	}
+}
+
+func TestIndexStatusWithoutWaitReturnsSnapshot(t *testing.T) {
+	svc := &progressProjectService{
+		fakeProjectService: newFakeProjectService(),
+		sequence:           []models.IndexingProgress{{Status: models.IndexingStatusIndexing, TotalFiles: 4}},
+	}
+	m := &Manager{projectService: svc}
+
+	_, output, err := m.handleIndexStatus("")(context.Background(), &sdkmcp.CallToolRequest{}, indexStatusInput{ProjectID: "beta"})
+	if err != nil {
+		t.Fatalf("indexStatus failed: %v", err)
+	}
+	if output.Fresh || output.ProjectID != "beta" || output.Progress.TotalFiles != 4 {
+		t.Fatalf("unexpected snapshot: %+v", output)
+	}
+}
+
+// bearerTransport adds a fixed bearer token to every request.
+type bearerTransport struct{ secret string }
+
+func (b bearerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+b.secret)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestIndexEventsOnlyReachSessionsScopedToProject(t *testing.T) {
+	cfg := models.DefaultMCPServerConfig()
+	cfg.AllowAnonymous = true
+	m := &Manager{
+		projectService: newFakeProjectService(),
+		config:         cfg,
+		disabledTools:  map[string]bool{},
+		enabledTools:   map[string]bool{},
+		limiter:        newRateLimiter(),
+	}
+	m.initTools()
+	if err := m.buildServerLocked(); err != nil {
+		t.Fatalf("build server: %v", err)
+	}
+	tokens := map[string]*models.MCPAPIToken{
+		"alpha-secret": {ID: "t-alpha", Name: "alpha only", Projects: []string{"alpha"}},
+		"beta-secret":  {ID: "t-beta", Name: "beta only", Projects: []string{"beta"}},
+	}
+	verify := func(_ context.Context, secret string, _ *http.Request) (*auth.TokenInfo, error) {
+		token, ok := tokens[secret]
+		if !ok {
+			return nil, auth.ErrInvalidToken
+		}
+		return &auth.TokenInfo{Expiration: tokenExpiration, Extra: map[string]any{tokenInfoKey: token}}, nil
+	}
+	httpServer := httptest.NewServer(auth.RequireBearerToken(verify, nil)(m.handler))
+	// Registered first so it runs after the sessions are closed; the standalone event streams
+	// stay open until their connections are dropped.
+	t.Cleanup(func() {
+		httpServer.CloseClientConnections()
+		httpServer.Close()
+	})
+
+	ctx := context.Background()
+	connect := func(secret string) chan string {
+		received := make(chan string, 4)
+		client := sdkmcp.NewClient(&sdkmcp.Implementation{Name: secret, Version: "1.0"}, &sdkmcp.ClientOptions{
+			LoggingMessageHandler: func(_ context.Context, req *sdkmcp.LoggingMessageRequest) {
+				data, _ := req.Params.Data.(map[string]any)
+				projectID, _ := data["projectId"].(string)
+				received <- projectID
+			},
+		})
+		transport := &sdkmcp.StreamableClientTransport{
+			Endpoint:   httpServer.URL + "/mcp",
+			HTTPClient: &http.Client{Transport: bearerTransport{secret: secret}},
+		}
+		session, err := client.Connect(ctx, transport, nil)
+		if err != nil {
+			t.Fatalf("connect with %s: %v", secret, err)
+		}
+		t.Cleanup(func() { session.Close() })
+		if err := session.SetLoggingLevel(ctx, &sdkmcp.SetLoggingLevelParams{Level: "info"}); err != nil {
+			t.Fatalf("set log level: %v", err)
+		}
+		return received
+	}
+	alphaEvents := connect("alpha-secret")
+	betaEvents := connect("beta-secret")
+
+	m.handleIndexingStatus(map[string]interface{}{"projectId": "beta", "status": string(models.IndexingStatusCompleted)})
+	m.handleIndexingStatus(map[string]interface{}{"projectId": "alpha", "status": string(models.IndexingStatusCompleted)})
+
+	next := func(events chan string) string {
+		select {
+		case projectID := <-events:
+			return projectID
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for a log notification")
+			return ""
+		}
+	}
+	// Notifications arrive in order on each session, so the first one tells whether the
+	// other project's event leaked through.
+	if got := next(alphaEvents); got != "alpha" {
+		t.Fatalf("alpha-scoped session received an event for %q", got)
+	}
+	if got := next(betaEvents); got != "beta" {
+		t.Fatalf("beta-scoped session received an event for %q", got)
+	}
+}
+
+func TestWaitsOutliveTheServerWriteTimeout(t *testing.T) {
+	// Tool calls are audited into the config database.
+	t.Setenv("HOME", t.TempDir())
+	configStore, err := store.NewConfigStore()
+	if err != nil {
+		t.Fatalf("open config store: %v", err)
+	}
+	t.Cleanup(func() { configStore.Close() })
+	cfg := models.DefaultMCPServerConfig()
+	cfg.AllowAnonymous = true
+	m := &Manager{
+		configStore: configStore,
+		projectService: &progressProjectService{
+			fakeProjectService: newFakeProjectService(),
+			sequence:           []models.IndexingProgress{{Status: models.IndexingStatusIndexing, TotalFiles: 2}},
+		},
+		config:        cfg,
+		disabledTools: map[string]bool{},
+		enabledTools:  map[string]bool{},
+		limiter:       newRateLimiter(),
+	}
+	m.initTools()
+	if err := m.buildServerLocked(); err != nil {
+		t.Fatalf("build server: %v", err)
+	}
+	httpServer := httptest.NewUnstartedServer(m.handler)
+	httpServer.Config.WriteTimeout = 300 * time.Millisecond
+	httpServer.Start()
+	t.Cleanup(func() {
+		httpServer.CloseClientConnections()
+		httpServer.Close()
+	})
+
+	ctx := context.Background()
+	received := make(chan string, 4)
+	client := sdkmcp.NewClient(&sdkmcp.Implementation{Name: "waiter", Version: "1.0"}, &sdkmcp.ClientOptions{
+		LoggingMessageHandler: func(_ context.Context, req *sdkmcp.LoggingMessageRequest) {
+			data, _ := req.Params.Data.(map[string]any)
+			projectID, _ := data["projectId"].(string)
+			received <- projectID
+		},
+	})
+	session, err := client.Connect(ctx, &sdkmcp.StreamableClientTransport{Endpoint: httpServer.URL + "/mcp"}, nil)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+	if err := session.SetLoggingLevel(ctx, &sdkmcp.SetLoggingLevelParams{Level: "info"}); err != nil {
+		t.Fatalf("set log level: %v", err)
+	}
+
+	result, err := session.CallTool(ctx, &sdkmcp.CallToolParams{
+		Name:      "indexStatus",
+		Arguments: map[string]any{"projectId": "alpha", "wait": true, "timeoutSeconds": 1},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("a wait longer than the write timeout should still be answered: %v %+v", err, result)
+	}
+	if timedOut, _ := result.StructuredContent.(map[string]any)["timedOut"].(bool); !timedOut {
+		t.Fatalf("expected the wait to time out, got %+v", result.StructuredContent)
+	}
+
+	// The standalone event stream has been open longer than the write timeout by now.
+	m.handleIndexingStatus(map[string]interface{}{"projectId": "alpha", "status": string(models.IndexingStatusCompleted)})
+	select {
+	case projectID := <-received:
+		if projectID != "alpha" {
+			t.Fatalf("unexpected notification for %q", projectID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the notification stream was cut off by the write timeout")
+	}
+}
@@ -0,0 +1,164 @@
+/*
+  File: projects.go
+  Purpose: Multi-project support for the unbound /mcp endpoint (project resolution,
+           listProjects and cross-project search).
+  Author: CodeTextor project
+*/
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"CodeTextor/backend/pkg/models"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type listProjectsInput struct{}
+
+type projectSummary struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	RootPath    string `json:"rootPath,omitempty"`
+	IsIndexing  bool   `json:"isIndexing"`
+}
+
+type listProjectsOutput struct {
+	Projects []projectSummary `json:"projects"`
+}
+
+// resolveProjectID picks the project a call targets: the session's bound project, or the
+// projectId argument on the unbound endpoint. Token project scopes are enforced here.
+func (m *Manager) resolveProjectID(req sdkmcp.Request, boundProjectID, requested string) (string, error) {
+	bound := strings.TrimSpace(boundProjectID)
+	requested = strings.TrimSpace(requested)
+
+	projectID := bound
+	switch {
+	case bound != "" && requested != "" && requested != bound:
+		return "", fmt.Errorf("this session is bound to project %s; projectId %s is not accessible here", bound, requested)
+	case bound == "" && requested == "":
+		return "", fmt.Errorf("projectId is required; pass projectId (see listProjects) or call the MCP server via /mcp/<projectId>")
+	case bound == "":
+		if _, err := m.projectService.GetProject(requested); err != nil {
+			return "", fmt.Errorf("unknown project %s: %w", requested, err)
+		}
+		projectID = requested
+	}
+
+	if token := requestToken(req); !tokenAllowsProject(token, projectID) {
+		return "", fmt.Errorf("token %s is not allowed to access project %s", token.Name, projectID)
+	}
+	return projectID, nil
+}
+
+// accessibleProjects lists the projects the caller may reach from the unbound endpoint.
+func (m *Manager) accessibleProjects(req sdkmcp.Request) ([]*models.Project, error) {
+	projects, err := m.projectService.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+	token := requestToken(req)
+	result := make([]*models.Project, 0, len(projects))
+	for _, project := range projects {
+		if tokenAllowsProject(token, project.ID) {
+			result = append(result, project)
+		}
+	}
+	return result, nil
+}
+
+func (m *Manager) handleListProjects(boundProjectID string) sdkmcp.ToolHandlerFor[listProjectsInput, listProjectsOutput] {
+	return func(_ context.Context, req *sdkmcp.CallToolRequest, _ listProjectsInput) (*sdkmcp.CallToolResult, listProjectsOutput, error) {
+		var projects []*models.Project
+		if bound := strings.TrimSpace(boundProjectID); bound != "" {
+			project, err := m.projectService.GetProject(bound)
+			if err != nil {
+				return nil, listProjectsOutput{}, err
+			}
+			projects = []*models.Project{project}
+		} else {
+			var err error
+			if projects, err = m.accessibleProjects(req); err != nil {
+				return nil, listProjectsOutput{}, err
+			}
+		}
+
+		output := listProjectsOutput{Projects: make([]projectSummary, 0, len(projects))}
+		for _, project := range projects {
+			output.Projects = append(output.Projects, projectSummary{
+				ID:          project.ID,
+				Name:        project.Name,
+				Description: project.Description,
+				RootPath:    project.Config.RootPath,
+				IsIndexing:  project.IsIndexing,
+			})
+		}
+		return nil, output, nil
+	}
+}
+
+// searchAcrossProjects runs the query on every accessible project and merges the hits by
+// similarity. Projects that cannot be searched (e.g. missing model) are reported, not fatal.
+// Diversification options apply within each project; maxPerFile is enforced again after merging.
+// totalResults sums the per-project counts above minScore. Cursors are per project, so merged
+// results cannot be paged.
+func (m *Manager) searchAcrossProjects(req sdkmcp.Request, input searchInput, k int) (searchOutput, error) {
+	start := time.Now()
+	if strings.TrimSpace(input.Cursor) != "" {
+		return searchOutput{}, fmt.Errorf("cursor requires projectId: results merged across projects cannot be paged")
+	}
+	projects, err := m.accessibleProjects(req)
+	if err != nil {
+		return searchOutput{}, err
+	}
+	if len(projects) == 0 {
+		return searchOutput{}, fmt.Errorf("no projects are accessible")
+	}
+
+	output := searchOutput{Results: []*models.Chunk{}}
+	for _, project := range projects {
+		resp, err := m.projectService.SearchWithOptions(input.searchRequest(project.ID, k))
+		if err != nil {
+			if output.Skipped == nil {
+				output.Skipped = make(map[string]string)
+			}
+			output.Skipped[project.ID] = err.Error()
+			continue
+		}
+		output.Projects = append(output.Projects, project.ID)
+		output.Results = append(output.Results, resp.Chunks...)
+		output.TotalResults += resp.TotalResults
+	}
+	if len(output.Projects) == 0 {
+		return searchOutput{}, fmt.Errorf("search failed in every project: %v", output.Skipped)
+	}
+
+	sort.SliceStable(output.Results, func(i, j int) bool {
+		return output.Results[i].Similarity > output.Results[j].Similarity
+	})
+	if input.MaxPerFile > 0 {
+		perFile := make(map[string]int)
+		capped := output.Results[:0]
+		for _, chunk := range output.Results {
+			key := chunk.ProjectID + "\x00" + chunk.FilePath
+			if perFile[key] >= input.MaxPerFile {
+				continue
+			}
+			perFile[key]++
+			capped = append(capped, chunk)
+		}
+		output.Results = capped
+	}
+	if len(output.Results) > k {
+		output.Results = output.Results[:k]
+	}
+	output.QueryTimeMs = time.Since(start).Milliseconds()
+	return output, nil
+}
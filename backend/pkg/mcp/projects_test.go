@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"fmt"
+	"testing"
+
+	"CodeTextor/backend/pkg/models"
+	"CodeTextor/backend/pkg/services"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// fakeProjectService implements the subset of ProjectServiceAPI used by project resolution and search.
+type fakeProjectService struct {
+	services.ProjectServiceAPI
+	projects map[string][]*models.Chunk
+	failing  map[string]bool
+}
+
+func (f *fakeProjectService) ListProjects() ([]*models.Project, error) {
+	ids := []string{"alpha", "beta", "gamma"}
+	projects := make([]*models.Project, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := f.projects[id]; ok {
+			projects = append(projects, &models.Project{ID: id, Name: id})
+		}
+	}
+	return projects, nil
+}
+
+func (f *fakeProjectService) GetProject(projectID string) (*models.Project, error) {
+	if _, ok := f.projects[projectID]; !ok {
+		return nil, fmt.Errorf("project not found: %s", projectID)
+	}
+	return &models.Project{ID: projectID, Name: projectID}, nil
+}
+
+func (f *fakeProjectService) Search(projectID string, _ string, k int) (*models.SearchResponse, error) {
+	if f.failing[projectID] {
+		return nil, fmt.Errorf("no embedding model")
+	}
+	chunks := f.projects[projectID]
+	if len(chunks) > k {
+		chunks = chunks[:k]
+	}
+	return &models.SearchResponse{Chunks: chunks, TotalResults: len(chunks)}, nil
+}
+
+func (f *fakeProjectService) SearchWithOptions(req models.SearchRequest) (*models.SearchResponse, error) {
+	return f.Search(req.ProjectID, req.Query, req.K)
+}
+
+func newFakeProjectService() *fakeProjectService {
+	return &fakeProjectService{
+		projects: map[string][]*models.Chunk{
+			"alpha": {{ID: "a1", ProjectID: "alpha", Similarity: 0.9}, {ID: "a2", ProjectID: "alpha", Similarity: 0.4}},
+			"beta":  {{ID: "b1", ProjectID: "beta", Similarity: 0.7}},
+			"gamma": {{ID: "g1", ProjectID: "gamma", Similarity: 0.95}},
+		},
+		failing: map[string]bool{"gamma": true},
+	}
+}
+
+func requestWithToken(token *models.MCPAPIToken) sdkmcp.Request {
+	return &sdkmcp.CallToolRequest{Extra: &sdkmcp.RequestExtra{
+		TokenInfo: &auth.TokenInfo{Extra: map[string]any{tokenInfoKey: token}},
+	}}
+}
+
+func TestResolveProjectID(t *testing.T) {
+	m := &Manager{projectService: newFakeProjectService()}
+	req := &sdkmcp.CallToolRequest{}
+
+	if id, err := m.resolveProjectID(req, "alpha", ""); err != nil || id != "alpha" {
+		t.Errorf("bound session: got %q, %v", id, err)
+	}
+	if _, err := m.resolveProjectID(req, "alpha", "beta"); err == nil {
+		t.Error("expected error when projectId differs from the bound project")
+	}
+	if _, err := m.resolveProjectID(req, "", ""); err == nil {
+		t.Error("expected error without any project")
+	}
+	if id, err := m.resolveProjectID(req, "", "beta"); err != nil || id != "beta" {
+		t.Errorf("unbound session: got %q, %v", id, err)
+	}
+	if _, err := m.resolveProjectID(req, "", "missing"); err == nil {
+		t.Error("expected error for unknown project")
+	}
+
+	scoped := requestWithToken(&models.MCPAPIToken{Name: "ci", Projects: []string{"alpha"}})
+	if _, err := m.resolveProjectID(scoped, "", "beta"); err == nil {
+		t.Error("expected token scope to reject beta")
+	}
+}
+
+func TestSearchAcrossProjectsMergesBySimilarity(t *testing.T) {
+	m := &Manager{projectService: newFakeProjectService()}
+
+	output, err := m.searchAcrossProjects(&sdkmcp.CallToolRequest{}, searchInput{Query: "query"}, 2)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(output.Results) != 2 || output.Results[0].ID != "a1" || output.Results[1].ID != "b1" {
+		t.Errorf("unexpected merged results: %+v", output.Results)
+	}
+	if _, ok := output.Skipped["gamma"]; !ok {
+		t.Errorf("expected gamma to be reported as skipped, got %v", output.Skipped)
+	}
+
+	scoped := requestWithToken(&models.MCPAPIToken{Name: "ci", Projects: []string{"beta"}})
+	output, err = m.searchAcrossProjects(scoped, searchInput{Query: "query"}, 5)
+	if err != nil {
+		t.Fatalf("scoped search: %v", err)
+	}
+	if len(output.Projects) != 1 || output.Projects[0] != "beta" {
+		t.Errorf("scoped search touched %v", output.Projects)
+	}
+}
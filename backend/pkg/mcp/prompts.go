@@ -0,0 +1,311 @@
+/*
+  File: prompts.go
+  Purpose: Server-defined MCP prompts for common code-understanding workflows.
+  Author: CodeTextor project
+*/
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"CodeTextor/backend/pkg/models"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	toolKindTool   = "tool"
+	toolKindPrompt = "prompt"
+
+	promptDefaultResults = 8
+	promptMaxResults     = 50
+	promptMaxSourceBytes = 24 * 1024
+	promptMaxReviewFiles = 20
+)
+
+var projectPromptArgument = &sdkmcp.PromptArgument{
+	Name:        "projectId",
+	Description: "Project id; required on the unbound /mcp endpoint",
+}
+
+// promptDefinitions lists the prompts registered next to the tools; the handler is
+// resolved per bound project when the server is built.
+func promptDefinitions() map[string]*toolState {
+	return map[string]*toolState{
+		"explainFile": {
+			name:        "explainFile",
+			kind:        toolKindPrompt,
+			description: "Explain a file using its outline and source from the index",
+		},
+		"findFeature": {
+			name:        "findFeature",
+			kind:        toolKindPrompt,
+			description: "Locate where a feature is implemented, pre-filled with semantic search hits",
+		},
+		"reviewChanges": {
+			name:        "reviewChanges",
+			kind:        toolKindPrompt,
+			description: "Review recently re-indexed files under a path with their outlines",
+		},
+	}
+}
+
+// promptRegistration returns the register callback for a prompt, or nil for unknown names.
+func (m *Manager) promptRegistration(name string, state *toolState) func(*sdkmcp.Server, string) {
+	var prompt *sdkmcp.Prompt
+	var handler func(string) sdkmcp.PromptHandler
+
+	switch name {
+	case "explainFile":
+		prompt = &sdkmcp.Prompt{
+			Name:  "explainFile",
+			Title: "Explain this file",
+			Arguments: []*sdkmcp.PromptArgument{
+				{Name: "path", Description: "File path relative to the project root", Required: true},
+				{Name: "depth", Description: "Optional outline depth limit"},
+				projectPromptArgument,
+			},
+		}
+		handler = m.handleExplainFilePrompt
+	case "findFeature":
+		prompt = &sdkmcp.Prompt{
+			Name:  "findFeature",
+			Title: "Find where a feature is implemented",
+			Arguments: []*sdkmcp.PromptArgument{
+				{Name: "feature", Description: "Natural-language description of the feature", Required: true},
+				{Name: "k", Description: "Number of search hits to include (1-50)"},
+				projectPromptArgument,
+			},
+		}
+		handler = m.handleFindFeaturePrompt
+	case "reviewChanges":
+		prompt = &sdkmcp.Prompt{
+			Name:  "reviewChanges",
+			Title: "Review changes in a path",
+			Arguments: []*sdkmcp.PromptArgument{
+				{Name: "path", Description: "File or directory relative to the project root", Required: true},
+				{Name: "focus", Description: "Optional review focus used to pull related code via search"},
+				projectPromptArgument,
+			},
+		}
+		handler = m.handleReviewChangesPrompt
+	default:
+		return nil
+	}
+
+	return func(s *sdkmcp.Server, boundProjectID string) {
+		p := *prompt
+		p.Description = describeForProject(state.description, m.projectLabel(boundProjectID))
+		s.AddPrompt(&p, wrapPrompt(m, name, handler(boundProjectID)))
+	}
+}
+
+func wrapPrompt(m *Manager, name string, handler sdkmcp.PromptHandler) sdkmcp.PromptHandler {
+	return func(ctx context.Context, req *sdkmcp.GetPromptRequest) (*sdkmcp.GetPromptResult, error) {
+		start := time.Now()
+		result, err := handler(ctx, req)
+
+		m.recordCall(name, time.Since(start))
+		if err != nil {
+			m.lastError.Store(err.Error())
+		}
+		return result, err
+	}
+}
+
+func promptArgument(req *sdkmcp.GetPromptRequest, name string) string {
+	if req == nil || req.Params == nil {
+		return ""
+	}
+	return strings.TrimSpace(req.Params.Arguments[name])
+}
+
+func promptResult(description, text string) *sdkmcp.GetPromptResult {
+	return &sdkmcp.GetPromptResult{
+		Description: description,
+		Messages: []*sdkmcp.PromptMessage{{
+			Role:    "user",
+			Content: &sdkmcp.TextContent{Text: text},
+		}},
+	}
+}
+
+func promptSearchResults(requested string) int {
+	k, err := strconv.Atoi(requested)
+	if err != nil || k <= 0 {
+		return promptDefaultResults
+	}
+	if k > promptMaxResults {
+		return promptMaxResults
+	}
+	return k
+}
+
+func (m *Manager) handleExplainFilePrompt(boundProjectID string) sdkmcp.PromptHandler {
+	return func(_ context.Context, req *sdkmcp.GetPromptRequest) (*sdkmcp.GetPromptResult, error) {
+		projectID, err := m.resolveProjectID(req, boundProjectID, promptArgument(req, "projectId"))
+		if err != nil {
+			return nil, err
+		}
+		path := promptArgument(req, "path")
+		if path == "" {
+			return nil, fmt.Errorf("path cannot be empty")
+		}
+		// Like file resources, only indexed files are exposed.
+		indexed, err := m.projectService.ListIndexedFiles(projectID)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(indexed, path) {
+			return nil, fmt.Errorf("file %s is not indexed in project %s", path, projectID)
+		}
+
+		nodes, err := m.projectService.GetFileOutline(projectID, path)
+		if err != nil {
+			return nil, err
+		}
+		if depth, err := strconv.Atoi(promptArgument(req, "depth")); err == nil && depth > 0 {
+			nodes = limitOutlineDepth(nodes, depth)
+		}
+		source, err := m.projectService.ReadFileContent(projectID, path)
+		if err != nil {
+			return nil, err
+		}
+		truncated := false
+		if len(source) > promptMaxSourceBytes {
+			source = truncateUTF8(source, promptMaxSourceBytes)
+			truncated = true
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "Explain the file `%s` from project %s.\n", path, m.projectLabel(projectID))
+		b.WriteString("Describe its purpose, its main symbols and how they interact, and any notable design decisions.\n\n")
+		b.WriteString("## Outline\n")
+		writeOutline(&b, nodes, 0)
+		fmt.Fprintf(&b, "\n## Source\n```\n%s\n```\n", source)
+		if truncated {
+			b.WriteString("\nThe source was truncated; call nodeSource with ids from the outline for the remaining symbols.\n")
+		}
+		return promptResult(fmt.Sprintf("Explain %s", path), b.String()), nil
+	}
+}
+
+func (m *Manager) handleFindFeaturePrompt(boundProjectID string) sdkmcp.PromptHandler {
+	return func(_ context.Context, req *sdkmcp.GetPromptRequest) (*sdkmcp.GetPromptResult, error) {
+		projectID, err := m.resolveProjectID(req, boundProjectID, promptArgument(req, "projectId"))
+		if err != nil {
+			return nil, err
+		}
+		feature := promptArgument(req, "feature")
+		if feature == "" {
+			return nil, fmt.Errorf("feature cannot be empty")
+		}
+
+		resp, err := m.projectService.Search(projectID, feature, promptSearchResults(promptArgument(req, "k")))
+		if err != nil {
+			return nil, err
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "Find where the following feature is implemented in project %s:\n\n> %s\n\n", m.projectLabel(projectID), feature)
+		b.WriteString("## Semantic search hits\n")
+		writeSearchHits(&b, resp.Chunks)
+		b.WriteString("\nUse nodeSource with the chunk ids above to confirm the implementation, and outline to map the surrounding file. ")
+		b.WriteString("Answer with the files and symbols involved and how control flows between them.\n")
+		return promptResult(fmt.Sprintf("Find implementation of %q", feature), b.String()), nil
+	}
+}
+
+func (m *Manager) handleReviewChangesPrompt(boundProjectID string) sdkmcp.PromptHandler {
+	return func(_ context.Context, req *sdkmcp.GetPromptRequest) (*sdkmcp.GetPromptResult, error) {
+		projectID, err := m.resolveProjectID(req, boundProjectID, promptArgument(req, "projectId"))
+		if err != nil {
+			return nil, err
+		}
+		scope := strings.Trim(promptArgument(req, "path"), "/")
+		if scope == "" {
+			return nil, fmt.Errorf("path cannot be empty")
+		}
+
+		timestamps, err := m.projectService.GetOutlineTimestamps(projectID)
+		if err != nil {
+			return nil, err
+		}
+		files := make([]string, 0)
+		for file := range timestamps {
+			if file == scope || strings.HasPrefix(file, scope+"/") || scope == "." {
+				files = append(files, file)
+			}
+		}
+		sort.Slice(files, func(i, j int) bool {
+			if timestamps[files[i]] == timestamps[files[j]] {
+				return files[i] < files[j]
+			}
+			return timestamps[files[i]] > timestamps[files[j]]
+		})
+		if len(files) > promptMaxReviewFiles {
+			files = files[:promptMaxReviewFiles]
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no indexed files found under %s", scope)
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "Review the recent changes under `%s` in project %s.\n", scope, m.projectLabel(projectID))
+		b.WriteString("Look for bugs, missing error handling, inconsistent naming and missing tests. Files are listed most recently re-indexed first.\n\n")
+		for _, file := range files {
+			nodes, err := m.projectService.GetFileOutline(projectID, file)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(&b, "## %s (indexed %s)\n", file, time.Unix(timestamps[file], 0).UTC().Format(time.RFC3339))
+			writeOutline(&b, limitOutlineDepth(nodes, 2), 0)
+			b.WriteString("\n")
+		}
+
+		if focus := promptArgument(req, "focus"); focus != "" {
+			resp, err := m.projectService.Search(projectID, focus, promptSearchResults(""))
+			if err == nil {
+				fmt.Fprintf(&b, "## Code related to %q\n", focus)
+				writeSearchHits(&b, resp.Chunks)
+			}
+		}
+		b.WriteString("\nFetch the relevant snippets with nodeSource before commenting on specific lines.\n")
+		return promptResult(fmt.Sprintf("Review changes in %s", scope), b.String()), nil
+	}
+}
+
+func writeOutline(b *strings.Builder, nodes []*models.OutlineNode, level int) {
+	if len(nodes) == 0 && level == 0 {
+		b.WriteString("(no symbols)\n")
+		return
+	}
+	for _, node := range nodes {
+		fmt.Fprintf(b, "%s- %s %s (lines %d-%d, id %s)\n", strings.Repeat("  ", level), node.Kind, node.Name, node.StartLine, node.EndLine, node.ID)
+		writeOutline(b, node.Children, level+1)
+	}
+}
+
+func writeSearchHits(b *strings.Builder, chunks []*models.Chunk) {
+	if len(chunks) == 0 {
+		b.WriteString("(no results)\n")
+		return
+	}
+	for idx, chunk := range chunks {
+		symbol := chunk.SymbolName
+		if symbol == "" {
+			symbol = "(anonymous)"
+		}
+		fmt.Fprintf(b, "%d. %s:%d-%d %s %s (similarity %.3f, id %s)\n", idx+1, chunk.FilePath, chunk.LineStart, chunk.LineEnd, chunk.SymbolKind, symbol, chunk.Similarity, chunk.ID)
+		if signature := strings.TrimSpace(chunk.Signature); signature != "" {
+			fmt.Fprintf(b, "   %s\n", signature)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"CodeTextor/backend/pkg/models"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestWriteOutlineIndentsChildren(t *testing.T) {
+	nodes := []*models.OutlineNode{{
+		ID: "n1", Name: "Manager", Kind: "type", StartLine: 1, EndLine: 20,
+		Children: []*models.OutlineNode{{ID: "n2", Name: "Start", Kind: "method", StartLine: 5, EndLine: 9}},
+	}}
+
+	var b strings.Builder
+	writeOutline(&b, nodes, 0)
+
+	want := "- type Manager (lines 1-20, id n1)\n  - method Start (lines 5-9, id n2)\n"
+	if b.String() != want {
+		t.Errorf("unexpected outline:\n%s", b.String())
+	}
+}
+
+func TestPromptSearchResultsBounds(t *testing.T) {
+	cases := map[string]int{"": promptDefaultResults, "abc": promptDefaultResults, "0": promptDefaultResults, "5": 5, "500": promptMaxResults}
+	for input, want := range cases {
+		if got := promptSearchResults(input); got != want {
+			t.Errorf("promptSearchResults(%q) = %d, want %d", input, got, want)
+		}
+	}
+}
+
+// promptProjectService adds an empty outline to the indexed files of resourceProjectService.
+type promptProjectService struct {
+	*resourceProjectService
+	source string
+}
+
+func (p *promptProjectService) GetFileOutline(string, string) ([]*models.OutlineNode, error) {
+	return nil, nil
+}
+
+func (p *promptProjectService) ReadFileContent(string, string) (string, error) {
+	return p.source, nil
+}
+
+func TestExplainFilePromptOnlyServesIndexedFiles(t *testing.T) {
+	svc := &promptProjectService{
+		resourceProjectService: &resourceProjectService{fakeProjectService: newFakeProjectService(), indexed: []string{"main.go"}},
+		source:                 strings.Repeat("a", promptMaxSourceBytes-1) + "é tail",
+	}
+	m := &Manager{projectService: svc}
+	explain := func(path string) (*sdkmcp.GetPromptResult, error) {
+		return m.handleExplainFilePrompt("alpha")(context.Background(), &sdkmcp.GetPromptRequest{
+			Params: &sdkmcp.GetPromptParams{Name: "explainFile", Arguments: map[string]string{"path": path}},
+		})
+	}
+
+	if _, err := explain(".env"); err == nil {
+		t.Fatal("expected a file outside the index to be rejected")
+	}
+	result, err := explain("main.go")
+	if err != nil {
+		t.Fatalf("explain indexed file: %v", err)
+	}
+	text := result.Messages[0].Content.(*sdkmcp.TextContent).Text
+	if !utf8.ValidString(text) || strings.Contains(text, "tail") || !strings.Contains(text, "truncated") {
+		t.Fatalf("expected the source to be cut before the multi-byte rune")
+	}
+}
@@ -0,0 +1,258 @@
+/*
+  File: ratelimit.go
+  Purpose: Per-client and per-tool token-bucket rate limits plus a concurrency cap for
+           embedding-backed MCP calls.
+  Author: CodeTextor project
+  Notes: Limited calls receive a structured tool error carrying retryAfterMs instead of
+         having their connection stalled or dropped.
+*/
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"CodeTextor/backend/pkg/models"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// embeddingQueueTimeout is how long a call waits for an embedding slot before it is rejected.
+	embeddingQueueTimeout = 2 * time.Second
+	// embeddingRetryAfter is the hint returned when the embedding slots stay busy.
+	embeddingRetryAfter = time.Second
+	// idleBucketTTL drops buckets that have not been used for a while.
+	idleBucketTTL = 30 * time.Minute
+)
+
+// embeddingBackedCalls lists tools and prompts that embed text on the shared model session.
+var embeddingBackedCalls = map[string]bool{
+	"search":       true,
+	"findFeature":  true,
+	"reindexFiles": true,
+	"contextPack":  true,
+}
+
+// tokenBucket is a classic token bucket; it is not safe for concurrent use on its own.
+type tokenBucket struct {
+	tokens   float64
+	last     time.Time
+	lastUsed time.Time
+}
+
+// take consumes one token, returning zero on success or the wait until a token is available.
+func (b *tokenBucket) take(limit models.MCPRateLimit, now time.Time) time.Duration {
+	capacity := float64(limit.Burst)
+	if capacity < 1 {
+		capacity = 1
+	}
+	perSecond := limit.RequestsPerMinute / 60
+	if b.last.IsZero() {
+		b.tokens = capacity
+	} else {
+		b.tokens = math.Min(capacity, b.tokens+now.Sub(b.last).Seconds()*perSecond)
+	}
+	b.last = now
+	b.lastUsed = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	missing := 1 - b.tokens
+	return time.Duration(math.Ceil(missing / perSecond * float64(time.Second)))
+}
+
+// rateLimiter tracks buckets per key and the embedding concurrency slots.
+type rateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+
+	slotsMu  sync.Mutex
+	slots    chan struct{}
+	capacity int
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{buckets: make(map[string]*tokenBucket)}
+}
+
+// allow consumes a token from the bucket identified by key.
+func (r *rateLimiter) allow(key string, limit models.MCPRateLimit, now time.Time) time.Duration {
+	if limit.RequestsPerMinute <= 0 {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if now.Sub(r.lastSweep) > idleBucketTTL {
+		for bucketKey, bucket := range r.buckets {
+			if now.Sub(bucket.lastUsed) > idleBucketTTL {
+				delete(r.buckets, bucketKey)
+			}
+		}
+		r.lastSweep = now
+	}
+
+	bucket, ok := r.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{}
+		r.buckets[key] = bucket
+	}
+	return bucket.take(limit, now)
+}
+
+// embeddingSlotKey stores the call's *embeddingSlot in the handler context.
+type embeddingSlotKey struct{}
+
+// embeddingSlot is the embedding slot held by a call. The middleware releases it when the
+// handler returns unless the handler detached it for work that outlives the call.
+type embeddingSlot struct {
+	mu       sync.Mutex
+	release  func()
+	detached bool
+}
+
+func (s *embeddingSlot) finish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.detached {
+		s.release()
+	}
+}
+
+// detachEmbeddingSlot hands the call's embedding slot to work that continues after the handler
+// returns; the returned func must be called when that work finishes. It is a no-op when the
+// call holds no slot.
+func detachEmbeddingSlot(ctx context.Context) func() {
+	slot, ok := ctx.Value(embeddingSlotKey{}).(*embeddingSlot)
+	if !ok {
+		return func() {}
+	}
+	slot.mu.Lock()
+	defer slot.mu.Unlock()
+	if slot.detached {
+		return func() {}
+	}
+	slot.detached = true
+	return slot.release
+}
+
+// acquireSlot reserves an embedding slot, waiting up to embeddingQueueTimeout.
+// The returned release func must be called when the call finishes.
+func (r *rateLimiter) acquireSlot(ctx context.Context, capacity int) (func(), bool) {
+	if capacity <= 0 {
+		return func() {}, true
+	}
+	r.slotsMu.Lock()
+	if r.slots == nil || r.capacity != capacity {
+		r.slots = make(chan struct{}, capacity)
+		r.capacity = capacity
+	}
+	slots := r.slots
+	r.slotsMu.Unlock()
+
+	release := func() { <-slots }
+	select {
+	case slots <- struct{}{}:
+		return release, true
+	default:
+	}
+
+	timer := time.NewTimer(embeddingQueueTimeout)
+	defer timer.Stop()
+	select {
+	case slots <- struct{}{}:
+		return release, true
+	case <-timer.C:
+		return nil, false
+	case <-ctx.Done():
+		return nil, false
+	}
+}
+
+// rateLimitClientKey identifies the caller: its API token when authenticated, otherwise its session.
+func rateLimitClientKey(req sdkmcp.Request) string {
+	if token := requestToken(req); token != nil {
+		return "token:" + token.ID
+	}
+	if session, ok := req.GetSession().(*sdkmcp.ServerSession); ok && session != nil {
+		return "session:" + session.ID()
+	}
+	return "anonymous"
+}
+
+type rateLimitedOutput struct {
+	Error        string `json:"error"`
+	Scope        string `json:"scope"`
+	RetryAfterMs int64  `json:"retryAfterMs"`
+}
+
+// rateLimitedResult builds the structured error returned to limited callers. Tool calls get an
+// isError result with structured content; other methods get a plain error.
+func rateLimitedResult(method, name, scope string, retryAfter time.Duration) (sdkmcp.Result, error) {
+	retryMs := retryAfter.Milliseconds()
+	if retryMs < 1 {
+		retryMs = 1
+	}
+	message := fmt.Sprintf("rate limited (%s) calling %s; retry after %dms", scope, name, retryMs)
+	if method != "tools/call" {
+		return nil, fmt.Errorf("%s", message)
+	}
+	return &sdkmcp.CallToolResult{
+		IsError: true,
+		Content: []sdkmcp.Content{&sdkmcp.TextContent{Text: message}},
+		StructuredContent: rateLimitedOutput{
+			Error:        "rate_limited",
+			Scope:        scope,
+			RetryAfterMs: retryMs,
+		},
+	}, nil
+}
+
+// rateLimitMiddleware applies the configured client/tool buckets and the embedding concurrency cap.
+func (m *Manager) rateLimitMiddleware(next sdkmcp.MethodHandler) sdkmcp.MethodHandler {
+	return func(ctx context.Context, method string, req sdkmcp.Request) (sdkmcp.Result, error) {
+		var name string
+		embeds := false
+		switch params := req.GetParams().(type) {
+		case *sdkmcp.CallToolParamsRaw:
+			name = params.Name
+			embeds = embeddingBackedCalls[name]
+		case *sdkmcp.GetPromptParams:
+			name = params.Name
+			embeds = embeddingBackedCalls[name] || (name == "reviewChanges" && params.Arguments["focus"] != "")
+		default:
+			return next(ctx, method, req)
+		}
+
+		cfg := m.GetConfig()
+		now := time.Now()
+		client := rateLimitClientKey(req)
+		if wait := m.limiter.allow(client, cfg.ClientRateLimit, now); wait > 0 {
+			return rateLimitedResult(method, name, "client", wait)
+		}
+		if limit, ok := cfg.ToolRateLimits[name]; ok {
+			if wait := m.limiter.allow(client+"|"+name, limit, now); wait > 0 {
+				return rateLimitedResult(method, name, "tool", wait)
+			}
+		}
+
+		if embeds {
+			release, ok := m.limiter.acquireSlot(ctx, cfg.MaxConcurrentEmbeddingCalls)
+			if !ok {
+				return rateLimitedResult(method, name, "concurrency", embeddingRetryAfter)
+			}
+			slot := &embeddingSlot{release: release}
+			ctx = context.WithValue(ctx, embeddingSlotKey{}, slot)
+			defer slot.finish()
+		}
+		return next(ctx, method, req)
+	}
+}
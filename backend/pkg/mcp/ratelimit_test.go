@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"CodeTextor/backend/pkg/models"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestRateLimiterBurstAndRefill(t *testing.T) {
+	limiter := newRateLimiter()
+	limit := models.MCPRateLimit{RequestsPerMinute: 60, Burst: 2}
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		if wait := limiter.allow("client", limit, now); wait != 0 {
+			t.Fatalf("call %d within burst was limited (wait %v)", i, wait)
+		}
+	}
+	wait := limiter.allow("client", limit, now)
+	if wait <= 0 || wait > time.Second {
+		t.Fatalf("expected a retry-after up to 1s, got %v", wait)
+	}
+	if wait := limiter.allow("other", limit, now); wait != 0 {
+		t.Errorf("buckets should be independent per key, got wait %v", wait)
+	}
+	if wait := limiter.allow("client", limit, now.Add(time.Second)); wait != 0 {
+		t.Errorf("bucket should refill after a second, got wait %v", wait)
+	}
+	if wait := limiter.allow("client", models.MCPRateLimit{}, now); wait != 0 {
+		t.Errorf("zero limit should be disabled, got wait %v", wait)
+	}
+}
+
+func TestAcquireSlotRejectsWhenSaturated(t *testing.T) {
+	limiter := newRateLimiter()
+	release, ok := limiter.acquireSlot(context.Background(), 1)
+	if !ok {
+		t.Fatal("first slot should be granted")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, ok := limiter.acquireSlot(ctx, 1); ok {
+		t.Error("expected saturated slots to be rejected")
+	}
+
+	release()
+	release, ok = limiter.acquireSlot(context.Background(), 1)
+	if !ok {
+		t.Fatal("slot should be available after release")
+	}
+	release()
+}
+
+func TestRateLimitedResultIsStructuredToolError(t *testing.T) {
+	result, err := rateLimitedResult("tools/call", "search", "tool", 1500*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	toolResult, ok := result.(*sdkmcp.CallToolResult)
+	if !ok || !toolResult.IsError {
+		t.Fatalf("expected an isError tool result, got %#v", result)
+	}
+	output, ok := toolResult.StructuredContent.(rateLimitedOutput)
+	if !ok || output.RetryAfterMs != 1500 || output.Scope != "tool" {
+		t.Errorf("unexpected structured content: %#v", toolResult.StructuredContent)
+	}
+
+	if _, err := rateLimitedResult("prompts/get", "findFeature", "client", time.Second); err == nil {
+		t.Error("expected prompts to receive an error")
+	}
+}
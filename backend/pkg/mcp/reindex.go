@@ -0,0 +1,131 @@
+/*
+  File: reindex.go
+  Purpose: Opt-in MCP tools that let agents refresh the index after editing files.
+  Author: CodeTextor project
+  Notes: Both tools are disabled by default and must be enabled through ToggleTool.
+*/
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"CodeTextor/backend/pkg/models"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// reindexMaxFiles caps the number of paths accepted by a single reindexFiles call.
+const reindexMaxFiles = 100
+
+type reindexFilesInput struct {
+	Paths          []string `json:"paths" jsonschema_description:"File paths relative to the project root (max 100)"`
+	ProjectID      string   `json:"projectId,omitempty" jsonschema_description:"Project id (required on the unbound /mcp endpoint)"`
+	Wait           bool     `json:"wait,omitempty" jsonschema_description:"Block until the files are re-indexed"`
+	TimeoutSeconds int      `json:"timeoutSeconds,omitempty" jsonschema_description:"Maximum wait in seconds (default 60, max 600)"`
+}
+
+type reindexFilesOutput struct {
+	ProjectID string                     `json:"projectId"`
+	Queued    bool                       `json:"queued,omitempty"`
+	TimedOut  bool                       `json:"timedOut,omitempty"`
+	Results   []models.FileReindexResult `json:"results,omitempty"`
+}
+
+type reindexProjectInput struct {
+	ProjectID      string `json:"projectId,omitempty" jsonschema_description:"Project id (required on the unbound /mcp endpoint)"`
+	Wait           bool   `json:"wait,omitempty" jsonschema_description:"Block until the indexing run finishes"`
+	TimeoutSeconds int    `json:"timeoutSeconds,omitempty" jsonschema_description:"Maximum wait in seconds (default 60, max 600)"`
+}
+
+type reindexProjectOutput struct {
+	ProjectID string                  `json:"projectId"`
+	Progress  models.IndexingProgress `json:"progress"`
+	Fresh     bool                    `json:"fresh"`
+	TimedOut  bool                    `json:"timedOut,omitempty"`
+}
+
+func (m *Manager) handleReindexFiles(boundProjectID string) sdkmcp.ToolHandlerFor[reindexFilesInput, reindexFilesOutput] {
+	return func(ctx context.Context, req *sdkmcp.CallToolRequest, input reindexFilesInput) (*sdkmcp.CallToolResult, reindexFilesOutput, error) {
+		projectID, err := m.resolveProjectID(req, boundProjectID, input.ProjectID)
+		if err != nil {
+			return nil, reindexFilesOutput{}, err
+		}
+		paths := normalizeScope(input.Paths)
+		if len(paths) == 0 {
+			return nil, reindexFilesOutput{}, fmt.Errorf("paths cannot be empty")
+		}
+		if len(paths) > reindexMaxFiles {
+			return nil, reindexFilesOutput{}, fmt.Errorf("at most %d paths can be re-indexed per call", reindexMaxFiles)
+		}
+
+		// The work runs detached from the request so a timeout or disconnect does not leave
+		// a file half re-indexed; it keeps the call's embedding slot until it finishes.
+		type reindexResult struct {
+			results []models.FileReindexResult
+			err     error
+		}
+		done := make(chan reindexResult, 1)
+		release := detachEmbeddingSlot(ctx)
+		go func() {
+			defer release()
+			results, err := m.projectService.ReindexFiles(projectID, paths)
+			if err != nil {
+				log.Printf("MCP reindexFiles failed for project %s: %v", projectID, err)
+			}
+			done <- reindexResult{results: results, err: err}
+		}()
+
+		output := reindexFilesOutput{ProjectID: projectID}
+		if !input.Wait {
+			output.Queued = true
+			return nil, output, nil
+		}
+
+		timer := time.NewTimer(waitTimeout(input.TimeoutSeconds))
+		defer timer.Stop()
+		select {
+		case result := <-done:
+			if result.err != nil {
+				return nil, reindexFilesOutput{}, result.err
+			}
+			output.Results = result.results
+			return nil, output, nil
+		case <-timer.C:
+			output.TimedOut = true
+			return nil, output, nil
+		case <-ctx.Done():
+			return nil, reindexFilesOutput{}, ctx.Err()
+		}
+	}
+}
+
+func (m *Manager) handleReindexProject(boundProjectID string) sdkmcp.ToolHandlerFor[reindexProjectInput, reindexProjectOutput] {
+	return func(ctx context.Context, req *sdkmcp.CallToolRequest, input reindexProjectInput) (*sdkmcp.CallToolResult, reindexProjectOutput, error) {
+		projectID, err := m.resolveProjectID(req, boundProjectID, input.ProjectID)
+		if err != nil {
+			return nil, reindexProjectOutput{}, err
+		}
+		if err := m.projectService.ReindexProject(projectID); err != nil {
+			return nil, reindexProjectOutput{}, err
+		}
+
+		output := reindexProjectOutput{ProjectID: projectID}
+		if !input.Wait {
+			if output.Progress, err = m.projectService.GetIndexingProgress(projectID); err != nil {
+				return nil, reindexProjectOutput{}, err
+			}
+			output.Fresh = indexingFresh(output.Progress)
+			return nil, output, nil
+		}
+
+		if output.Progress, output.TimedOut, err = m.waitForIndex(ctx, req, projectID, input.TimeoutSeconds); err != nil {
+			return nil, reindexProjectOutput{}, err
+		}
+		output.Fresh = !output.TimedOut
+		return nil, output, nil
+	}
+}
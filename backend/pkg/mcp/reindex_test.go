@@ -0,0 +1,140 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"CodeTextor/backend/pkg/models"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// reindexProjectService records reindex requests on top of the fake project service.
+type reindexProjectService struct {
+	*fakeProjectService
+	reindexed []string
+	rebuilt   []string
+}
+
+func (r *reindexProjectService) ReindexFiles(projectID string, paths []string) ([]models.FileReindexResult, error) {
+	results := make([]models.FileReindexResult, 0, len(paths))
+	for _, path := range paths {
+		r.reindexed = append(r.reindexed, projectID+":"+path)
+		results = append(results, models.FileReindexResult{Path: path, Status: models.FileReindexIndexed})
+	}
+	return results, nil
+}
+
+func (r *reindexProjectService) ReindexProject(projectID string) error {
+	r.rebuilt = append(r.rebuilt, projectID)
+	return nil
+}
+
+func (r *reindexProjectService) GetIndexingProgress(string) (models.IndexingProgress, error) {
+	return models.IndexingProgress{Status: models.IndexingStatusCompleted, TotalFiles: 2, ProcessedFiles: 2}, nil
+}
+
+func TestReindexToolsAreOptIn(t *testing.T) {
+	m := &Manager{
+		projectService: newFakeProjectService(),
+		disabledTools:  map[string]bool{},
+		enabledTools:   map[string]bool{"reindexProject": true},
+	}
+	m.initTools()
+
+	if m.tools["reindexFiles"].enabled {
+		t.Fatalf("reindexFiles should be disabled by default")
+	}
+	if !m.tools["reindexProject"].enabled {
+		t.Fatalf("reindexProject should honour the persisted opt-in")
+	}
+	if !m.tools["search"].enabled {
+		t.Fatalf("search should stay enabled by default")
+	}
+}
+
+func TestReindexFilesWaitsForResults(t *testing.T) {
+	svc := &reindexProjectService{fakeProjectService: newFakeProjectService()}
+	m := &Manager{projectService: svc}
+
+	_, output, err := m.handleReindexFiles("alpha")(context.Background(), &sdkmcp.CallToolRequest{}, reindexFilesInput{
+		Paths: []string{"main.go", " main.go ", "pkg/util.go"},
+		Wait:  true,
+	})
+	if err != nil {
+		t.Fatalf("reindexFiles failed: %v", err)
+	}
+	if output.Queued || output.TimedOut || len(output.Results) != 2 {
+		t.Fatalf("unexpected output: %+v", output)
+	}
+	if len(svc.reindexed) != 2 || svc.reindexed[0] != "alpha:main.go" {
+		t.Fatalf("unexpected reindex calls: %v", svc.reindexed)
+	}
+
+	if _, _, err := m.handleReindexFiles("alpha")(context.Background(), &sdkmcp.CallToolRequest{}, reindexFilesInput{}); err == nil {
+		t.Fatalf("expected an error for empty paths")
+	}
+}
+
+func TestReindexProjectWaitsForFreshIndex(t *testing.T) {
+	svc := &reindexProjectService{fakeProjectService: newFakeProjectService()}
+	m := &Manager{projectService: svc}
+
+	_, output, err := m.handleReindexProject("")(context.Background(), &sdkmcp.CallToolRequest{}, reindexProjectInput{ProjectID: "beta", Wait: true})
+	if err != nil {
+		t.Fatalf("reindexProject failed: %v", err)
+	}
+	if !output.Fresh || output.ProjectID != "beta" || len(svc.rebuilt) != 1 {
+		t.Fatalf("unexpected output: %+v (rebuilt %v)", output, svc.rebuilt)
+	}
+}
+
+// blockingReindexService holds ReindexFiles until gate is closed.
+type blockingReindexService struct {
+	*fakeProjectService
+	gate     chan struct{}
+	finished chan struct{}
+}
+
+func (b *blockingReindexService) ReindexFiles(string, []string) ([]models.FileReindexResult, error) {
+	<-b.gate
+	defer close(b.finished)
+	return nil, nil
+}
+
+func TestQueuedReindexFilesKeepsEmbeddingSlot(t *testing.T) {
+	svc := &blockingReindexService{fakeProjectService: newFakeProjectService(), gate: make(chan struct{}), finished: make(chan struct{})}
+	cfg := models.DefaultMCPServerConfig()
+	cfg.MaxConcurrentEmbeddingCalls = 1
+	m := &Manager{projectService: svc, config: cfg, limiter: newRateLimiter()}
+
+	handler := m.rateLimitMiddleware(func(ctx context.Context, _ string, req sdkmcp.Request) (sdkmcp.Result, error) {
+		result, _, err := m.handleReindexFiles("alpha")(ctx, req.(*sdkmcp.CallToolRequest), reindexFilesInput{Paths: []string{"main.go"}})
+		return result, err
+	})
+	req := &sdkmcp.CallToolRequest{Params: &sdkmcp.CallToolParamsRaw{Name: "reindexFiles"}}
+	if _, err := handler(context.Background(), "tools/call", req); err != nil {
+		t.Fatalf("reindexFiles failed: %v", err)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, ok := m.limiter.acquireSlot(cancelled, 1); ok {
+		t.Fatalf("expected the queued reindex to keep its embedding slot")
+	}
+
+	close(svc.gate)
+	<-svc.finished
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if release, ok := m.limiter.acquireSlot(cancelled, 1); ok {
+			release()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the slot to be released once the reindex finished")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,274 @@
+/*
+  File: resources.go
+  Purpose: MCP resources exposing indexed files and outlines with update subscriptions.
+  Author: CodeTextor project
+*/
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	resourceScheme = "codetextor"
+
+	resourceKindFile    = "file"
+	resourceKindOutline = "outline"
+
+	fileResourceTemplate    = "codetextor://{projectId}/file/{+path}"
+	outlineResourceTemplate = "codetextor://{projectId}/outline/{+path}"
+
+	fileIndexedEventName = "project:fileIndexed"
+)
+
+// resourceURI builds the canonical URI for a project file or outline resource.
+func resourceURI(projectID, kind, filePath string) string {
+	u := url.URL{
+		Scheme: resourceScheme,
+		Host:   projectID,
+		Path:   "/" + kind + "/" + strings.TrimPrefix(filePath, "/"),
+	}
+	return u.String()
+}
+
+// parseResourceURI splits a codetextor:// URI into project id, resource kind and file path.
+func parseResourceURI(raw string) (string, string, string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid resource uri %q: %w", raw, err)
+	}
+	if !strings.EqualFold(u.Scheme, resourceScheme) {
+		return "", "", "", fmt.Errorf("unsupported resource scheme %q", u.Scheme)
+	}
+	projectID := strings.TrimSpace(u.Host)
+	if projectID == "" {
+		return "", "", "", fmt.Errorf("resource uri %q is missing a project id", raw)
+	}
+
+	kind, filePath, ok := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+	if !ok || strings.TrimSpace(filePath) == "" {
+		return "", "", "", fmt.Errorf("resource uri %q is missing a file path", raw)
+	}
+	if kind != resourceKindFile && kind != resourceKindOutline {
+		return "", "", "", fmt.Errorf("unknown resource kind %q", kind)
+	}
+	cleaned := path.Clean(filePath)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", "", "", fmt.Errorf("resource path %q escapes the project root", filePath)
+	}
+	return projectID, kind, cleaned, nil
+}
+
+func resourceMIMEType(kind, filePath string) string {
+	if kind == resourceKindOutline {
+		return "application/json"
+	}
+	if mimeType := mime.TypeByExtension(path.Ext(filePath)); mimeType != "" {
+		return mimeType
+	}
+	return "text/plain"
+}
+
+// registerResources attaches the file/outline resource templates and the dynamic listing middleware.
+func (m *Manager) registerResources(s *sdkmcp.Server, boundProjectID string) {
+	handler := m.handleReadResource(boundProjectID)
+	s.AddResourceTemplate(&sdkmcp.ResourceTemplate{
+		Name:        "file",
+		Title:       "Indexed file",
+		Description: "Raw contents of an indexed file, addressed by its path relative to the project root",
+		URITemplate: fileResourceTemplate,
+	}, handler)
+	s.AddResourceTemplate(&sdkmcp.ResourceTemplate{
+		Name:        "outline",
+		Title:       "File outline",
+		Description: "Hierarchical Tree-sitter outline of an indexed file as JSON",
+		MIMEType:    "application/json",
+		URITemplate: outlineResourceTemplate,
+	}, handler)
+	s.AddReceivingMiddleware(m.resourceListMiddleware(boundProjectID))
+}
+
+// resourceListMiddleware answers resources/list from the index instead of the static
+// registry so the listing follows files as they are indexed or removed.
+func (m *Manager) resourceListMiddleware(boundProjectID string) sdkmcp.Middleware {
+	return func(next sdkmcp.MethodHandler) sdkmcp.MethodHandler {
+		return func(ctx context.Context, method string, req sdkmcp.Request) (sdkmcp.Result, error) {
+			if method != "resources/list" {
+				return next(ctx, method, req)
+			}
+			cursor := ""
+			if params, ok := req.GetParams().(*sdkmcp.ListResourcesParams); ok && params != nil {
+				cursor = params.Cursor
+			}
+			return m.listResources(req, boundProjectID, cursor)
+		}
+	}
+}
+
+func (m *Manager) listResources(req sdkmcp.Request, boundProjectID, cursor string) (*sdkmcp.ListResourcesResult, error) {
+	offset := 0
+	if strings.TrimSpace(cursor) != "" {
+		parsed, err := strconv.Atoi(cursor)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("invalid cursor %q", cursor)
+		}
+		offset = parsed
+	}
+
+	projectIDs := []string{strings.TrimSpace(boundProjectID)}
+	if projectIDs[0] == "" {
+		projects, err := m.accessibleProjects(req)
+		if err != nil {
+			return nil, err
+		}
+		projectIDs = projectIDs[:0]
+		for _, project := range projects {
+			projectIDs = append(projectIDs, project.ID)
+		}
+	}
+
+	result := &sdkmcp.ListResourcesResult{Resources: []*sdkmcp.Resource{}}
+	index := 0
+	for _, projectID := range projectIDs {
+		paths, err := m.projectService.ListIndexedFiles(projectID)
+		if err != nil {
+			return nil, err
+		}
+		for _, filePath := range paths {
+			if index < offset {
+				index++
+				continue
+			}
+			if len(result.Resources) == sdkmcp.DefaultPageSize {
+				result.NextCursor = strconv.Itoa(index)
+				return result, nil
+			}
+			result.Resources = append(result.Resources, &sdkmcp.Resource{
+				Name:     filePath,
+				Title:    fmt.Sprintf("%s (%s)", filePath, projectID),
+				URI:      resourceURI(projectID, resourceKindFile, filePath),
+				MIMEType: resourceMIMEType(resourceKindFile, filePath),
+			})
+			index++
+		}
+	}
+	return result, nil
+}
+
+func (m *Manager) resolveResourceProject(req sdkmcp.Request, boundProjectID, uri string) (string, string, string, error) {
+	projectID, kind, filePath, err := parseResourceURI(uri)
+	if err != nil {
+		return "", "", "", err
+	}
+	bound := strings.TrimSpace(boundProjectID)
+	if bound != "" && projectID != bound {
+		return "", "", "", fmt.Errorf("resource %s belongs to project %s, but this session is bound to %s", uri, projectID, bound)
+	}
+	if token := requestToken(req); !tokenAllowsProject(token, projectID) {
+		return "", "", "", fmt.Errorf("token %s is not allowed to access project %s", token.Name, projectID)
+	}
+	return projectID, kind, filePath, nil
+}
+
+func (m *Manager) handleReadResource(boundProjectID string) sdkmcp.ResourceHandler {
+	return func(_ context.Context, req *sdkmcp.ReadResourceRequest) (*sdkmcp.ReadResourceResult, error) {
+		uri := req.Params.URI
+		projectID, kind, filePath, err := m.resolveResourceProject(req, boundProjectID, uri)
+		if err != nil {
+			return nil, err
+		}
+		// Only indexed files are exposed; files under the root that the filters exclude or that
+		// were never indexed are reported as missing.
+		indexed, err := m.projectService.ListIndexedFiles(projectID)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(indexed, filePath) {
+			return nil, sdkmcp.ResourceNotFoundError(uri)
+		}
+
+		var text string
+		switch kind {
+		case resourceKindOutline:
+			nodes, err := m.projectService.GetFileOutline(projectID, filePath)
+			if err != nil {
+				return nil, sdkmcp.ResourceNotFoundError(uri)
+			}
+			encoded, err := json.Marshal(nodes)
+			if err != nil {
+				return nil, err
+			}
+			text = string(encoded)
+		default:
+			content, err := m.projectService.ReadFileContent(projectID, filePath)
+			if err != nil {
+				return nil, sdkmcp.ResourceNotFoundError(uri)
+			}
+			text = content
+		}
+
+		return &sdkmcp.ReadResourceResult{Contents: []*sdkmcp.ResourceContents{{
+			URI:      uri,
+			MIMEType: resourceMIMEType(kind, filePath),
+			Text:     text,
+		}}}, nil
+	}
+}
+
+func (m *Manager) handleSubscribe(boundProjectID string) func(context.Context, *sdkmcp.SubscribeRequest) error {
+	return func(_ context.Context, req *sdkmcp.SubscribeRequest) error {
+		_, _, _, err := m.resolveResourceProject(req, boundProjectID, req.Params.URI)
+		return err
+	}
+}
+
+func (m *Manager) handleUnsubscribe(context.Context, *sdkmcp.UnsubscribeRequest) error {
+	return nil
+}
+
+// handleProjectEvent reacts to backend events emitted by the project service.
+func (m *Manager) handleProjectEvent(event string, data interface{}) {
+	payload, ok := data.(map[string]interface{})
+	if !ok {
+		return
+	}
+	switch event {
+	case fileIndexedEventName:
+		projectID, _ := payload["projectId"].(string)
+		filePath, _ := payload["filePath"].(string)
+		if projectID == "" || filePath == "" {
+			return
+		}
+		m.notifyResourceUpdated(projectID, filePath)
+		m.logIndexEvent(projectID, "info", map[string]any{
+			"event":     "fileIndexed",
+			"projectId": projectID,
+			"filePath":  filePath,
+		})
+	case indexingStatusEventName:
+		m.handleIndexingStatus(payload)
+	}
+}
+
+// notifyResourceUpdated tells subscribed sessions that a file and its outline changed.
+func (m *Manager) notifyResourceUpdated(projectID, filePath string) {
+	ctx := context.Background()
+	for _, srv := range m.serversForProject(projectID) {
+		for _, kind := range []string{resourceKindFile, resourceKindOutline} {
+			_ = srv.ResourceUpdated(ctx, &sdkmcp.ResourceUpdatedNotificationParams{
+				URI: resourceURI(projectID, kind, filePath),
+			})
+		}
+	}
+}
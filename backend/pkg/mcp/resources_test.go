@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestResourceURIRoundTrip(t *testing.T) {
+	uri := resourceURI("my-project", resourceKindFile, "src/dir with space/main.go")
+	projectID, kind, filePath, err := parseResourceURI(uri)
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", uri, err)
+	}
+	if projectID != "my-project" || kind != resourceKindFile || filePath != "src/dir with space/main.go" {
+		t.Errorf("unexpected parse result: %q %q %q", projectID, kind, filePath)
+	}
+}
+
+func TestParseResourceURIRejectsInvalid(t *testing.T) {
+	cases := []string{
+		"file:///etc/passwd",
+		"codetextor:///file/main.go",
+		"codetextor://proj/file/",
+		"codetextor://proj/unknown/main.go",
+		"codetextor://proj/file/../../secret",
+	}
+	for _, uri := range cases {
+		if _, _, _, err := parseResourceURI(uri); err == nil {
+			t.Errorf("expected error for %s", uri)
+		}
+	}
+}
+
+// resourceProjectService serves a fixed set of indexed files on top of the fake project service.
+type resourceProjectService struct {
+	*fakeProjectService
+	indexed []string
+}
+
+func (r *resourceProjectService) ListIndexedFiles(string) ([]string, error) {
+	return r.indexed, nil
+}
+
+func (r *resourceProjectService) ReadFileContent(_ string, relativePath string) (string, error) {
+	return "contents of " + relativePath, nil
+}
+
+func TestReadResourceOnlyServesIndexedFiles(t *testing.T) {
+	m := &Manager{projectService: &resourceProjectService{fakeProjectService: newFakeProjectService(), indexed: []string{"main.go"}}}
+	read := func(filePath string) (*sdkmcp.ReadResourceResult, error) {
+		uri := resourceURI("alpha", resourceKindFile, filePath)
+		return m.handleReadResource("alpha")(context.Background(), &sdkmcp.ReadResourceRequest{Params: &sdkmcp.ReadResourceParams{URI: uri}})
+	}
+
+	result, err := read("main.go")
+	if err != nil {
+		t.Fatalf("read indexed file: %v", err)
+	}
+	if result.Contents[0].Text != "contents of main.go" {
+		t.Fatalf("unexpected contents %+v", result.Contents[0])
+	}
+	if _, err := read(".env"); err == nil {
+		t.Fatal("expected a file outside the index to be reported as not found")
+	}
+}
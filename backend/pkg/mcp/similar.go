@@ -0,0 +1,101 @@
+/*
+  File: similar.go
+  Purpose: MCP "similar" tool - "more like this" search seeded by an indexed chunk or symbol.
+  Author: CodeTextor project
+  Notes: The seed's stored embedding is used as the query vector; nothing is re-embedded.
+*/
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"CodeTextor/backend/pkg/models"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type similarInput struct {
+	ID        string `json:"id,omitempty" jsonschema_description:"Chunk or outline node id returned by search/outline to use as the seed"`
+	Symbol    string `json:"symbol,omitempty" jsonschema_description:"Symbol name to use as the seed when no id is given (first indexed declaration wins)"`
+	K         int    `json:"k,omitempty" jsonschema_description:"Max chunks to return (1-50, default 8)" jsonschema_extras:"minimum=1,maximum=50"`
+	Scope     string `json:"scope,omitempty" jsonschema_description:"any (default), otherFiles to skip the seed's file, or otherPackages to skip its package/directory"`
+	ProjectID string `json:"projectId,omitempty" jsonschema_description:"Project id (required on the unbound /mcp endpoint)"`
+}
+
+type similarSeed struct {
+	ChunkID    string `json:"chunkId"`
+	FilePath   string `json:"filePath"`
+	StartLine  int    `json:"startLine"`
+	EndLine    int    `json:"endLine"`
+	SymbolName string `json:"symbolName,omitempty"`
+	SymbolKind string `json:"symbolKind,omitempty"`
+}
+
+type similarOutput struct {
+	Seed         similarSeed     `json:"seed"`
+	Results      []*models.Chunk `json:"results"`
+	TotalResults int             `json:"totalResults"`
+	QueryTimeMs  int64           `json:"queryTimeMs"`
+}
+
+func (m *Manager) handleSimilar(boundProjectID string) sdkmcp.ToolHandlerFor[similarInput, similarOutput] {
+	return func(_ context.Context, req *sdkmcp.CallToolRequest, input similarInput) (*sdkmcp.CallToolResult, similarOutput, error) {
+		projectID, err := m.resolveProjectID(req, boundProjectID, input.ProjectID)
+		if err != nil {
+			return nil, similarOutput{}, err
+		}
+		k := input.K
+		if k <= 0 {
+			k = 8
+		}
+		if k > 50 {
+			k = 50
+		}
+
+		seedID, err := m.resolveSimilarSeed(projectID, input)
+		if err != nil {
+			return nil, similarOutput{}, err
+		}
+		resp, err := m.projectService.SimilarTo(projectID, seedID, k, models.SimilarScope(strings.TrimSpace(input.Scope)))
+		if err != nil {
+			return nil, similarOutput{}, err
+		}
+
+		seed := resp.Seed
+		return nil, similarOutput{
+			Seed: similarSeed{
+				ChunkID:    seed.ID,
+				FilePath:   seed.FilePath,
+				StartLine:  seed.LineStart,
+				EndLine:    seed.LineEnd,
+				SymbolName: seed.SymbolName,
+				SymbolKind: seed.SymbolKind,
+			},
+			Results:      resp.Chunks,
+			TotalResults: resp.TotalResults,
+			QueryTimeMs:  resp.QueryTimeMs,
+		}, nil
+	}
+}
+
+// resolveSimilarSeed returns the chunk id to seed from, looking the symbol up when no id is given.
+func (m *Manager) resolveSimilarSeed(projectID string, input similarInput) (string, error) {
+	if id := strings.TrimSpace(input.ID); id != "" {
+		return id, nil
+	}
+	symbol := strings.TrimSpace(input.Symbol)
+	if symbol == "" {
+		return "", fmt.Errorf("either id or symbol is required")
+	}
+	chunks, err := m.projectService.FindSymbolChunks(projectID, []string{symbol}, nil)
+	if err != nil {
+		return "", err
+	}
+	if len(chunks) == 0 {
+		return "", fmt.Errorf("symbol %s is not indexed in project %s", symbol, projectID)
+	}
+	return chunks[0].ID, nil
+}
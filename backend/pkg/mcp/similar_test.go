@@ -0,0 +1,54 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"CodeTextor/backend/pkg/models"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// similarProjectService resolves symbols and records the seed passed to SimilarTo.
+type similarProjectService struct {
+	*fakeProjectService
+	seedID string
+	scope  models.SimilarScope
+}
+
+func (s *similarProjectService) FindSymbolChunks(_ string, names, _ []string) ([]*models.Chunk, error) {
+	if len(names) == 1 && names[0] == "Save" {
+		return []*models.Chunk{{ID: "save-chunk", FilePath: "store.go"}}, nil
+	}
+	return nil, nil
+}
+
+func (s *similarProjectService) SimilarTo(_ string, chunkID string, k int, scope models.SimilarScope) (*models.SimilarResponse, error) {
+	s.seedID, s.scope = chunkID, scope
+	seed := &models.Chunk{ID: chunkID, FilePath: "store.go", LineStart: 3, LineEnd: 9, SymbolName: "Save"}
+	hits := []*models.Chunk{{ID: "dup", FilePath: "legacy/store.go", Similarity: 0.97}}
+	return &models.SimilarResponse{Seed: seed, Chunks: hits, TotalResults: len(hits)}, nil
+}
+
+func TestSimilarResolvesSymbolSeed(t *testing.T) {
+	svc := &similarProjectService{fakeProjectService: newFakeProjectService()}
+	m := &Manager{projectService: svc}
+
+	_, output, err := m.handleSimilar("alpha")(context.Background(), &sdkmcp.CallToolRequest{}, similarInput{Symbol: "Save", Scope: "otherFiles"})
+	if err != nil {
+		t.Fatalf("similar failed: %v", err)
+	}
+	if svc.seedID != "save-chunk" || svc.scope != models.SimilarScopeOtherFiles {
+		t.Fatalf("unexpected seed %q / scope %q", svc.seedID, svc.scope)
+	}
+	if output.Seed.SymbolName != "Save" || output.Seed.StartLine != 3 || output.TotalResults != 1 {
+		t.Fatalf("unexpected output: %+v", output)
+	}
+
+	if _, _, err := m.handleSimilar("alpha")(context.Background(), &sdkmcp.CallToolRequest{}, similarInput{Symbol: "Missing"}); err == nil {
+		t.Fatalf("expected an error for an unknown symbol")
+	}
+	if _, _, err := m.handleSimilar("alpha")(context.Background(), &sdkmcp.CallToolRequest{}, similarInput{}); err == nil {
+		t.Fatalf("expected an error without id or symbol")
+	}
+}
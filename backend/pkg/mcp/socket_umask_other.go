@@ -0,0 +1,14 @@
+//go:build !unix
+
+/*
+  File: socket_umask_other.go
+  Purpose: Socket creation without a umask on platforms that have none.
+  Author: CodeTextor project
+*/
+
+package mcp
+
+// withSocketUmask runs create as is; these platforms have no umask to tighten.
+func withSocketUmask(create func() error) error {
+	return create()
+}
@@ -0,0 +1,20 @@
+//go:build unix
+
+/*
+  File: socket_umask_unix.go
+  Purpose: Restrictive umask while the MCP Unix socket is created.
+  Author: CodeTextor project
+  Notes: The umask is process-wide; it is only held for the duration of net.Listen.
+*/
+
+package mcp
+
+import "syscall"
+
+// withSocketUmask runs create with a umask that leaves a new socket file readable and writable
+// by the owner only, so it is never reachable with wider permissions before it is chmod'ed.
+func withSocketUmask(create func() error) error {
+	previous := syscall.Umask(0o177)
+	defer syscall.Umask(previous)
+	return create()
+}
@@ -0,0 +1,96 @@
+/*
+  File: sse.go
+  Purpose: Legacy HTTP+SSE transport (protocol 2024-11-05) served next to streamable HTTP.
+  Author: CodeTextor project
+  Notes: Clients open GET /sse/<projectId> (or /sse for all projects) and POST their messages to
+         the session endpoint announced in the first "endpoint" event (same path plus ?sessionid=).
+*/
+
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const ssePathPrefix = "sse"
+
+// isSSEPath reports whether a request targets the legacy SSE transport.
+func isSSEPath(path string) bool {
+	first, _, _ := strings.Cut(strings.Trim(path, "/"), "/")
+	return strings.EqualFold(first, ssePathPrefix)
+}
+
+// newTransportHandler routes /sse requests to the legacy SSE handler and everything else to
+// the streamable HTTP handler. Both share the per-project server cache and tool toggles, and
+// long-lived responses get their write deadline lifted or extended.
+func newTransportHandler(getServer func(*http.Request) *sdkmcp.Server) http.Handler {
+	streamable := sdkmcp.NewStreamableHTTPHandler(getServer, nil)
+	sse := sdkmcp.NewSSEHandler(getServer, nil)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet:
+			// Event streams of both transports outlive the server write timeout; lift it for
+			// this response.
+			_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+		case r.Method == http.MethodPost && !isSSEPath(r.URL.Path):
+			// Streamable HTTP answers a tool call on its POST; give waiting calls the time they
+			// asked for on top of the usual write timeout.
+			if wait := toolCallWait(r); wait > 0 {
+				_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(wait + serverWriteTimeout))
+			}
+		}
+		if isSSEPath(r.URL.Path) {
+			sse.ServeHTTP(w, r)
+			return
+		}
+		streamable.ServeHTTP(w, r)
+	})
+}
+
+// waitingTools are the tools that can block until indexing finishes when called with wait.
+var waitingTools = map[string]bool{"indexStatus": true, "reindexFiles": true, "reindexProject": true}
+
+// toolCallWaitPeekBytes bounds how much of a POST body toolCallWait reads; waiting calls are small.
+const toolCallWaitPeekBytes = 64 * 1024
+
+// toolCallWait returns how long a POSTed tools/call may block, or 0 when it is not a waiting
+// call. The body is peeked and left intact for the transport.
+func toolCallWait(r *http.Request) time.Duration {
+	if r.Body == nil {
+		return 0
+	}
+	peeked, err := io.ReadAll(io.LimitReader(r.Body, toolCallWaitPeekBytes))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(peeked), r.Body), r.Body}
+	if err != nil {
+		return 0
+	}
+
+	var call struct {
+		Method string `json:"method"`
+		Params struct {
+			Name      string `json:"name"`
+			Arguments struct {
+				Wait           bool `json:"wait"`
+				TimeoutSeconds int  `json:"timeoutSeconds"`
+			} `json:"arguments"`
+		} `json:"params"`
+	}
+	if json.Unmarshal(peeked, &call) != nil || call.Method != "tools/call" {
+		return 0
+	}
+	if !waitingTools[call.Params.Name] || !call.Params.Arguments.Wait {
+		return 0
+	}
+	return waitTimeout(call.Params.Arguments.TimeoutSeconds)
+}
@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"CodeTextor/backend/pkg/models"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestExtractProjectIDFromPath(t *testing.T) {
+	cases := map[string]string{
+		"/mcp":           "",
+		"/mcp/alpha":     "alpha",
+		"/sse":           "",
+		"/sse/alpha":     "alpha",
+		"/SSE/beta/":     "beta",
+		"/legacy-id":     "legacy-id",
+		"/mcp/alpha/sub": "alpha",
+	}
+	for path, want := range cases {
+		if got := extractProjectIDFromPath(path); got != want {
+			t.Errorf("extractProjectIDFromPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestSSETransportSharesProjectServers(t *testing.T) {
+	cfg := models.DefaultMCPServerConfig()
+	cfg.AllowAnonymous = true
+	m := &Manager{
+		projectService: newFakeProjectService(),
+		config:         cfg,
+		disabledTools:  map[string]bool{"nodeSource": true},
+		enabledTools:   map[string]bool{},
+		limiter:        newRateLimiter(),
+	}
+	m.initTools()
+	if err := m.buildServerLocked(); err != nil {
+		t.Fatalf("build server: %v", err)
+	}
+	httpServer := httptest.NewServer(m.handler)
+	defer httpServer.Close()
+
+	ctx := context.Background()
+	client := sdkmcp.NewClient(&sdkmcp.Implementation{Name: "legacy-client", Version: "1.0"}, nil)
+	session, err := client.Connect(ctx, &sdkmcp.SSEClientTransport{Endpoint: httpServer.URL + "/sse/alpha"}, nil)
+	if err != nil {
+		t.Fatalf("connect over SSE: %v", err)
+	}
+	defer session.Close()
+
+	result, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("list tools: %v", err)
+	}
+	names := make(map[string]bool)
+	for _, tool := range result.Tools {
+		names[tool.Name] = true
+	}
+	if !names["search"] || names["nodeSource"] || names["listProjects"] {
+		t.Fatalf("unexpected tools for the bound SSE session: %v", names)
+	}
+
+	m.serverCache.Lock()
+	_, cached := m.boundServers["alpha"]
+	m.serverCache.Unlock()
+	if !cached {
+		t.Fatalf("expected the SSE session to reuse the cached project server")
+	}
+}
+
+func TestToolCallWaitCoversWaitingReindexCalls(t *testing.T) {
+	cases := map[string]time.Duration{
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"reindexFiles","arguments":{"paths":["a.go"],"wait":true,"timeoutSeconds":300}}}`: 300 * time.Second,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"reindexProject","arguments":{"wait":true}}}`:                                     indexStatusDefaultWait * time.Second,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"reindexFiles","arguments":{"paths":["a.go"]}}}`:                                  0,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"search","arguments":{"wait":true}}}`:                                             0,
+	}
+	for body, want := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+		if got := toolCallWait(req); got != want {
+			t.Errorf("toolCallWait(%s) = %v, want %v", body, got, want)
+		}
+		if rest, _ := io.ReadAll(req.Body); string(rest) != body {
+			t.Errorf("expected the body to be left intact, got %q", rest)
+		}
+	}
+}
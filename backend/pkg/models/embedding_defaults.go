@@ -64,13 +64,14 @@ func DefaultEmbeddingModels() []*EmbeddingModelInfo {
 			License:             "Apache-2.0",
 			DownloadStatus:      "pending",
 			CodeFocus:           "general",
+			QueryPrefix:         "Represent this sentence for searching relevant passages: ",
 		},
 		{
 			ID:                  "ibm-granite/granite-embedding-107m-multilingual",
 			DisplayName:         "Granite 107M Multilingual",
 			Backend:             "onnx",
-			Description:         "IBM Granite multilingual embedding model (768 dims).",
-			Dimension:           768,
+			Description:         "IBM Granite multilingual embedding model (384 dims).",
+			Dimension:           384,
 			DiskSizeBytes:       400 * 1024 * 1024, // ~400 MB
 			RAMRequirementBytes: 800 * 1024 * 1024, // ~0.8 GB
 			CPULatencyMs:        35,
@@ -106,6 +107,7 @@ func DefaultEmbeddingModels() []*EmbeddingModelInfo {
 			License:             "MIT",
 			DownloadStatus:      "pending",
 			CodeFocus:           "general",
+			QueryPrefix:         "Represent this sentence for searching relevant passages: ",
 		},
 		{
 			ID:                  "baai/bge-large-en-v1.5",
@@ -127,6 +129,7 @@ func DefaultEmbeddingModels() []*EmbeddingModelInfo {
 			License:             "MIT",
 			DownloadStatus:      "pending",
 			CodeFocus:           "general",
+			QueryPrefix:         "Represent this sentence for searching relevant passages: ",
 		},
 		{
 			ID:                  "intfloat/e5-base-v2",
@@ -148,6 +151,8 @@ func DefaultEmbeddingModels() []*EmbeddingModelInfo {
 			License:             "Apache-2.0",
 			DownloadStatus:      "pending",
 			CodeFocus:           "general",
+			QueryPrefix:         "query: ",
+			DocumentPrefix:      "passage: ",
 		},
 		{
 			ID:                  "intfloat/e5-large-v2",
@@ -169,6 +174,8 @@ func DefaultEmbeddingModels() []*EmbeddingModelInfo {
 			License:             "Apache-2.0",
 			DownloadStatus:      "pending",
 			CodeFocus:           "general",
+			QueryPrefix:         "query: ",
+			DocumentPrefix:      "passage: ",
 		},
 		{
 			ID:                  "intfloat/multilingual-e5-base",
@@ -190,6 +197,8 @@ func DefaultEmbeddingModels() []*EmbeddingModelInfo {
 			License:             "Apache-2.0",
 			DownloadStatus:      "pending",
 			CodeFocus:           "general",
+			QueryPrefix:         "query: ",
+			DocumentPrefix:      "passage: ",
 		},
 		{
 			ID:                  "intfloat/multilingual-e5-large",
@@ -211,6 +220,8 @@ func DefaultEmbeddingModels() []*EmbeddingModelInfo {
 			License:             "Apache-2.0",
 			DownloadStatus:      "pending",
 			CodeFocus:           "general",
+			QueryPrefix:         "query: ",
+			DocumentPrefix:      "passage: ",
 		},
 		{
 			ID:                  "nomic-ai/nomic-embed-text-v1.5",
@@ -232,13 +243,16 @@ func DefaultEmbeddingModels() []*EmbeddingModelInfo {
 			License:             "Apache-2.0",
 			DownloadStatus:      "pending",
 			CodeFocus:           "general",
+			QueryPrefix:         "search_query: ",
+			DocumentPrefix:      "search_document: ",
+			SupportsMatryoshka:  true,
 		},
 		{
 			ID:                  "nomic-ai/nomic-embed-code",
 			DisplayName:         "Nomic Embed Code",
 			Backend:             "onnx",
-			Description:         "1024-dim encoder specialized for source code.",
-			Dimension:           1024,
+			Description:         "3584-dim encoder specialized for source code.",
+			Dimension:           3584,
 			DiskSizeBytes:       500 * 1024 * 1024,
 			RAMRequirementBytes: 1200 * 1024 * 1024,
 			CPULatencyMs:        40,
@@ -253,6 +267,7 @@ func DefaultEmbeddingModels() []*EmbeddingModelInfo {
 			License:             "Apache-2.0",
 			DownloadStatus:      "pending",
 			CodeFocus:           "code",
+			QueryPrefix:         "Represent this query for searching relevant code: ",
 		},
 		{
 			ID:                  "sentence-transformers/paraphrase-multilingual-mpnet-base-v2",
@@ -0,0 +1,108 @@
+package models
+
+// EvalCase is a retrieval test: searching for Query should return ExpectedFile, and the chunk of
+// ExpectedSymbol in it when set.
+type EvalCase struct {
+	Query          string `json:"query"`
+	ExpectedFile   string `json:"expectedFile"`
+	ExpectedSymbol string `json:"expectedSymbol,omitempty"`
+	// Source is "manual" for hand-written cases and "docstring" for generated ones.
+	Source string `json:"source,omitempty"`
+}
+
+// EvalCase sources.
+const (
+	EvalCaseSourceManual    = "manual"
+	EvalCaseSourceDocstring = "docstring"
+)
+
+// EvalSearchMode is a search configuration an evaluation runs the cases with.
+type EvalSearchMode string
+
+const (
+	// EvalModeSemantic is the plain bi-encoder ranking.
+	EvalModeSemantic EvalSearchMode = "semantic"
+	// EvalModeMMR diversifies the ranking with maximal marginal relevance.
+	EvalModeMMR EvalSearchMode = "mmr"
+	// EvalModeRerank rescores the candidates with the cross-encoder (the project's reranker, or
+	// the default one).
+	EvalModeRerank EvalSearchMode = "rerank"
+	// EvalModeFused merges the rankings of every evaluated model with reciprocal rank fusion.
+	EvalModeFused EvalSearchMode = "fused"
+)
+
+// DefaultEvalK is the ranking depth used when EvalRequest.K is unset.
+const DefaultEvalK = 10
+
+// DefaultEvalGeneratedCases is how many cases are generated from docstrings when no limit is given.
+const DefaultEvalGeneratedCases = 50
+
+// EvalRequest configures a retrieval-quality evaluation.
+type EvalRequest struct {
+	ProjectID string `json:"projectId"`
+
+	// Models are the embedding models to compare; empty evaluates the project's primary model.
+	// Models the project does not keep vectors for are embedded for the run and discarded after.
+	Models []string `json:"models,omitempty"`
+
+	// Modes are the search modes to run for each model; empty runs "semantic". "fused" runs once
+	// over all evaluated models.
+	Modes []EvalSearchMode `json:"modes,omitempty"`
+
+	// K is the ranking depth for recall and MRR (default 10, max 50).
+	K int `json:"k,omitempty"`
+
+	// Cases overrides the stored cases. When neither exists, or GenerateCases is set, cases are
+	// generated from the docstrings of indexed symbols.
+	Cases         []EvalCase `json:"cases,omitempty"`
+	GenerateCases bool       `json:"generateCases,omitempty"`
+	// MaxCases caps generated cases (default 50).
+	MaxCases int `json:"maxCases,omitempty"`
+}
+
+// EvalCaseResult is the outcome of one case in one run.
+type EvalCaseResult struct {
+	Query          string `json:"query"`
+	ExpectedFile   string `json:"expectedFile"`
+	ExpectedSymbol string `json:"expectedSymbol,omitempty"`
+	// Rank is the 1-based position of the first matching chunk, 0 when it is not in the top K.
+	Rank      int     `json:"rank"`
+	LatencyMs float64 `json:"latencyMs"`
+	TopFile   string  `json:"topFile,omitempty"`
+	TopSymbol string  `json:"topSymbol,omitempty"`
+}
+
+// EvalRun reports one model and search mode.
+type EvalRun struct {
+	// Model is the embedding model, or the fused models joined with "+".
+	Model string         `json:"model"`
+	Mode  EvalSearchMode `json:"mode"`
+	// Error is set when the run could not complete; the metrics are then zero.
+	Error string `json:"error,omitempty"`
+
+	RecallAt1     float64 `json:"recallAt1"`
+	RecallAt5     float64 `json:"recallAt5"`
+	RecallAtK     float64 `json:"recallAtK"`
+	MRR           float64 `json:"mrr"`
+	MeanLatencyMs float64 `json:"meanLatencyMs"`
+	P95LatencyMs  float64 `json:"p95LatencyMs"`
+
+	// IndexBytes is the space the model's vectors take, EmbeddingDim their length. EmbedTimeMs
+	// is set when the evaluation had to embed the project with the model first.
+	IndexBytes   int64 `json:"indexBytes"`
+	EmbeddingDim int   `json:"embeddingDim,omitempty"`
+	EmbedTimeMs  int64 `json:"embedTimeMs,omitempty"`
+
+	Cases []EvalCaseResult `json:"cases,omitempty"`
+}
+
+// EvalReport is the result of an evaluation.
+type EvalReport struct {
+	ProjectID string `json:"projectId"`
+	K         int    `json:"k"`
+	CaseCount int    `json:"caseCount"`
+	// CaseSource is "request", "stored" or "docstring".
+	CaseSource string    `json:"caseSource"`
+	Runs       []EvalRun `json:"runs"`
+	DurationMs int64     `json:"durationMs"`
+}
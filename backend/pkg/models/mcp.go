@@ -13,6 +13,10 @@ type MCPServerProtocol string
 const (
 	// MCPProtocolHTTP serves MCP over the streamable HTTP transport.
 	MCPProtocolHTTP MCPServerProtocol = "http"
+	// MCPProtocolHTTPS serves the streamable HTTP transport over TLS.
+	MCPProtocolHTTPS MCPServerProtocol = "https"
+	// MCPProtocolUnix serves the streamable HTTP transport on a Unix domain socket.
+	MCPProtocolUnix MCPServerProtocol = "unix"
 	// MCPProtocolStdio serves MCP over stdio (not yet implemented).
 	MCPProtocolStdio MCPServerProtocol = "stdio"
 )
@@ -24,6 +28,32 @@ type MCPServerConfig struct {
 	Protocol       MCPServerProtocol `json:"protocol"`
 	AutoStart      bool              `json:"autoStart"`
 	MaxConnections int               `json:"maxConnections"`
+	// AllowAnonymous disables bearer-token authentication; tokens are required by default.
+	AllowAnonymous bool `json:"allowAnonymous"`
+	// TLSCertFile and TLSKeyFile point to a PEM certificate/key pair for the https protocol.
+	// When both are empty a self-signed certificate is generated under the config dir.
+	TLSCertFile string `json:"tlsCertFile,omitempty"`
+	TLSKeyFile  string `json:"tlsKeyFile,omitempty"`
+	// SocketPath is the Unix socket used by the unix protocol (defaults to <ConfigDir>/mcp.sock).
+	SocketPath string `json:"socketPath,omitempty"`
+	// SocketMode is the octal file mode applied to the socket, e.g. "0600".
+	SocketMode string `json:"socketMode,omitempty"`
+	// AuditRetentionDays and AuditMaxEntries bound the audit log; zero disables the limit.
+	AuditRetentionDays int `json:"auditRetentionDays"`
+	AuditMaxEntries    int `json:"auditMaxEntries"`
+	// ClientRateLimit bounds all calls made by one client (token, or session when anonymous).
+	ClientRateLimit MCPRateLimit `json:"clientRateLimit"`
+	// ToolRateLimits bounds calls to a given tool or prompt, per client.
+	ToolRateLimits map[string]MCPRateLimit `json:"toolRateLimits,omitempty"`
+	// MaxConcurrentEmbeddingCalls caps in-flight calls that embed a query; zero disables the cap.
+	MaxConcurrentEmbeddingCalls int `json:"maxConcurrentEmbeddingCalls"`
+}
+
+// MCPRateLimit configures a token bucket refilled at RequestsPerMinute with room for Burst calls.
+// A zero RequestsPerMinute disables the limit.
+type MCPRateLimit struct {
+	RequestsPerMinute float64 `json:"requestsPerMinute"`
+	Burst             int     `json:"burst"`
 }
 
 // DefaultMCPServerConfig returns the initial configuration used on first run.
@@ -34,6 +64,14 @@ func DefaultMCPServerConfig() MCPServerConfig {
 		Protocol:       MCPProtocolHTTP,
 		AutoStart:      false,
 		MaxConnections: 32,
+		SocketMode:     "0600",
+
+		AuditRetentionDays: 30,
+		AuditMaxEntries:    50000,
+
+		ClientRateLimit:             MCPRateLimit{RequestsPerMinute: 600, Burst: 60},
+		ToolRateLimits:              map[string]MCPRateLimit{"search": {RequestsPerMinute: 120, Burst: 20}},
+		MaxConcurrentEmbeddingCalls: 2,
 	}
 }
 
@@ -47,10 +85,68 @@ type MCPServerStatus struct {
 	LastError           string  `json:"lastError,omitempty"`
 }
 
-// MCPTool reports metadata for a registered tool along with usage stats.
+// MCPTool reports metadata for a registered tool or prompt along with usage stats.
 type MCPTool struct {
 	Name        string `json:"name"`
+	Kind        string `json:"kind"`
 	Description string `json:"description"`
 	Enabled     bool   `json:"enabled"`
 	CallCount   int64  `json:"callCount"`
 }
+
+// MCPAPIToken describes an API token accepted by the MCP HTTP server.
+// Only a hash of the secret is persisted; Prefix helps users recognise the token.
+type MCPAPIToken struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Projects   []string `json:"projects"`
+	Tools      []string `json:"tools"`
+	CreatedAt  int64    `json:"createdAt"`
+	LastUsedAt int64    `json:"lastUsedAt,omitempty"`
+}
+
+// MCPAPITokenSecret is returned once when a token is created and carries the plaintext secret.
+type MCPAPITokenSecret struct {
+	Token  MCPAPIToken `json:"token"`
+	Secret string      `json:"secret"`
+}
+
+// MCPAuditEntry records a single tool call, prompt request or resource read.
+type MCPAuditEntry struct {
+	ID            int64   `json:"id"`
+	Timestamp     int64   `json:"timestamp"` // Unix milliseconds
+	SessionID     string  `json:"sessionId,omitempty"`
+	ClientName    string  `json:"clientName,omitempty"`
+	ClientVersion string  `json:"clientVersion,omitempty"`
+	TokenName     string  `json:"tokenName,omitempty"`
+	ProjectID     string  `json:"projectId,omitempty"`
+	Method        string  `json:"method"`
+	Name          string  `json:"name"`
+	Arguments     string  `json:"arguments,omitempty"` // JSON-encoded request arguments
+	ResultBytes   int     `json:"resultBytes"`
+	DurationMs    float64 `json:"durationMs"`
+	Error         string  `json:"error,omitempty"`
+}
+
+// MCPAuditQuery filters audit log entries; zero values match everything.
+type MCPAuditQuery struct {
+	ClientName string `json:"clientName,omitempty"`
+	ProjectID  string `json:"projectId,omitempty"`
+	Name       string `json:"name,omitempty"`
+	ErrorsOnly bool   `json:"errorsOnly,omitempty"`
+	Since      int64  `json:"since,omitempty"` // Unix milliseconds, inclusive
+	Until      int64  `json:"until,omitempty"` // Unix milliseconds, exclusive
+	Limit      int    `json:"limit,omitempty"`
+	Offset     int    `json:"offset,omitempty"`
+}
+
+// MCPClientStats aggregates audit entries per MCP client.
+type MCPClientStats struct {
+	ClientName          string  `json:"clientName"`
+	ClientVersion       string  `json:"clientVersion,omitempty"`
+	CallCount           int64   `json:"callCount"`
+	ErrorCount          int64   `json:"errorCount"`
+	AverageResponseTime float64 `json:"averageResponseTime"`
+	LastSeen            int64   `json:"lastSeen"`
+}
@@ -83,7 +83,7 @@ type ProjectConfig struct {
 	// Default: "default" (uses the system's default model)
 	EmbeddingModel string `json:"embeddingModel"`
 
-	// EmbeddingBackend indicates which backend should be used (fastembed, onnx).
+	// EmbeddingBackend indicates which backend should be used (fastembed, onnx, openai, ollama).
 	EmbeddingBackend string `json:"embeddingBackend,omitempty"`
 
 	// EmbeddingModelInfo stores the snapshot of the selected embedding model metadata.
@@ -94,27 +94,88 @@ type ProjectConfig struct {
 	// MaxResponseBytes is the maximum byte size for MCP API responses.
 	// Default: 100000 (100KB)
 	MaxResponseBytes int `json:"maxResponseBytes"`
+
+	// Rerank configures the optional cross-encoder rerank stage applied to search results.
+	// Nil means reranking is disabled.
+	Rerank *RerankConfig `json:"rerank,omitempty"`
+
+	// EmbeddingStorage selects how chunk embeddings are encoded on disk
+	// (float32, float16, int8 or binary). Changing it re-encodes the stored vectors.
+	// Default: "float32"
+	EmbeddingStorage string `json:"embeddingStorage,omitempty"`
+
+	// OutputDimension truncates embeddings to their first N components (re-normalised) for
+	// models trained with Matryoshka representation learning. 0 keeps the model's full dimension.
+	OutputDimension int `json:"outputDimension,omitempty"`
+
+	// AdditionalEmbeddingModels lists models whose vectors are kept next to the primary
+	// EmbeddingModel (in chunk_embeddings, at their full dimension) so searches can pick a
+	// model or fuse results across models.
+	AdditionalEmbeddingModels []string `json:"additionalEmbeddingModels,omitempty"`
+}
+
+// MinMatryoshkaDimension is the smallest output dimension accepted for Matryoshka truncation.
+const MinMatryoshkaDimension = 64
+
+// EmbeddingDimension returns the length of the vectors the project stores: the output
+// dimension when truncation is enabled, otherwise the model's dimension (0 if unknown).
+func (c ProjectConfig) EmbeddingDimension() int {
+	if c.OutputDimension > 0 {
+		return c.OutputDimension
+	}
+	if c.EmbeddingModelInfo != nil {
+		return c.EmbeddingModelInfo.Dimension
+	}
+	return 0
+}
+
+// Embedding storage modes. float16 halves the index, int8 keeps a per-vector scale and one byte
+// per component, and binary keeps one sign bit per component: searches prefilter by Hamming
+// distance and rescore the survivors against the float query.
+const (
+	EmbeddingStorageFloat32 = "float32"
+	EmbeddingStorageFloat16 = "float16"
+	EmbeddingStorageInt8    = "int8"
+	EmbeddingStorageBinary  = "binary"
+)
+
+// RerankConfig enables a cross-encoder pass over the top bi-encoder candidates of a search.
+type RerankConfig struct {
+	// Enabled turns the rerank stage on for this project.
+	Enabled bool `json:"enabled"`
+
+	// Model is the reranker catalog id (see DefaultRerankerModels).
+	// Default: "cross-encoder/ms-marco-minilm-l-6-v2"
+	Model string `json:"model,omitempty"`
+
+	// TopN is how many bi-encoder candidates are rescored before the top-k are returned.
+	// Default: 50
+	TopN int `json:"topN,omitempty"`
 }
 
 // EmbeddingModelInfo describes an embedding model entry either from the global catalog
 // or from the per-project snapshot (stored inside project_meta).
 type EmbeddingModelInfo struct {
-	ID                   string `json:"id"`
-	DisplayName          string `json:"displayName"`
-	Backend              string `json:"backend"`
-	Description          string `json:"description,omitempty"`
-	Dimension            int    `json:"dimension"`
-	DiskSizeBytes        int64  `json:"diskSizeBytes,omitempty"`
-	RAMRequirementBytes  int64  `json:"ramRequirementBytes,omitempty"`
-	CPULatencyMs         int    `json:"cpuLatencyMs,omitempty"`
-	IsMultilingual       bool   `json:"isMultilingual"`
-	CodeQuality          string `json:"codeQuality,omitempty"`
-	Notes                string `json:"notes,omitempty"`
-	SourceType           string `json:"sourceType"` // e.g., "onnx", "huggingface"
-	SourceURI            string `json:"sourceUri,omitempty"`
-	LocalPath            string `json:"localPath,omitempty"`
-	TokenizerURI         string `json:"tokenizerUri,omitempty"`
-	TokenizerLocalPath   string `json:"tokenizerLocalPath,omitempty"`
+	ID                  string `json:"id"`
+	DisplayName         string `json:"displayName"`
+	Backend             string `json:"backend"`
+	Description         string `json:"description,omitempty"`
+	Dimension           int    `json:"dimension"`
+	DiskSizeBytes       int64  `json:"diskSizeBytes,omitempty"`
+	RAMRequirementBytes int64  `json:"ramRequirementBytes,omitempty"`
+	CPULatencyMs        int    `json:"cpuLatencyMs,omitempty"`
+	IsMultilingual      bool   `json:"isMultilingual"`
+	CodeQuality         string `json:"codeQuality,omitempty"`
+	Notes               string `json:"notes,omitempty"`
+	SourceType          string `json:"sourceType"` // e.g., "onnx", "huggingface"
+	SourceURI           string `json:"sourceUri,omitempty"`
+	LocalPath           string `json:"localPath,omitempty"`
+	TokenizerURI        string `json:"tokenizerUri,omitempty"`
+	TokenizerLocalPath  string `json:"tokenizerLocalPath,omitempty"`
+	// SHA256 and TokenizerSHA256 are the expected hex digests of the model and tokenizer files.
+	// Downloads that do not match are discarded; empty values skip verification.
+	SHA256               string `json:"sha256,omitempty"`
+	TokenizerSHA256      string `json:"tokenizerSha256,omitempty"`
 	License              string `json:"license,omitempty"`
 	DownloadStatus       string `json:"downloadStatus,omitempty"` // e.g., "pending", "ready"
 	RequiresConversion   bool   `json:"requiresConversion,omitempty"`
@@ -124,7 +185,36 @@ type EmbeddingModelInfo struct {
 	CodeFocus            string `json:"codeFocus,omitempty"` // e.g., "general", "code-specialized"
 	EstimatedTokensPerS  int    `json:"estimatedTokensPerSecond,omitempty"`
 	SupportsQuantization bool   `json:"supportsQuantization,omitempty"`
-	MaxSequenceLength    int    `json:"maxSequenceLength,omitempty"`
+	// SupportsMatryoshka marks models whose leading components form valid smaller embeddings.
+	SupportsMatryoshka bool `json:"supportsMatryoshka,omitempty"`
+	MaxSequenceLength  int  `json:"maxSequenceLength,omitempty"`
+
+	// Remote HTTP backends ("openai", "ollama") call an embedding server instead of a local runtime.
+	// BaseURL is the server root; for openai with or without the trailing /v1 (e.g. http://localhost:8080/v1),
+	// for ollama it defaults to OLLAMA_HOST or http://localhost:11434.
+	BaseURL string `json:"baseUrl,omitempty"`
+	// RemoteModel is the model name sent to the server; defaults to ID.
+	RemoteModel string `json:"remoteModel,omitempty"`
+	// APIKey is sent as a bearer token when set. It is kept in the config DB only and
+	// stripped from per-project snapshots.
+	APIKey string `json:"apiKey,omitempty"`
+	// BatchSize caps texts per request (default 32).
+	BatchSize int `json:"batchSize,omitempty"`
+	// MaxRetries is how often a failed request is retried on network errors, 429 and 5xx (default 3).
+	MaxRetries int `json:"maxRetries,omitempty"`
+	// TimeoutSeconds bounds a single request (default 60).
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+
+	// QueryPrefix and DocumentPrefix are the instructions asymmetric models expect in front of
+	// search queries and indexed chunks (e.g. "query: " / "passage: " for e5). Changing them
+	// requires re-indexing.
+	QueryPrefix    string `json:"queryPrefix,omitempty"`
+	DocumentPrefix string `json:"documentPrefix,omitempty"`
+}
+
+// IsRemote reports whether the model is served over HTTP rather than by a local runtime.
+func (m *EmbeddingModelInfo) IsRemote() bool {
+	return m != nil && (strings.EqualFold(m.Backend, "openai") || strings.EqualFold(m.Backend, "ollama"))
 }
 
 // Clone returns a deep copy of the metadata to avoid sharing pointers.
@@ -159,6 +249,9 @@ const (
 	IndexingStatusCompleted IndexingStatus = "completed"
 	// IndexingStatusError indicates the indexer stopped due to an error.
 	IndexingStatusError IndexingStatus = "error"
+	// IndexingStatusMigrating indicates chunks are being re-embedded with a new model in the
+	// background while the previous embeddings keep serving queries.
+	IndexingStatusMigrating IndexingStatus = "migrating"
 )
 
 // IndexingProgress represents the current state of an indexing operation.
@@ -168,6 +261,36 @@ type IndexingProgress struct {
 	CurrentFile    string         `json:"currentFile"`
 	Status         IndexingStatus `json:"status"` // e.g., "idle", "indexing", "completed", "error"
 	Error          string         `json:"error,omitempty"`
+
+	// MigrationModel is the model chunks are being re-embedded with while Status is "migrating";
+	// TotalChunks and ProcessedChunks count the chunks covered so far. MigrationBackfill is set
+	// when the job only adds embeddings for additional models instead of switching models.
+	MigrationModel    string `json:"migrationModel,omitempty"`
+	MigrationBackfill bool   `json:"migrationBackfill,omitempty"`
+	TotalChunks       int    `json:"totalChunks,omitempty"`
+	ProcessedChunks   int    `json:"processedChunks,omitempty"`
+}
+
+// FileReindexStatus describes the outcome of re-indexing a single file on request.
+type FileReindexStatus string
+
+const (
+	// FileReindexIndexed indicates the file was re-chunked and re-embedded.
+	FileReindexIndexed FileReindexStatus = "indexed"
+	// FileReindexUnchanged indicates the stored index already matched the file on disk.
+	FileReindexUnchanged FileReindexStatus = "unchanged"
+	// FileReindexFailed indicates the file could not be re-indexed.
+	FileReindexFailed FileReindexStatus = "failed"
+	// FileReindexExcluded indicates the file is outside the project's include/exclude filters
+	// and was not indexed.
+	FileReindexExcluded FileReindexStatus = "excluded"
+)
+
+// FileReindexResult reports what happened to one file passed to ReindexFiles.
+type FileReindexResult struct {
+	Path   string            `json:"path"`
+	Status FileReindexStatus `json:"status"`
+	Error  string            `json:"error,omitempty"`
 }
 
 // ProjectStats contains current statistics about a project's index.
@@ -197,6 +320,16 @@ type ProjectStats struct {
 	// LastEmbeddingModel describes the embedding model that produced the current DB contents.
 	LastEmbeddingModel *EmbeddingModelInfo `json:"lastEmbeddingModel,omitempty"`
 
+	// EmbeddingStorage is the project's embedding storage mode (see EmbeddingStorageFloat32).
+	EmbeddingStorage string `json:"embeddingStorage,omitempty"`
+
+	// EmbeddingBytes is the space taken by stored embedding vectors, including the vectors of
+	// additional models in chunk_embeddings.
+	EmbeddingBytes int64 `json:"embeddingBytes,omitempty"`
+
+	// EmbeddingBytesSaved is how much smaller the vectors are than float32 storage would be.
+	EmbeddingBytesSaved int64 `json:"embeddingBytesSaved,omitempty"`
+
 	// IsIndexing indicates whether the project is currently being indexed
 	IsIndexing bool `json:"isIndexing"`
 
@@ -207,13 +340,21 @@ type ProjectStats struct {
 // EmbeddingCapabilities describes available embedding backends on this machine.
 type EmbeddingCapabilities struct {
 	OnnxRuntimeAvailable bool `json:"onnxRuntimeAvailable"`
+	// OllamaAvailable reports whether an Ollama server answered at OllamaBaseURL.
+	OllamaAvailable bool   `json:"ollamaAvailable"`
+	OllamaBaseURL   string `json:"ollamaBaseUrl,omitempty"`
 }
 
 // ProjectEmbeddingModelUsage summarizes how many chunks were built with a specific model.
 type ProjectEmbeddingModelUsage struct {
-	ModelID   string               `json:"modelId"`
+	ModelID    string              `json:"modelId"`
 	ChunkCount int                 `json:"chunkCount"`
-	ModelInfo *EmbeddingModelInfo  `json:"modelInfo,omitempty"`
+	ModelInfo  *EmbeddingModelInfo `json:"modelInfo,omitempty"`
+	// Coverage is ChunkCount as a fraction of all chunks (0.0 to 1.0).
+	Coverage float64 `json:"coverage"`
+	// Primary is true for vectors stored on the chunks themselves and false for additional
+	// models (or a model switch in progress) kept in chunk_embeddings.
+	Primary bool `json:"primary"`
 }
 
 // OutlineNode represents the hierarchical structure of a file that was parsed by Tree-sitter.
@@ -230,19 +371,20 @@ type OutlineNode struct {
 // Chunk represents a piece of text from a file, along with its embedding.
 // Extended with semantic chunking metadata for better code understanding.
 type Chunk struct {
-	ID         string    `json:"id"`
-	ProjectID  string    `json:"projectId"`
-	FilePath   string    `json:"filePath"`
-	Content    string    `json:"content"` // Enriched content with metadata headers
-	Embedding  []float32 `json:"embedding"`
-	EmbeddingModelID string `json:"embeddingModelId,omitempty"`
-	Similarity float64   `json:"similarity,omitempty"`
-	LineStart  int       `json:"lineStart"`
-	LineEnd    int       `json:"lineEnd"`
-	CharStart  int       `json:"charStart"`
-	CharEnd    int       `json:"charEnd"`
-	CreatedAt  int64     `json:"createdAt"`
-	UpdatedAt  int64     `json:"updatedAt"`
+	ID               string    `json:"id"`
+	ProjectID        string    `json:"projectId"`
+	FilePath         string    `json:"filePath"`
+	Content          string    `json:"content"` // Enriched content with metadata headers
+	Embedding        []float32 `json:"embedding"`
+	EmbeddingModelID string    `json:"embeddingModelId,omitempty"`
+	Similarity       float64   `json:"similarity,omitempty"`
+	RerankScore      float64   `json:"rerankScore,omitempty"` // Cross-encoder relevance (0-1) when reranking ran
+	LineStart        int       `json:"lineStart"`
+	LineEnd          int       `json:"lineEnd"`
+	CharStart        int       `json:"charStart"`
+	CharEnd          int       `json:"charEnd"`
+	CreatedAt        int64     `json:"createdAt"`
+	UpdatedAt        int64     `json:"updatedAt"`
 
 	// Semantic chunking metadata
 	Language    string `json:"language,omitempty"`    // Programming language (e.g., "go", "python")
@@ -256,6 +398,9 @@ type Chunk struct {
 	TokenCount  int    `json:"tokenCount,omitempty"`  // Estimated token count
 	IsCollapsed bool   `json:"isCollapsed,omitempty"` // Whether body was collapsed
 	SourceCode  string `json:"sourceCode,omitempty"`  // Raw source code without enrichment
+
+	// MergedChunkIDs lists the chunks folded into this search result when contiguous hits are merged.
+	MergedChunkIDs []string `json:"mergedChunkIds,omitempty"`
 }
 
 // File represents a file that has been indexed.
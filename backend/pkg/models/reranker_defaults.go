@@ -0,0 +1,77 @@
+/*
+  File: reranker_defaults.go
+  Purpose: Provide the cross-encoder reranker catalog used by the optional search rerank stage.
+  Author: CodeTextor project
+  Notes: Rerankers reuse EmbeddingModelInfo so the Downloader can fetch them; Dimension is unused.
+*/
+
+package models
+
+import "strings"
+
+// DefaultRerankerModelID is used when a project enables reranking without picking a model.
+const DefaultRerankerModelID = "cross-encoder/ms-marco-minilm-l-6-v2"
+
+// DefaultRerankTopN is how many bi-encoder candidates are rescored by default.
+const DefaultRerankTopN = 50
+
+// MaxRerankTopN caps the candidate pool so a single search stays interactive.
+const MaxRerankTopN = 200
+
+// DefaultRerankerModels returns the cross-encoder models CodeTextor can download for reranking.
+func DefaultRerankerModels() []*EmbeddingModelInfo {
+	return []*EmbeddingModelInfo{
+		{
+			ID:                  DefaultRerankerModelID,
+			DisplayName:         "MS MARCO MiniLM L-6 (reranker)",
+			Backend:             "onnx",
+			Description:         "Small English cross-encoder trained on MS MARCO passage ranking.",
+			Dimension:           1,
+			DiskSizeBytes:       90 * 1024 * 1024, // ~90 MB
+			RAMRequirementBytes: 256 * 1024 * 1024,
+			CPULatencyMs:        8,
+			CodeQuality:         "fair",
+			Notes:               "Requires ONNX Runtime. Fast enough to rescore ~50 candidates per query.",
+			SourceType:          "onnx",
+			SourceURI:           "https://huggingface.co/cross-encoder/ms-marco-MiniLM-L-6-v2/resolve/main/onnx/model.onnx?download=1",
+			TokenizerURI:        "https://huggingface.co/cross-encoder/ms-marco-MiniLM-L-6-v2/resolve/main/tokenizer.json?download=1",
+			PreferredFilename:   "model.onnx",
+			MaxSequenceLength:   512,
+			License:             "Apache-2.0",
+			DownloadStatus:      "pending",
+			CodeFocus:           "general",
+		},
+		{
+			ID:                  "baai/bge-reranker-base",
+			DisplayName:         "BGE Reranker Base",
+			Backend:             "onnx",
+			Description:         "Multilingual cross-encoder from BAAI; slower but more accurate.",
+			Dimension:           1,
+			DiskSizeBytes:       1100 * 1024 * 1024, // ~1.1 GB
+			RAMRequirementBytes: 1536 * 1024 * 1024,
+			CPULatencyMs:        60,
+			IsMultilingual:      true,
+			CodeQuality:         "good",
+			Notes:               "Requires ONNX Runtime. Lower TopN if search latency becomes noticeable.",
+			SourceType:          "onnx",
+			SourceURI:           "https://huggingface.co/BAAI/bge-reranker-base/resolve/main/onnx/model.onnx?download=1",
+			TokenizerURI:        "https://huggingface.co/BAAI/bge-reranker-base/resolve/main/tokenizer.json?download=1",
+			PreferredFilename:   "model.onnx",
+			MaxSequenceLength:   512,
+			License:             "MIT",
+			DownloadStatus:      "pending",
+			CodeFocus:           "general",
+		},
+	}
+}
+
+// FindRerankerModel returns a copy of the catalog entry with the given id (case-insensitive), or nil.
+func FindRerankerModel(id string) *EmbeddingModelInfo {
+	id = strings.TrimSpace(id)
+	for _, entry := range DefaultRerankerModels() {
+		if strings.EqualFold(entry.ID, id) {
+			return entry
+		}
+	}
+	return nil
+}
@@ -5,6 +5,28 @@ type SearchResponse struct {
 	Chunks       []*Chunk `json:"chunks"`
 	TotalResults int      `json:"totalResults"`
 	QueryTimeMs  int64    `json:"queryTime"`
+
+	// TotalResults counts every chunk at or above MinScore, not just this page.
+	// Offset is the position of the first chunk of this page in the full ranking.
+	Offset int `json:"offset"`
+
+	// NextCursor fetches the following page; empty when there are no more results.
+	NextCursor string `json:"nextCursor,omitempty"`
+
+	// Rerank reports the cross-encoder stage; nil when reranking is disabled for the project.
+	Rerank *RerankStats `json:"rerank,omitempty"`
+
+	// EmbeddingModels lists the models whose vectors ranked the results.
+	EmbeddingModels []string `json:"embeddingModels,omitempty"`
+}
+
+// RerankStats describes how the cross-encoder stage reordered a search.
+type RerankStats struct {
+	Model      string `json:"model"`
+	Candidates int    `json:"candidates"`
+	LatencyMs  int64  `json:"latencyMs"`
+	// Error is set when the reranker could not run; results then keep their bi-encoder order.
+	Error string `json:"error,omitempty"`
 }
 
 // SearchRequest represents a semantic search query.
@@ -12,4 +34,59 @@ type SearchRequest struct {
 	ProjectID string `json:"projectId"`
 	Query     string `json:"query"`
 	K         int    `json:"k"`
+
+	// MMR reorders candidates by maximal marginal relevance so near-duplicate chunks do not crowd out others.
+	MMR bool `json:"mmr,omitempty"`
+
+	// MMRLambda weighs relevance (1.0) against diversity (0.0) when MMR is on.
+	// Default: 0.7
+	MMRLambda float64 `json:"mmrLambda,omitempty"`
+
+	// MaxPerFile caps how many results may come from the same file (0 = unlimited).
+	MaxPerFile int `json:"maxPerFile,omitempty"`
+
+	// MergeContiguous merges overlapping or adjacent hits from one file into a single result range.
+	MergeContiguous bool `json:"mergeContiguous,omitempty"`
+
+	// MinScore drops chunks whose cosine similarity is below it (0 = no threshold).
+	MinScore float64 `json:"minScore,omitempty"`
+
+	// Cursor continues a previous search from its NextCursor. The query and options must match.
+	Cursor string `json:"cursor,omitempty"`
+
+	// EmbeddingModel searches the vectors of one of the project's models (the primary model or
+	// one of its additional models). Empty uses the primary model.
+	EmbeddingModel string `json:"embeddingModel,omitempty"`
+
+	// FuseModels searches with every model of the project and merges the rankings with
+	// reciprocal rank fusion. EmbeddingModel is ignored when set.
+	FuseModels bool `json:"fuseModels,omitempty"`
+}
+
+// DefaultMMRLambda is the relevance/diversity trade-off used when MMRLambda is unset.
+const DefaultMMRLambda = 0.7
+
+// Diversified reports whether the request asks for any post-processing beyond plain top-k.
+func (r SearchRequest) Diversified() bool {
+	return r.MMR || r.MaxPerFile > 0 || r.MergeContiguous
+}
+
+// SimilarScope restricts where "more like this" results may come from.
+type SimilarScope string
+
+const (
+	// SimilarScopeAny allows results from anywhere except the seed's own range.
+	SimilarScopeAny SimilarScope = "any"
+	// SimilarScopeOtherFiles excludes every chunk from the seed's file.
+	SimilarScopeOtherFiles SimilarScope = "otherFiles"
+	// SimilarScopeOtherPackages excludes chunks from the seed's package (or directory when no package is known).
+	SimilarScopeOtherPackages SimilarScope = "otherPackages"
+)
+
+// SimilarResponse lists chunks whose stored embeddings are closest to a seed chunk.
+type SimilarResponse struct {
+	Seed         *Chunk   `json:"seed"`
+	Chunks       []*Chunk `json:"chunks"`
+	TotalResults int      `json:"totalResults"`
+	QueryTimeMs  int64    `json:"queryTime"`
 }
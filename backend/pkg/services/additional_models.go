@@ -0,0 +1,231 @@
+package services
+
+import (
+	"CodeTextor/backend/pkg/embedding"
+	"CodeTextor/backend/pkg/models"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// additionalModelConfig returns a copy of base that embeds with modelID at its full dimension.
+// It fails when the model is unknown or cannot run here instead of falling back to the default.
+func (s *ProjectService) additionalModelConfig(base models.ProjectConfig, modelID string) (models.ProjectConfig, error) {
+	config := base
+	config.EmbeddingModel = modelID
+	config.EmbeddingModelInfo = nil
+	config.OutputDimension = 0
+	config.AdditionalEmbeddingModels = nil
+	if err := s.ensureEmbeddingModelSnapshot(&config); err != nil {
+		return config, err
+	}
+	if !strings.EqualFold(config.EmbeddingModel, modelID) {
+		return config, fmt.Errorf("embedding model %s cannot be used on this machine", modelID)
+	}
+	return config, nil
+}
+
+// additionalClientKey identifies the cached client of one additional model of a project.
+type additionalClientKey struct {
+	projectID string
+	modelID   string
+}
+
+// cachedAdditionalClient is a cached additional-model client. ONNX clients are shared with
+// s.embeddingClients, which owns and closes them.
+type cachedAdditionalClient struct {
+	client embedding.EmbeddingClient
+	shared bool
+}
+
+// additionalEmbeddingClient returns the embedding client of one of the project's additional models.
+// Clients are cached per project so searches neither rebuild them nor probe the backend again.
+func (s *ProjectService) additionalEmbeddingClient(project *models.Project, modelID string) (embedding.EmbeddingClient, error) {
+	key := additionalClientKey{projectID: project.ID, modelID: strings.ToLower(modelID)}
+	s.clientsMu.Lock()
+	cached, ok := s.additionalClients[key]
+	s.clientsMu.Unlock()
+	if ok {
+		return cached.client, nil
+	}
+
+	config, err := s.additionalModelConfig(project.Config, modelID)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.configEmbeddingClient(project.ID, &config)
+	if err != nil {
+		return nil, err
+	}
+	entry := cachedAdditionalClient{client: client, shared: strings.EqualFold(config.EmbeddingModelInfo.Backend, "onnx")}
+
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	if existing, ok := s.additionalClients[key]; ok {
+		// Another caller built the same client first; keep theirs.
+		if !entry.shared {
+			entry.client.Close()
+		}
+		return existing.client, nil
+	}
+	s.additionalClients[key] = entry
+	return client, nil
+}
+
+// dropAdditionalClients removes the cached additional-model clients matched by match and closes
+// the ones the cache owns.
+func (s *ProjectService) dropAdditionalClients(match func(projectID, modelID string) bool) {
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	for key, entry := range s.additionalClients {
+		if !match(key.projectID, key.modelID) {
+			continue
+		}
+		if !entry.shared {
+			if err := entry.client.Close(); err != nil {
+				log.Printf("Failed to close %s embedding client of project %s: %v", key.modelID, key.projectID, err)
+			}
+		}
+		delete(s.additionalClients, key)
+	}
+}
+
+// additionalEmbeddingClients builds clients for every additional model of the project, keyed by
+// the configured model ID. Models that cannot be loaded are logged and skipped so they never
+// block indexing with the primary model.
+func (s *ProjectService) additionalEmbeddingClients(project *models.Project) map[string]embedding.EmbeddingClient {
+	if len(project.Config.AdditionalEmbeddingModels) == 0 {
+		return nil
+	}
+	clients := make(map[string]embedding.EmbeddingClient, len(project.Config.AdditionalEmbeddingModels))
+	for _, modelID := range project.Config.AdditionalEmbeddingModels {
+		client, err := s.additionalEmbeddingClient(project, modelID)
+		if err != nil {
+			log.Printf("Skipping additional embedding model %s for project %s: %v", modelID, project.ID, err)
+			continue
+		}
+		clients[modelID] = client
+	}
+	return clients
+}
+
+// normalizeAdditionalModels trims and de-duplicates the additional models, drops the primary
+// model and validates models that were not configured before.
+func (s *ProjectService) normalizeAdditionalModels(config *models.ProjectConfig, previous []string) error {
+	normalized := make([]string, 0, len(config.AdditionalEmbeddingModels))
+	for _, modelID := range config.AdditionalEmbeddingModels {
+		modelID = strings.TrimSpace(modelID)
+		if modelID == "" || strings.EqualFold(modelID, config.EmbeddingModel) || containsModelID(normalized, modelID) {
+			continue
+		}
+		if !containsModelID(previous, modelID) {
+			if _, err := s.additionalModelConfig(*config, modelID); err != nil {
+				return fmt.Errorf("invalid additional embedding model: %w", err)
+			}
+		}
+		normalized = append(normalized, modelID)
+	}
+	if len(normalized) == 0 {
+		normalized = nil
+	}
+	config.AdditionalEmbeddingModels = normalized
+	return nil
+}
+
+// syncAdditionalEmbeddings reconciles stored vectors with the saved list of additional models:
+// vectors of removed models are dropped and added models are embedded in the background. A
+// running indexer is restarted instead so its clients match the list; its run fills the gaps.
+func (s *ProjectService) syncAdditionalEmbeddings(project *models.Project, previous []string) {
+	current := project.Config.AdditionalEmbeddingModels
+	// Clients of removed models are closed once a running indexer has been restarted without them.
+	defer s.dropAdditionalClients(func(projectID, modelID string) bool {
+		return projectID == project.ID && !containsModelID(current, modelID)
+	})
+	var added []string
+	for _, modelID := range current {
+		if !containsModelID(previous, modelID) {
+			added = append(added, modelID)
+		}
+	}
+	removed := false
+	for _, modelID := range previous {
+		if containsModelID(current, modelID) {
+			continue
+		}
+		removed = true
+		vectorStore, err := s.GetVectorStore(project.ID)
+		if err == nil {
+			err = vectorStore.DeleteModelEmbeddings(modelID)
+		}
+		if err != nil {
+			log.Printf("Failed to drop %s embeddings of project %s: %v", modelID, project.ID, err)
+		}
+	}
+	if len(added) == 0 && !removed {
+		return
+	}
+
+	if project.IsIndexing && s.indexerManager.IsIndexerRunning(project.ID) {
+		if err := s.StartIndexing(project.ID); err != nil {
+			log.Printf("Failed to restart indexing of %s with the new embedding models: %v", project.ID, err)
+		}
+		return
+	}
+	if len(added) == 0 {
+		return
+	}
+	err := s.indexerManager.StartModelBackfill(project.ID, added, func(ctx context.Context, progress *models.IndexingProgress) error {
+		return s.backfillAdditionalModels(ctx, project.ID, added, progress)
+	})
+	if err != nil {
+		log.Printf("Embeddings for %s will be added by the next indexing run of %s: %v", strings.Join(added, ", "), project.ID, err)
+	}
+}
+
+// backfillAdditionalModels embeds the indexed chunks with each of modelIDs. Vectors stored before
+// a failure or cancellation are kept; indexing runs complete them later.
+func (s *ProjectService) backfillAdditionalModels(ctx context.Context, projectID string, modelIDs []string, progress *models.IndexingProgress) error {
+	project, err := s.GetProject(projectID)
+	if err != nil {
+		return err
+	}
+	vectorStore, err := s.GetVectorStore(projectID)
+	if err != nil {
+		return err
+	}
+	for _, modelID := range modelIDs {
+		client, err := s.additionalEmbeddingClient(project, modelID)
+		if err != nil {
+			return err
+		}
+		if err := s.embedMissingChunks(ctx, projectID, vectorStore, client, modelID, progress); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveProjectModel maps a requested model to the project's primary model ("" when it is the
+// primary one) or to the configured spelling of one of its additional models.
+func resolveProjectModel(config models.ProjectConfig, modelID string) (string, error) {
+	modelID = strings.TrimSpace(modelID)
+	if modelID == "" || strings.EqualFold(modelID, config.EmbeddingModel) {
+		return "", nil
+	}
+	for _, additional := range config.AdditionalEmbeddingModels {
+		if strings.EqualFold(additional, modelID) {
+			return additional, nil
+		}
+	}
+	return "", fmt.Errorf("embedding model %s is not configured for this project", modelID)
+}
+
+func containsModelID(modelIDs []string, modelID string) bool {
+	for _, candidate := range modelIDs {
+		if strings.EqualFold(candidate, modelID) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"testing"
+
+	"CodeTextor/backend/pkg/models"
+)
+
+func TestAdditionalEmbeddingModelsBackfillAndSearch(t *testing.T) {
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+	project := setupMigrationProject(t, service, 5, newMigrationTestServer(t, 3, nil))
+
+	config := project.Config
+	config.AdditionalEmbeddingModels = []string{" local/new ", "LOCAL/NEW", "local/old"}
+	project, err := service.UpdateProjectConfig(project.ID, config)
+	if err != nil {
+		t.Fatalf("add model: %v", err)
+	}
+	if got := project.Config.AdditionalEmbeddingModels; len(got) != 1 || got[0] != "local/new" {
+		t.Fatalf("expected the list to be normalized to local/new, got %v", got)
+	}
+	if progress := waitForMigration(t, service, project.ID); progress.Status != models.IndexingStatusCompleted || !progress.MigrationBackfill {
+		t.Fatalf("unexpected backfill progress %+v", progress)
+	}
+
+	stats, err := service.GetProjectStats(project.ID)
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	if len(stats.EmbeddingModels) != 2 || !stats.EmbeddingModels[0].Primary || stats.EmbeddingModels[1].ModelID != "local/new" || stats.EmbeddingModels[1].Coverage != 1 {
+		t.Fatalf("expected full coverage for both models, got %+v", stats.EmbeddingModels)
+	}
+
+	resp, err := service.SearchWithOptions(models.SearchRequest{ProjectID: project.ID, Query: "f", K: 3, EmbeddingModel: "local/new"})
+	if err != nil {
+		t.Fatalf("search by model: %v", err)
+	}
+	if len(resp.Chunks) != 3 || len(resp.EmbeddingModels) != 1 || resp.EmbeddingModels[0] != "local/new" {
+		t.Fatalf("expected local/new to rank the results, got %d chunks from %v", len(resp.Chunks), resp.EmbeddingModels)
+	}
+	resp, err = service.SearchWithOptions(models.SearchRequest{ProjectID: project.ID, Query: "f", K: 10, FuseModels: true})
+	if err != nil {
+		t.Fatalf("fused search: %v", err)
+	}
+	if len(resp.Chunks) != 5 || len(resp.EmbeddingModels) != 2 || resp.TotalResults != 5 {
+		t.Fatalf("expected every chunk once from both models, got %d chunks from %v", len(resp.Chunks), resp.EmbeddingModels)
+	}
+	if _, err := service.SearchWithOptions(models.SearchRequest{ProjectID: project.ID, Query: "f", EmbeddingModel: "local/other"}); err == nil {
+		t.Fatalf("expected a model outside the project to be rejected")
+	}
+
+	config = project.Config
+	config.AdditionalEmbeddingModels = nil
+	if _, err := service.UpdateProjectConfig(project.ID, config); err != nil {
+		t.Fatalf("remove model: %v", err)
+	}
+	vectorStore, _ := service.GetVectorStore(project.ID)
+	if covered, _, _ := vectorStore.ModelEmbeddingCoverage("local/new"); covered != 0 {
+		t.Fatalf("expected removed model vectors to be dropped, %d left", covered)
+	}
+}
+
+func TestMigrateToAdditionalModelReusesVectors(t *testing.T) {
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+	project := setupMigrationProject(t, service, 4, newMigrationTestServer(t, 3, nil))
+
+	config := project.Config
+	config.AdditionalEmbeddingModels = []string{"local/new"}
+	if _, err := service.UpdateProjectConfig(project.ID, config); err != nil {
+		t.Fatalf("add model: %v", err)
+	}
+	waitForMigration(t, service, project.ID)
+
+	if err := service.MigrateEmbeddingModel(project.ID, "local/new"); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if progress := waitForMigration(t, service, project.ID); progress.Status != models.IndexingStatusCompleted {
+		t.Fatalf("unexpected final progress %+v", progress)
+	}
+	updated, _ := service.GetProject(project.ID)
+	if updated.Config.EmbeddingModel != "local/new" || len(updated.Config.AdditionalEmbeddingModels) != 0 {
+		t.Fatalf("expected local/new to become the only model, got %s + %v", updated.Config.EmbeddingModel, updated.Config.AdditionalEmbeddingModels)
+	}
+	stats, _ := service.GetProjectStats(project.ID)
+	if len(stats.EmbeddingModels) != 1 || stats.EmbeddingModels[0].ModelID != "local/new" {
+		t.Fatalf("expected only local/new embeddings, got %+v", stats.EmbeddingModels)
+	}
+}
+
+func TestAdditionalEmbeddingClientIsCachedUntilRemoved(t *testing.T) {
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+	project := setupMigrationProject(t, service, 2, newMigrationTestServer(t, 3, nil))
+
+	config := project.Config
+	config.AdditionalEmbeddingModels = []string{"local/new"}
+	project, err := service.UpdateProjectConfig(project.ID, config)
+	if err != nil {
+		t.Fatalf("add model: %v", err)
+	}
+	waitForMigration(t, service, project.ID)
+
+	first, err := service.additionalEmbeddingClient(project, "local/new")
+	if err != nil {
+		t.Fatalf("client: %v", err)
+	}
+	if again, _ := service.additionalEmbeddingClient(project, "LOCAL/NEW"); again != first {
+		t.Fatalf("expected searches to reuse the cached client")
+	}
+
+	config = project.Config
+	config.AdditionalEmbeddingModels = nil
+	if _, err := service.UpdateProjectConfig(project.ID, config); err != nil {
+		t.Fatalf("remove model: %v", err)
+	}
+	service.clientsMu.Lock()
+	cached := len(service.additionalClients)
+	service.clientsMu.Unlock()
+	if cached != 0 {
+		t.Fatalf("expected the removed model's client to be dropped, %d cached", cached)
+	}
+}
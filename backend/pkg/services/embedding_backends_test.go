@@ -0,0 +1,191 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"CodeTextor/backend/pkg/models"
+)
+
+// embeddingTestHandler serves an OpenAI-compatible embeddings endpoint that embeds each input
+// with embed.
+func embeddingTestHandler(embed func(text string) []float32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input []string `json:"input"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		data := make([]map[string]any, len(req.Input))
+		for i, text := range req.Input {
+			data[i] = map[string]any{"index": i, "embedding": embed(text)}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	})
+}
+
+// newEmbeddingTestServer starts an embeddingTestHandler server that is closed with the test.
+func newEmbeddingTestServer(t *testing.T, embed func(text string) []float32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(embeddingTestHandler(embed))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOpenAIBackendProjectUsesStoredEndpoint(t *testing.T) {
+	embeddings := embeddingTestHandler(func(string) []float32 { return []float32{0.6, 0.8} })
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer key-123" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		embeddings.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+
+	if _, err := service.SaveEmbeddingModel(models.EmbeddingModelInfo{ID: "local/broken", Backend: "openai", Dimension: 2}); err == nil {
+		t.Fatalf("expected an openai model without base URL to be rejected")
+	}
+	saved, err := service.SaveEmbeddingModel(models.EmbeddingModelInfo{
+		ID:          "local/tei",
+		DisplayName: "Local TEI",
+		Backend:     "openai",
+		Dimension:   2,
+		BaseURL:     server.URL,
+		APIKey:      "key-123",
+	})
+	if err != nil {
+		t.Fatalf("save model: %v", err)
+	}
+	if saved.DownloadStatus != "ready" {
+		t.Fatalf("expected remote models to be ready, got %q", saved.DownloadStatus)
+	}
+
+	project := createProject(t, service, "Remote Embeddings")
+	config := project.Config
+	config.EmbeddingModel = "local/tei"
+	config.EmbeddingModelInfo = nil
+	updated, err := service.UpdateProjectConfig(project.ID, config)
+	if err != nil {
+		t.Fatalf("update config: %v", err)
+	}
+	if updated.Config.EmbeddingModel != "local/tei" || updated.Config.EmbeddingBackend != "openai" {
+		t.Fatalf("expected the remote model to be selected, got %s (%s)", updated.Config.EmbeddingModel, updated.Config.EmbeddingBackend)
+	}
+	if updated.Config.EmbeddingModelInfo.APIKey != "" {
+		t.Fatalf("expected the API key to be stripped from the project snapshot")
+	}
+
+	client, err := service.getEmbeddingClient(updated)
+	if err != nil {
+		t.Fatalf("embedding client: %v", err)
+	}
+	defer client.Close()
+	vectors, err := client.GenerateEmbeddings([]string{"func main()"})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if len(vectors) != 1 || len(vectors[0]) != 2 {
+		t.Fatalf("unexpected vectors %v", vectors)
+	}
+}
+
+func TestOllamaModelsAreDiscoveredAndProbed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags":
+			_, _ = w.Write([]byte(`{"models":[{"name":"nomic-embed-text:latest","details":{"family":"nomic-bert"}},{"name":"llama3.2:latest","details":{"family":"llama"}}]}`))
+		case "/api/embed":
+			_, _ = w.Write([]byte(`{"embeddings":[[0.1,0.2,0.3]]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+	t.Setenv("OLLAMA_HOST", server.URL)
+
+	capabilities, err := service.GetEmbeddingCapabilities()
+	if err != nil || !capabilities.OllamaAvailable {
+		t.Fatalf("expected Ollama to be reported available: %+v (%v)", capabilities, err)
+	}
+
+	entries, err := service.ListEmbeddingModels()
+	if err != nil {
+		t.Fatalf("list models: %v", err)
+	}
+	var found *models.EmbeddingModelInfo
+	for _, entry := range entries {
+		if entry.ID == "ollama/llama3.2" {
+			t.Fatalf("chat models must not be listed")
+		}
+		if entry.ID == "ollama/nomic-embed-text" {
+			found = entry
+		}
+	}
+	if found == nil || found.Backend != "ollama" || found.DownloadStatus != "ready" {
+		t.Fatalf("expected the pulled embedding model in the catalog, got %+v", found)
+	}
+
+	project := createProject(t, service, "Ollama Project")
+	config := project.Config
+	config.EmbeddingModel = found.ID
+	config.EmbeddingModelInfo = nil
+	updated, err := service.UpdateProjectConfig(project.ID, config)
+	if err != nil {
+		t.Fatalf("update config: %v", err)
+	}
+	if updated.Config.EmbeddingModelInfo.Dimension != 3 {
+		t.Fatalf("expected the probed dimension 3, got %d", updated.Config.EmbeddingModelInfo.Dimension)
+	}
+}
+
+func TestProjectEmbeddingClientAppliesInstructionPrefixes(t *testing.T) {
+	var inputs []string
+	server := newEmbeddingTestServer(t, func(text string) []float32 {
+		inputs = append(inputs, text)
+		return []float32{1, 0}
+	})
+
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+	if _, err := service.SaveEmbeddingModel(models.EmbeddingModelInfo{
+		ID:             "local/e5",
+		Backend:        "openai",
+		Dimension:      2,
+		BaseURL:        server.URL,
+		QueryPrefix:    "query: ",
+		DocumentPrefix: "passage: ",
+	}); err != nil {
+		t.Fatalf("save model: %v", err)
+	}
+
+	project := createProject(t, service, "Prefixed Project")
+	config := project.Config
+	config.EmbeddingModel = "local/e5"
+	config.EmbeddingModelInfo = nil
+	updated, err := service.UpdateProjectConfig(project.ID, config)
+	if err != nil {
+		t.Fatalf("update config: %v", err)
+	}
+
+	client, err := service.getEmbeddingClient(updated)
+	if err != nil {
+		t.Fatalf("embedding client: %v", err)
+	}
+	if _, err := client.GenerateEmbeddings([]string{"func Save()"}); err != nil {
+		t.Fatalf("documents: %v", err)
+	}
+	if _, err := client.GenerateQueryEmbeddings([]string{"save user"}); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(inputs) != 2 || inputs[0] != "passage: func Save()" || inputs[1] != "query: save user" {
+		t.Fatalf("unexpected server inputs %q", inputs)
+	}
+}
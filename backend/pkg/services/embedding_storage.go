@@ -0,0 +1,42 @@
+package services
+
+import (
+	"CodeTextor/backend/pkg/models"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// normalizeEmbeddingStorage validates a storage mode, defaulting to float32.
+func normalizeEmbeddingStorage(mode string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(mode))
+	switch normalized {
+	case "":
+		return models.EmbeddingStorageFloat32, nil
+	case models.EmbeddingStorageFloat32, models.EmbeddingStorageFloat16, models.EmbeddingStorageInt8, models.EmbeddingStorageBinary:
+		return normalized, nil
+	default:
+		return "", fmt.Errorf("unknown embedding storage mode %s (expected float32, float16, int8 or binary)", mode)
+	}
+}
+
+// migrateEmbeddingStorage switches the project's vector store to format and re-encodes the
+// chunks already stored. New inserts use the new format before the rewrite starts so an
+// indexing run in progress does not leave stragglers behind.
+func (s *ProjectService) migrateEmbeddingStorage(projectID, format string) error {
+	vectorStore, err := s.GetVectorStore(projectID)
+	if err != nil {
+		return err
+	}
+	previous := vectorStore.EmbeddingStorage()
+	vectorStore.SetEmbeddingStorage(format)
+	count, err := vectorStore.ReencodeEmbeddings(format)
+	if err != nil {
+		vectorStore.SetEmbeddingStorage(previous)
+		return fmt.Errorf("failed to re-encode embeddings as %s: %w", format, err)
+	}
+	if count > 0 {
+		log.Printf("Re-encoded %d embeddings of project %s as %s", count, projectID, format)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"CodeTextor/backend/internal/store"
+	"CodeTextor/backend/pkg/models"
+)
+
+func TestEmbeddingStorageModesReencodeAndSearch(t *testing.T) {
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+	project := createProject(t, service, "Quantized Project")
+
+	vectorStore, err := service.GetVectorStore(project.ID)
+	if err != nil {
+		t.Fatalf("open vector store: %v", err)
+	}
+	vectors := map[string][]float32{
+		"save.go":  {0.9, 0.1, -0.2, 0.4, 0.3, -0.5, 0.1, 0.7},
+		"load.go":  {-0.8, 0.2, 0.6, -0.1, -0.4, 0.5, -0.3, -0.2},
+		"parse.go": {0.1, -0.9, 0.3, 0.2, -0.6, -0.1, 0.8, 0.05},
+	}
+	for path, vec := range vectors {
+		if err := vectorStore.InsertChunk(&models.Chunk{FilePath: path, LineStart: 1, LineEnd: 5, Embedding: vec, EmbeddingModelID: "m"}); err != nil {
+			t.Fatalf("insert chunk: %v", err)
+		}
+	}
+	query := []float32{0.85, 0.15, -0.25, 0.35, 0.3, -0.45, 0.15, 0.65}
+
+	stats, err := service.GetProjectStats(project.ID)
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	if stats.EmbeddingStorage != models.EmbeddingStorageFloat32 || stats.EmbeddingBytes != 3*8*4 || stats.EmbeddingBytesSaved != 0 {
+		t.Fatalf("unexpected float32 stats %+v", stats)
+	}
+
+	expectedBytes := map[string]int64{
+		models.EmbeddingStorageFloat16: 3 * 8 * 2,
+		models.EmbeddingStorageInt8:    3 * (4 + 8),
+		models.EmbeddingStorageBinary:  3 * 1,
+	}
+	var results []*models.Chunk
+	for _, mode := range []string{models.EmbeddingStorageFloat16, models.EmbeddingStorageInt8, models.EmbeddingStorageBinary} {
+		config := project.Config
+		config.EmbeddingStorage = mode
+		if _, err := service.UpdateProjectConfig(project.ID, config); err != nil {
+			t.Fatalf("switch to %s: %v", mode, err)
+		}
+
+		stats, err := service.GetProjectStats(project.ID)
+		if err != nil {
+			t.Fatalf("stats: %v", err)
+		}
+		if stats.EmbeddingStorage != mode || stats.EmbeddingBytes != expectedBytes[mode] || stats.EmbeddingBytesSaved != 3*8*4-expectedBytes[mode] {
+			t.Fatalf("unexpected %s stats %+v", mode, stats)
+		}
+
+		found, total, err := vectorStore.SearchSimilarChunksAbove(query, 2, math.Inf(-1))
+		if err != nil {
+			t.Fatalf("search %s: %v", mode, err)
+		}
+		if total != 3 || len(found) != 2 || found[0].FilePath != "save.go" {
+			t.Fatalf("unexpected %s results (total %d): %+v", mode, total, found)
+		}
+		if len(found[0].Embedding) != 8 {
+			t.Fatalf("expected decoded embeddings in %s mode, got %v", mode, found[0].Embedding)
+		}
+		results = found
+	}
+
+	// Chunks indexed after the switch use the new encoding too.
+	if err := vectorStore.InsertChunk(&models.Chunk{FilePath: "new.go", LineStart: 1, LineEnd: 5, Embedding: vectors["save.go"], EmbeddingModelID: "m"}); err != nil {
+		t.Fatalf("insert chunk: %v", err)
+	}
+	if stats, _ := service.GetProjectStats(project.ID); stats.EmbeddingBytes != 4 {
+		t.Fatalf("expected the new chunk to be stored as binary, got %d bytes", stats.EmbeddingBytes)
+	}
+
+	// Vectors of additional models count towards the stored bytes; evaluation vectors do not.
+	if err := vectorStore.SaveModelEmbeddings("extra", map[string][]float32{results[0].ID: vectors["save.go"]}); err != nil {
+		t.Fatalf("save additional embedding: %v", err)
+	}
+	if err := vectorStore.SaveModelEmbeddings(store.TemporaryModelPrefix+"extra", map[string][]float32{results[0].ID: vectors["save.go"]}); err != nil {
+		t.Fatalf("save evaluation embedding: %v", err)
+	}
+	if stats, _ := service.GetProjectStats(project.ID); stats.EmbeddingBytes != 5 || stats.EmbeddingBytesSaved != 5*8*4-5 {
+		t.Fatalf("expected the additional model's vector to be counted, got %+v", stats)
+	}
+
+	config := project.Config
+	config.EmbeddingStorage = "int4"
+	if _, err := service.UpdateProjectConfig(project.ID, config); err == nil {
+		t.Fatalf("expected an unknown storage mode to be rejected")
+	}
+}
+
+func TestBinaryRowsAreRescoredRegardlessOfStorageMode(t *testing.T) {
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+	project := createProject(t, service, "Mixed Encoding Project")
+
+	vectorStore, err := service.GetVectorStore(project.ID)
+	if err != nil {
+		t.Fatalf("open vector store: %v", err)
+	}
+	// The sign code of "hamming" agrees with the query in more components, but its ±1 vector
+	// points away from the query's dominant first component.
+	vectorStore.SetEmbeddingStorage(models.EmbeddingStorageBinary)
+	for path, vec := range map[string][]float32{
+		"hamming.go": {-1, 1, 1, 1},
+		"cosine.go":  {1, -1, -1, 1},
+	} {
+		if err := vectorStore.InsertChunk(&models.Chunk{FilePath: path, LineStart: 1, LineEnd: 5, Embedding: vec, EmbeddingModelID: "m"}); err != nil {
+			t.Fatalf("insert chunk: %v", err)
+		}
+	}
+	// Rows written before a storage switch keep their binary encoding until re-encoded.
+	vectorStore.SetEmbeddingStorage(models.EmbeddingStorageFloat32)
+
+	results, _, err := vectorStore.SearchSimilarChunksAbove([]float32{1, 0.1, 0.1, 0.1}, 1, math.Inf(-1))
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 1 || results[0].FilePath != "cosine.go" {
+		t.Fatalf("expected the binary rows to be rescored, got %+v", results)
+	}
+}
@@ -0,0 +1,514 @@
+package services
+
+import (
+	"CodeTextor/backend/internal/store"
+	"CodeTextor/backend/pkg/embedding"
+	"CodeTextor/backend/pkg/models"
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxEvalK bounds the ranking depth of an evaluation, like the MCP search tool.
+const maxEvalK = 50
+
+// GetEvaluationCases returns the retrieval test cases stored for a project.
+func (s *ProjectService) GetEvaluationCases(projectID string) ([]models.EvalCase, error) {
+	if _, err := s.GetProject(projectID); err != nil {
+		return nil, err
+	}
+	vectorStore, err := s.GetVectorStore(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return vectorStore.ListEvalCases()
+}
+
+// SaveEvaluationCases replaces the project's stored test cases and returns them normalized.
+func (s *ProjectService) SaveEvaluationCases(projectID string, cases []models.EvalCase) ([]models.EvalCase, error) {
+	if _, err := s.GetProject(projectID); err != nil {
+		return nil, err
+	}
+	normalized, err := normalizeEvalCases(cases)
+	if err != nil {
+		return nil, err
+	}
+	vectorStore, err := s.GetVectorStore(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := vectorStore.ReplaceEvalCases(normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// GenerateEvaluationCases derives up to limit test cases from the docstrings of indexed symbols:
+// the first sentence of a docstring is the query and its symbol the expected hit. Symbols are
+// sampled evenly across the project. Nothing is stored.
+func (s *ProjectService) GenerateEvaluationCases(projectID string, limit int) ([]models.EvalCase, error) {
+	if _, err := s.GetProject(projectID); err != nil {
+		return nil, err
+	}
+	vectorStore, err := s.GetVectorStore(projectID)
+	if err != nil {
+		return nil, err
+	}
+	chunks, err := vectorStore.DocumentedChunks()
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = models.DefaultEvalGeneratedCases
+	}
+	return generateEvalCases(chunks, limit), nil
+}
+
+// evalModel is an embedding model prepared for an evaluation.
+type evalModel struct {
+	id string
+	// storeID is the model ID its vectors are stored under; temporary models are namespaced
+	// with store.TemporaryModelPrefix so they never mix with a backfill of the same model.
+	storeID    string
+	additional bool
+	client     embedding.EmbeddingClient
+	// temporary is set when the model's vectors were embedded for this evaluation only.
+	temporary   bool
+	indexBytes  int64
+	dim         int
+	embedTimeMs int64
+	err         error
+}
+
+// RunEvaluation measures retrieval quality on a project: every case is searched with each
+// requested model and mode, and the report gives recall@1/5/K, MRR, query latency (embedding
+// included) and the size of each model's vectors. Models the project keeps no vectors for are
+// embedded first, timed, and dropped again afterwards, so the call can take long on big projects.
+// One evaluation runs per project at a time, never alongside a migration or backfill; it stops
+// when ctx is cancelled or StopIndexing is called.
+func (s *ProjectService) RunEvaluation(ctx context.Context, req models.EvalRequest) (*models.EvalReport, error) {
+	start := time.Now()
+	project, err := s.GetProject(req.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, release, err := s.indexerManager.StartEvaluation(ctx, project.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	k := req.K
+	if k <= 0 {
+		k = models.DefaultEvalK
+	}
+	k = min(k, maxEvalK)
+
+	modes, err := normalizeEvalModes(req.Modes)
+	if err != nil {
+		return nil, err
+	}
+	cases, source, err := s.resolveEvalCases(project.ID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	modelIDs := normalizeEvalModels(req.Models, project.Config.EmbeddingModel)
+	prepared := make([]*evalModel, 0, len(modelIDs))
+	defer func() {
+		var temporary []string
+		for _, model := range prepared {
+			if !model.temporary {
+				continue
+			}
+			temporary = append(temporary, model.id)
+			if vectorStore, err := s.GetVectorStore(project.ID); err == nil {
+				if err := vectorStore.DeleteModelEmbeddings(model.storeID); err != nil {
+					log.Printf("Failed to drop evaluation embeddings of %s: %v", model.id, err)
+				}
+			}
+		}
+		s.dropEvalClients(project.ID, temporary)
+	}()
+	for _, modelID := range modelIDs {
+		prepared = append(prepared, s.prepareEvalModel(ctx, project, modelID))
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &models.EvalReport{ProjectID: project.ID, K: k, CaseCount: len(cases), CaseSource: source}
+	for _, mode := range modes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if mode == models.EvalModeFused {
+			report.Runs = append(report.Runs, s.runFusedEvaluation(project, prepared, cases, k))
+			continue
+		}
+		for _, model := range prepared {
+			report.Runs = append(report.Runs, s.runEvaluation(project, []*evalModel{model}, mode, cases, k))
+		}
+	}
+	report.DurationMs = time.Since(start).Milliseconds()
+	return report, nil
+}
+
+// dropEvalClients closes the cached clients of models embedded only for an evaluation, unless
+// they were added to the project meanwhile.
+func (s *ProjectService) dropEvalClients(projectID string, modelIDs []string) {
+	if len(modelIDs) == 0 {
+		return
+	}
+	var configured []string
+	if project, err := s.GetProject(projectID); err == nil {
+		configured = project.Config.AdditionalEmbeddingModels
+	}
+	s.dropAdditionalClients(func(id, modelID string) bool {
+		return id == projectID && containsModelID(modelIDs, modelID) && !containsModelID(configured, modelID)
+	})
+}
+
+// resolveEvalCases picks the cases of a request: its own, the stored ones, or generated ones.
+func (s *ProjectService) resolveEvalCases(projectID string, req models.EvalRequest) ([]models.EvalCase, string, error) {
+	if len(req.Cases) > 0 {
+		cases, err := normalizeEvalCases(req.Cases)
+		return cases, "request", err
+	}
+	if !req.GenerateCases {
+		stored, err := s.GetEvaluationCases(projectID)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(stored) > 0 {
+			return stored, "stored", nil
+		}
+	}
+	generated, err := s.GenerateEvaluationCases(projectID, req.MaxCases)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(generated) == 0 {
+		return nil, "", fmt.Errorf("project %s has no evaluation cases and no documented symbols to generate them from", projectID)
+	}
+	return generated, models.EvalCaseSourceDocstring, nil
+}
+
+// prepareEvalModel loads a model's client and makes sure every chunk has a vector from it.
+// Failures are kept on the model and reported by its runs.
+func (s *ProjectService) prepareEvalModel(ctx context.Context, project *models.Project, modelID string) *evalModel {
+	model := &evalModel{id: modelID, storeID: modelID}
+	vectorStore, err := s.GetVectorStore(project.ID)
+	if err != nil {
+		model.err = err
+		return model
+	}
+
+	if strings.EqualFold(modelID, project.Config.EmbeddingModel) {
+		model.id = project.Config.EmbeddingModel
+		model.storeID = model.id
+		model.client, model.err = s.getEmbeddingClient(project)
+		if size, dim, err := vectorStore.PrimaryEmbeddingSize(); err == nil {
+			model.indexBytes, model.dim = size, dim
+		}
+		return model
+	}
+
+	model.additional = true
+	if configured, err := resolveProjectModel(project.Config, modelID); err == nil {
+		model.id = configured
+		model.storeID = configured
+	} else {
+		model.temporary = true
+		model.storeID = store.TemporaryModelPrefix + modelID
+	}
+	model.client, model.err = s.additionalEmbeddingClient(project, model.id)
+	if model.err != nil {
+		return model
+	}
+	embedStart := time.Now()
+	if err := s.embedMissingChunks(ctx, project.ID, vectorStore, model.client, model.storeID, &models.IndexingProgress{}); err != nil {
+		model.err = err
+		return model
+	}
+	if model.temporary {
+		model.embedTimeMs = time.Since(embedStart).Milliseconds()
+	}
+	model.indexBytes, model.dim, model.err = vectorStore.ModelEmbeddingSize(model.storeID)
+	return model
+}
+
+// runFusedEvaluation evaluates the fusion of every model that could be prepared.
+func (s *ProjectService) runFusedEvaluation(project *models.Project, prepared []*evalModel, cases []models.EvalCase, k int) models.EvalRun {
+	var usable []*evalModel
+	for _, model := range prepared {
+		if model.err == nil {
+			usable = append(usable, model)
+		}
+	}
+	if len(usable) < 2 {
+		ids := make([]string, len(prepared))
+		for idx, model := range prepared {
+			ids[idx] = model.id
+		}
+		return models.EvalRun{Model: strings.Join(ids, "+"), Mode: models.EvalModeFused, Error: "fusion needs at least two usable models"}
+	}
+	return s.runEvaluation(project, usable, models.EvalModeFused, cases, k)
+}
+
+// runEvaluation searches every case with the given models (fused when several) and mode.
+func (s *ProjectService) runEvaluation(project *models.Project, evalModels []*evalModel, mode models.EvalSearchMode, cases []models.EvalCase, k int) models.EvalRun {
+	ids := make([]string, len(evalModels))
+	run := models.EvalRun{Mode: mode}
+	for idx, model := range evalModels {
+		ids[idx] = model.id
+		run.IndexBytes += model.indexBytes
+		run.EmbedTimeMs += model.embedTimeMs
+		if run.Error == "" && model.err != nil {
+			run.Error = model.err.Error()
+		}
+	}
+	run.Model = strings.Join(ids, "+")
+	if len(evalModels) == 1 {
+		run.EmbeddingDim = evalModels[0].dim
+	}
+	if run.Error != "" {
+		return run
+	}
+
+	var rerank *models.RerankConfig
+	if mode == models.EvalModeRerank {
+		rerank = &models.RerankConfig{}
+		if project.Config.Rerank != nil {
+			*rerank = *project.Config.Rerank
+		}
+		rerank.Enabled = true
+		if err := normalizeRerankConfig(rerank); err != nil {
+			run.Error = err.Error()
+			return run
+		}
+	}
+
+	latencies := make([]float64, 0, len(cases))
+	for _, c := range cases {
+		caseStart := time.Now()
+		queries := make([]modelQuery, 0, len(evalModels))
+		for _, model := range evalModels {
+			vec, err := embedQuery(model.client.GenerateQueryEmbeddings, c.Query)
+			if err != nil {
+				run.Error = err.Error()
+				return run
+			}
+			queries = append(queries, modelQuery{model: model.storeID, additional: model.additional, vector: vec})
+		}
+		req := models.SearchRequest{ProjectID: project.ID, Query: c.Query, K: k, MMR: mode == models.EvalModeMMR}
+		resp, err := s.rankSearch(project, req, c.Query, k, queries, rerank, caseStart)
+		if err != nil {
+			run.Error = err.Error()
+			return run
+		}
+		if resp.Rerank != nil && resp.Rerank.Error != "" && run.Error == "" {
+			run.Error = "reranker unavailable, bi-encoder order kept: " + resp.Rerank.Error
+		}
+		latency := float64(time.Since(caseStart).Microseconds()) / 1000
+
+		result := models.EvalCaseResult{
+			Query:          c.Query,
+			ExpectedFile:   c.ExpectedFile,
+			ExpectedSymbol: c.ExpectedSymbol,
+			Rank:           evalMatchRank(resp.Chunks, c),
+			LatencyMs:      latency,
+		}
+		if len(resp.Chunks) > 0 {
+			result.TopFile = resp.Chunks[0].FilePath
+			result.TopSymbol = resp.Chunks[0].SymbolName
+		}
+		run.Cases = append(run.Cases, result)
+		latencies = append(latencies, latency)
+	}
+	scoreEvalRun(&run, k, latencies)
+	return run
+}
+
+// scoreEvalRun fills in the recall, MRR and latency metrics of a run from its case results.
+func scoreEvalRun(run *models.EvalRun, k int, latencies []float64) {
+	n := len(run.Cases)
+	if n == 0 {
+		return
+	}
+	var at1, at5, atK int
+	var reciprocal, latencySum float64
+	for _, result := range run.Cases {
+		if result.Rank == 0 {
+			continue
+		}
+		if result.Rank <= 1 {
+			at1++
+		}
+		if result.Rank <= 5 {
+			at5++
+		}
+		if result.Rank <= k {
+			atK++
+			reciprocal += 1 / float64(result.Rank)
+		}
+	}
+	for _, latency := range latencies {
+		latencySum += latency
+	}
+	run.RecallAt1 = float64(at1) / float64(n)
+	run.RecallAt5 = float64(at5) / float64(n)
+	run.RecallAtK = float64(atK) / float64(n)
+	run.MRR = reciprocal / float64(n)
+	if len(latencies) > 0 {
+		sorted := append([]float64(nil), latencies...)
+		sort.Float64s(sorted)
+		run.MeanLatencyMs = latencySum / float64(len(sorted))
+		run.P95LatencyMs = sorted[int(math.Ceil(0.95*float64(len(sorted))))-1]
+	}
+}
+
+// evalMatchRank returns the 1-based position of the first chunk in the expected file (and
+// symbol, when the case names one), or 0.
+func evalMatchRank(chunks []*models.Chunk, c models.EvalCase) int {
+	for idx, chunk := range chunks {
+		if normalizeEvalPath(chunk.FilePath) != c.ExpectedFile {
+			continue
+		}
+		if c.ExpectedSymbol == "" ||
+			strings.EqualFold(chunk.SymbolName, c.ExpectedSymbol) ||
+			(chunk.Parent != "" && strings.EqualFold(chunk.Parent+"."+chunk.SymbolName, c.ExpectedSymbol)) {
+			return idx + 1
+		}
+	}
+	return 0
+}
+
+func normalizeEvalPath(path string) string {
+	return strings.TrimPrefix(filepath.ToSlash(strings.TrimSpace(path)), "./")
+}
+
+func normalizeEvalCases(cases []models.EvalCase) ([]models.EvalCase, error) {
+	normalized := make([]models.EvalCase, 0, len(cases))
+	for idx, c := range cases {
+		c.Query = strings.TrimSpace(c.Query)
+		c.ExpectedFile = normalizeEvalPath(c.ExpectedFile)
+		c.ExpectedSymbol = strings.TrimSpace(c.ExpectedSymbol)
+		if c.Query == "" || c.ExpectedFile == "" {
+			return nil, fmt.Errorf("evaluation case %d needs a query and an expected file", idx+1)
+		}
+		if c.Source == "" {
+			c.Source = models.EvalCaseSourceManual
+		}
+		normalized = append(normalized, c)
+	}
+	return normalized, nil
+}
+
+func normalizeEvalModes(modes []models.EvalSearchMode) ([]models.EvalSearchMode, error) {
+	if len(modes) == 0 {
+		return []models.EvalSearchMode{models.EvalModeSemantic}, nil
+	}
+	var normalized []models.EvalSearchMode
+	for _, mode := range modes {
+		mode = models.EvalSearchMode(strings.ToLower(strings.TrimSpace(string(mode))))
+		switch mode {
+		case models.EvalModeSemantic, models.EvalModeMMR, models.EvalModeRerank, models.EvalModeFused:
+		default:
+			return nil, fmt.Errorf("unknown evaluation mode %q (use semantic, mmr, rerank or fused)", mode)
+		}
+		if !containsEvalMode(normalized, mode) {
+			normalized = append(normalized, mode)
+		}
+	}
+	return normalized, nil
+}
+
+func containsEvalMode(modes []models.EvalSearchMode, mode models.EvalSearchMode) bool {
+	for _, candidate := range modes {
+		if candidate == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeEvalModels trims and de-duplicates the requested models, defaulting to the primary one.
+func normalizeEvalModels(requested []string, primary string) []string {
+	var modelIDs []string
+	for _, modelID := range requested {
+		modelID = strings.TrimSpace(modelID)
+		if modelID != "" && !containsModelID(modelIDs, modelID) {
+			modelIDs = append(modelIDs, modelID)
+		}
+	}
+	if len(modelIDs) == 0 {
+		modelIDs = []string{primary}
+	}
+	return modelIDs
+}
+
+// generateEvalCases turns documented chunks into cases, one per symbol, sampled evenly down to limit.
+func generateEvalCases(chunks []*models.Chunk, limit int) []models.EvalCase {
+	seen := make(map[string]bool)
+	var cases []models.EvalCase
+	for _, chunk := range chunks {
+		query := docstringQuery(chunk.DocString, chunk.SymbolName)
+		key := chunk.FilePath + "\x00" + chunk.SymbolName
+		if len(strings.Fields(query)) < 3 || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cases = append(cases, models.EvalCase{
+			Query:          query,
+			ExpectedFile:   normalizeEvalPath(chunk.FilePath),
+			ExpectedSymbol: chunk.SymbolName,
+			Source:         models.EvalCaseSourceDocstring,
+		})
+	}
+	if len(cases) <= limit {
+		return cases
+	}
+	sampled := make([]models.EvalCase, limit)
+	step := float64(len(cases)) / float64(limit)
+	for idx := range sampled {
+		sampled[idx] = cases[int(float64(idx)*step)]
+	}
+	return sampled
+}
+
+// docstringQuery extracts the first sentence of a docstring, without comment markers and
+// without a leading symbol name ("Foo returns ..." becomes "returns ...").
+func docstringQuery(doc, symbol string) string {
+	var words []string
+	for _, line := range strings.Split(doc, "\n") {
+		line = strings.TrimSpace(line)
+		for _, marker := range []string{"///", "//", "/**", "/*", "*/", "*", "#", `"""`, "'''"} {
+			line = strings.TrimSpace(strings.TrimPrefix(line, marker))
+		}
+		line = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(line, "*/"), `"""`))
+		if line == "" {
+			if len(words) > 0 {
+				break
+			}
+			continue
+		}
+		words = append(words, strings.Fields(line)...)
+	}
+
+	var sentence []string
+	for _, word := range words {
+		sentence = append(sentence, word)
+		if strings.HasSuffix(word, ".") && len(sentence) > 1 {
+			break
+		}
+	}
+	if len(sentence) > 0 && strings.EqualFold(strings.Trim(sentence[0], "`'\":,"), symbol) {
+		sentence = sentence[1:]
+	}
+	return strings.TrimSuffix(strings.Join(sentence, " "), ".")
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"CodeTextor/backend/internal/store"
+	"CodeTextor/backend/pkg/models"
+)
+
+var evalVocabulary = []string{"alpha", "beta", "gamma", "delta"}
+
+// keywordEmbedding counts the vocabulary words in text, so queries find chunks sharing their words.
+func keywordEmbedding(text string) []float32 {
+	vec := make([]float32, len(evalVocabulary)+1)
+	vec[len(evalVocabulary)] = 0.1
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		for idx, term := range evalVocabulary {
+			if strings.Trim(word, ".,") == term {
+				vec[idx]++
+			}
+		}
+	}
+	return vec
+}
+
+// setupEvalProject indexes three documented symbols with local/keywords and registers local/flat
+// (3 dimensions, every text gets the same vector) as a second model.
+func setupEvalProject(t *testing.T, service *ProjectService) *models.Project {
+	t.Helper()
+	entries := []models.EmbeddingModelInfo{
+		{ID: "local/keywords", Backend: "openai", Dimension: len(evalVocabulary) + 1, BaseURL: newEmbeddingTestServer(t, keywordEmbedding).URL},
+		{ID: "local/flat", Backend: "openai", Dimension: 3, BaseURL: newMigrationTestServer(t, 3, nil).URL},
+	}
+	for _, entry := range entries {
+		if _, err := service.SaveEmbeddingModel(entry); err != nil {
+			t.Fatalf("save %s: %v", entry.ID, err)
+		}
+	}
+
+	project := createProject(t, service, "Evaluated Project")
+	config := project.Config
+	config.EmbeddingModel = "local/keywords"
+	config.EmbeddingModelInfo = nil
+	project, err := service.UpdateProjectConfig(project.ID, config)
+	if err != nil {
+		t.Fatalf("select model: %v", err)
+	}
+
+	vectorStore, err := service.GetVectorStore(project.ID)
+	if err != nil {
+		t.Fatalf("open vector store: %v", err)
+	}
+	symbols := []struct{ file, name, doc, content string }{
+		{"pkg/a.go", "Alpha", "// Alpha parses alpha tokens.\n// It is fast.", "func Alpha() { alpha alpha }"},
+		{"pkg/b.go", "Beta", "// Beta stores beta values.", "func Beta() { beta beta }"},
+		{"pkg/c.go", "Gamma", "// Gamma renders gamma output.", "func Gamma() { gamma gamma }"},
+		{"pkg/c.go", "helper", "// ok", "func helper() { delta }"},
+	}
+	for idx, symbol := range symbols {
+		chunk := &models.Chunk{
+			FilePath:         symbol.file,
+			LineStart:        idx * 10,
+			LineEnd:          idx*10 + 5,
+			Content:          symbol.content,
+			SymbolName:       symbol.name,
+			SymbolKind:       "function",
+			DocString:        symbol.doc,
+			Embedding:        keywordEmbedding(symbol.content),
+			EmbeddingModelID: "local/keywords",
+		}
+		if err := vectorStore.InsertChunk(chunk); err != nil {
+			t.Fatalf("insert chunk: %v", err)
+		}
+	}
+	return project
+}
+
+func TestGenerateEvaluationCasesFromDocstrings(t *testing.T) {
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+	project := setupEvalProject(t, service)
+
+	cases, err := service.GenerateEvaluationCases(project.ID, 0)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if len(cases) != 3 {
+		t.Fatalf("expected one case per documented symbol with a usable sentence, got %+v", cases)
+	}
+	if cases[0].Query != "parses alpha tokens" || cases[0].ExpectedFile != "pkg/a.go" || cases[0].ExpectedSymbol != "Alpha" || cases[0].Source != models.EvalCaseSourceDocstring {
+		t.Fatalf("unexpected first case %+v", cases[0])
+	}
+
+	if sampled, _ := service.GenerateEvaluationCases(project.ID, 2); len(sampled) != 2 || sampled[0].ExpectedSymbol != "Alpha" {
+		t.Fatalf("expected an even sample of 2 cases, got %+v", sampled)
+	}
+}
+
+func TestRunEvaluationComparesModelsAndModes(t *testing.T) {
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+	project := setupEvalProject(t, service)
+
+	report, err := service.RunEvaluation(context.Background(), models.EvalRequest{
+		ProjectID: project.ID,
+		Models:    []string{"local/keywords", "local/flat"},
+		Modes:     []models.EvalSearchMode{models.EvalModeSemantic, models.EvalModeFused},
+		K:         2,
+	})
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if report.CaseSource != models.EvalCaseSourceDocstring || report.CaseCount != 3 || len(report.Runs) != 3 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	keywords := report.Runs[0]
+	if keywords.Model != "local/keywords" || keywords.Error != "" || keywords.RecallAt1 != 1 || keywords.MRR != 1 || keywords.RecallAtK != 1 {
+		t.Fatalf("expected the keyword model to find every symbol first, got %+v", keywords)
+	}
+	if keywords.IndexBytes != int64(4*(len(evalVocabulary)+1)*4) || keywords.EmbeddingDim != len(evalVocabulary)+1 || keywords.EmbedTimeMs != 0 || len(keywords.Cases) != 3 {
+		t.Fatalf("unexpected keyword model details %+v", keywords)
+	}
+	if keywords.MeanLatencyMs <= 0 || keywords.P95LatencyMs < keywords.MeanLatencyMs/3 {
+		t.Fatalf("expected latencies to be measured, got %+v", keywords)
+	}
+
+	flat := report.Runs[1]
+	if flat.Model != "local/flat" || flat.Error != "" || flat.EmbeddingDim != 3 || flat.IndexBytes == 0 {
+		t.Fatalf("expected local/flat to be embedded for the run, got %+v", flat)
+	}
+	if flat.RecallAt1 > keywords.RecallAt1 {
+		t.Fatalf("a model without signal should not beat the keyword model: %+v", flat)
+	}
+
+	fused := report.Runs[2]
+	if fused.Model != "local/keywords+local/flat" || fused.Mode != models.EvalModeFused || fused.Error != "" || fused.IndexBytes != keywords.IndexBytes+flat.IndexBytes {
+		t.Fatalf("unexpected fused run %+v", fused)
+	}
+
+	vectorStore, _ := service.GetVectorStore(project.ID)
+	if covered, _, _ := vectorStore.ModelEmbeddingCoverage("local/flat"); covered != 0 {
+		t.Fatalf("expected temporary evaluation vectors to be dropped, %d left", covered)
+	}
+}
+
+func TestRunEvaluationKeepsTemporaryVectorsApart(t *testing.T) {
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+	project := setupEvalProject(t, service)
+	vectorStore, _ := service.GetVectorStore(project.ID)
+
+	// Vectors a backfill of local/flat stored meanwhile must survive the evaluation's cleanup.
+	chunks, err := vectorStore.ChunksMissingModelEmbedding("local/flat", 1)
+	if err != nil || len(chunks) != 1 {
+		t.Fatalf("list chunks: %v", err)
+	}
+	if err := vectorStore.SaveModelEmbeddings("local/flat", map[string][]float32{chunks[0].ID: {1, 0, 0}}); err != nil {
+		t.Fatalf("save vectors: %v", err)
+	}
+	version, _ := vectorStore.IndexVersion()
+
+	if err := vectorStore.SaveModelEmbeddings(store.TemporaryModelPrefix+"local/flat", map[string][]float32{chunks[0].ID: {1, 0, 0}}); err != nil {
+		t.Fatalf("save temporary vectors: %v", err)
+	}
+	if got, _ := vectorStore.IndexVersion(); got != version {
+		t.Fatalf("expected temporary vectors to leave the index version alone, %s became %s", version, got)
+	}
+	stats, _ := service.GetProjectStats(project.ID)
+	for _, usage := range stats.EmbeddingModels {
+		if strings.HasPrefix(usage.ModelID, store.TemporaryModelPrefix) {
+			t.Fatalf("expected temporary vectors to be left out of the stats, got %+v", stats.EmbeddingModels)
+		}
+	}
+
+	report, err := service.RunEvaluation(context.Background(), models.EvalRequest{ProjectID: project.ID, Models: []string{"local/flat"}})
+	if err != nil || len(report.Runs) != 1 || report.Runs[0].Error != "" {
+		t.Fatalf("evaluate: %v %+v", err, report)
+	}
+	if covered, _, _ := vectorStore.ModelEmbeddingCoverage("local/flat"); covered != 1 {
+		t.Fatalf("expected the backfilled vector to be kept, %d left", covered)
+	}
+	if covered, _, _ := vectorStore.ModelEmbeddingCoverage(store.TemporaryModelPrefix + "local/flat"); covered != 0 {
+		t.Fatalf("expected temporary evaluation vectors to be dropped, %d left", covered)
+	}
+}
+
+func TestRunEvaluationUsesStoredCases(t *testing.T) {
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+	project := setupEvalProject(t, service)
+
+	if _, err := service.SaveEvaluationCases(project.ID, []models.EvalCase{{Query: "beta"}}); err == nil {
+		t.Fatalf("expected a case without an expected file to be rejected")
+	}
+	saved, err := service.SaveEvaluationCases(project.ID, []models.EvalCase{
+		{Query: " where are gamma things rendered ", ExpectedFile: "./pkg/c.go"},
+		{Query: "delta", ExpectedFile: "pkg/c.go", ExpectedSymbol: "Gamma"},
+	})
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if saved[0].Query != "where are gamma things rendered" || saved[0].ExpectedFile != "pkg/c.go" || saved[0].Source != models.EvalCaseSourceManual {
+		t.Fatalf("expected the case to be normalized, got %+v", saved[0])
+	}
+	if stored, _ := service.GetEvaluationCases(project.ID); len(stored) != 2 {
+		t.Fatalf("expected 2 stored cases, got %+v", stored)
+	}
+
+	report, err := service.RunEvaluation(context.Background(), models.EvalRequest{ProjectID: project.ID, K: 1})
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if report.CaseSource != "stored" || len(report.Runs) != 1 || report.Runs[0].Model != "local/keywords" {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	run := report.Runs[0]
+	if run.Cases[0].Rank != 1 || run.Cases[1].Rank != 0 || run.RecallAtK != 0.5 || run.MRR != 0.5 {
+		t.Fatalf("expected the file match to hit and the wrong symbol to miss, got %+v", run)
+	}
+
+	if _, err := service.RunEvaluation(context.Background(), models.EvalRequest{ProjectID: project.ID, Modes: []models.EvalSearchMode{"hybrid"}}); err == nil {
+		t.Fatalf("expected an unknown mode to be rejected")
+	}
+}
+
+func TestRunEvaluationHoldsTheProjectUntilStopped(t *testing.T) {
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+	project := setupEvalProject(t, service)
+
+	// local/gated holds its first embedding request until the gate opens.
+	requested, gate := make(chan struct{}, 1), make(chan struct{})
+	embeddings := embeddingTestHandler(func(text string) []float32 { return []float32{1, 0, 0} })
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case requested <- struct{}{}:
+		default:
+		}
+		<-gate
+		embeddings.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	if _, err := service.SaveEmbeddingModel(models.EmbeddingModelInfo{ID: "local/gated", Backend: "openai", Dimension: 3, BaseURL: server.URL}); err != nil {
+		t.Fatalf("save model: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := service.RunEvaluation(context.Background(), models.EvalRequest{ProjectID: project.ID, Models: []string{"local/gated"}})
+		done <- err
+	}()
+	select {
+	case <-requested:
+	case err := <-done:
+		t.Fatalf("evaluation finished before embedding: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("evaluation never embedded")
+	}
+
+	if _, err := service.RunEvaluation(context.Background(), models.EvalRequest{ProjectID: project.ID}); err == nil {
+		t.Fatalf("expected a second evaluation to be refused")
+	}
+	if err := service.MigrateEmbeddingModel(project.ID, "local/flat"); err == nil {
+		t.Fatalf("expected a migration to be refused during an evaluation")
+	}
+	if err := service.StopIndexing(project.ID); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	close(gate)
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the evaluation to be cancelled, got %v", err)
+	}
+
+	vectorStore, _ := service.GetVectorStore(project.ID)
+	if covered, _, _ := vectorStore.ModelEmbeddingCoverage(store.TemporaryModelPrefix + "local/gated"); covered != 0 {
+		t.Fatalf("expected temporary evaluation vectors to be dropped, %d left", covered)
+	}
+	if _, err := service.RunEvaluation(context.Background(), models.EvalRequest{ProjectID: project.ID}); err != nil {
+		t.Fatalf("expected the project to be released, got %v", err)
+	}
+}
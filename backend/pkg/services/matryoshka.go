@@ -0,0 +1,47 @@
+package services
+
+import (
+	"CodeTextor/backend/pkg/models"
+	"fmt"
+	"log"
+)
+
+// normalizeOutputDimension validates config.OutputDimension against the selected model. When the
+// setting was carried over from a previous model that the new one cannot honour, truncation is
+// switched off instead of rejecting the model change.
+func (s *ProjectService) normalizeOutputDimension(config *models.ProjectConfig, carriedOver bool) error {
+	if config.OutputDimension <= 0 {
+		config.OutputDimension = 0
+		return nil
+	}
+	err := s.validateOutputDimension(config.EmbeddingModelInfo, config.OutputDimension)
+	if err != nil && carriedOver {
+		log.Printf("Disabling output dimension %d: %v", config.OutputDimension, err)
+		config.OutputDimension = 0
+		return nil
+	}
+	return err
+}
+
+// validateOutputDimension checks that meta supports Matryoshka truncation to dimension. Project
+// snapshots taken before the capability flag existed are refreshed from the catalog.
+func (s *ProjectService) validateOutputDimension(meta *models.EmbeddingModelInfo, dimension int) error {
+	if meta == nil {
+		return fmt.Errorf("select an embedding model before setting an output dimension")
+	}
+	if !meta.SupportsMatryoshka {
+		if entry, err := s.configStore.GetEmbeddingModel(meta.ID); err == nil && entry.SupportsMatryoshka {
+			meta.SupportsMatryoshka = true
+		}
+	}
+	if !meta.SupportsMatryoshka {
+		return fmt.Errorf("embedding model %s does not support Matryoshka truncation", meta.ID)
+	}
+	if dimension < models.MinMatryoshkaDimension {
+		return fmt.Errorf("output dimension %d is below the minimum of %d", dimension, models.MinMatryoshkaDimension)
+	}
+	if meta.Dimension > 0 && dimension > meta.Dimension {
+		return fmt.Errorf("output dimension %d exceeds the %d dimensions of %s", dimension, meta.Dimension, meta.ID)
+	}
+	return nil
+}
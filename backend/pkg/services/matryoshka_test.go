@@ -0,0 +1,126 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"CodeTextor/backend/pkg/models"
+)
+
+func TestOutputDimensionTruncatesAndInvalidatesIndex(t *testing.T) {
+	vector := make([]float32, 128)
+	for i := range vector {
+		vector[i] = float32(i%7) + 1
+	}
+	server := newEmbeddingTestServer(t, func(string) []float32 { return vector })
+
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+	for _, model := range []models.EmbeddingModelInfo{
+		{ID: "local/mrl", Backend: "openai", Dimension: 128, BaseURL: server.URL, SupportsMatryoshka: true},
+		{ID: "local/plain", Backend: "openai", Dimension: 128, BaseURL: server.URL},
+	} {
+		if _, err := service.SaveEmbeddingModel(model); err != nil {
+			t.Fatalf("save model: %v", err)
+		}
+	}
+
+	project := createProject(t, service, "Matryoshka Project")
+	config := project.Config
+	config.EmbeddingModel = "local/plain"
+	config.EmbeddingModelInfo = nil
+	config.OutputDimension = 64
+	if _, err := service.UpdateProjectConfig(project.ID, config); err == nil {
+		t.Fatalf("expected truncation to be rejected for a model without Matryoshka support")
+	}
+
+	config.EmbeddingModel = "local/mrl"
+	updated, err := service.UpdateProjectConfig(project.ID, config)
+	if err != nil {
+		t.Fatalf("enable truncation: %v", err)
+	}
+	for _, invalid := range []int{32, 256} {
+		config := updated.Config
+		config.OutputDimension = invalid
+		if _, err := service.UpdateProjectConfig(project.ID, config); err == nil {
+			t.Fatalf("expected output dimension %d to be rejected", invalid)
+		}
+	}
+
+	client, err := service.getEmbeddingClient(updated)
+	if err != nil {
+		t.Fatalf("embedding client: %v", err)
+	}
+	vectors, err := client.GenerateQueryEmbeddings([]string{"save user"})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	norm := float64(0)
+	for _, f := range vectors[0] {
+		norm += float64(f) * float64(f)
+	}
+	if len(vectors[0]) != 64 || math.Abs(norm-1) > 1e-5 {
+		t.Fatalf("expected a unit 64-dim vector, got %d dims with norm %f", len(vectors[0]), norm)
+	}
+
+	// An index built at the full dimension no longer matches the selection.
+	vectorStore, err := service.GetVectorStore(project.ID)
+	if err != nil {
+		t.Fatalf("open vector store: %v", err)
+	}
+	if err := vectorStore.InsertChunk(&models.Chunk{FilePath: "a.go", LineStart: 1, LineEnd: 2, Embedding: make([]float32, 128), EmbeddingModelID: "local/mrl"}); err != nil {
+		t.Fatalf("insert chunk: %v", err)
+	}
+	if match, err := service.embeddingUsageMatchesSelection(updated, vectorStore); err != nil || match {
+		t.Fatalf("expected 128-dim chunks to invalidate a 64-dim selection (match=%v, err=%v)", match, err)
+	}
+	if err := vectorStore.ResetProjectData(); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if err := vectorStore.InsertChunk(&models.Chunk{FilePath: "a.go", LineStart: 1, LineEnd: 2, Embedding: vectors[0], EmbeddingModelID: "local/mrl"}); err != nil {
+		t.Fatalf("insert chunk: %v", err)
+	}
+	if match, err := service.embeddingUsageMatchesSelection(updated, vectorStore); err != nil || !match {
+		t.Fatalf("expected truncated chunks to match (match=%v, err=%v)", match, err)
+	}
+
+	// Switching to a model without support turns truncation off instead of failing.
+	config = updated.Config
+	config.EmbeddingModel = "local/plain"
+	config.EmbeddingModelInfo = nil
+	switched, err := service.UpdateProjectConfig(project.ID, config)
+	if err != nil {
+		t.Fatalf("switch model: %v", err)
+	}
+	if switched.Config.OutputDimension != 0 {
+		t.Fatalf("expected the output dimension to be cleared, got %d", switched.Config.OutputDimension)
+	}
+}
+
+func TestCatalogDimensionDoesNotInvalidateIndex(t *testing.T) {
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+	// The catalog claims 768 dimensions but the model produces 384.
+	if _, err := service.SaveEmbeddingModel(models.EmbeddingModelInfo{ID: "local/misreported", Backend: "openai", Dimension: 768, BaseURL: newMigrationTestServer(t, 384, nil).URL}); err != nil {
+		t.Fatalf("save model: %v", err)
+	}
+	project := createProject(t, service, "Misreported Project")
+	config := project.Config
+	config.EmbeddingModel = "local/misreported"
+	config.EmbeddingModelInfo = nil
+	project, err := service.UpdateProjectConfig(project.ID, config)
+	if err != nil {
+		t.Fatalf("select model: %v", err)
+	}
+
+	vectorStore, err := service.GetVectorStore(project.ID)
+	if err != nil {
+		t.Fatalf("open vector store: %v", err)
+	}
+	if err := vectorStore.InsertChunk(&models.Chunk{FilePath: "a.go", LineStart: 1, LineEnd: 2, Embedding: make([]float32, 384), EmbeddingModelID: "local/misreported"}); err != nil {
+		t.Fatalf("insert chunk: %v", err)
+	}
+	if match, err := service.embeddingUsageMatchesSelection(project, vectorStore); err != nil || !match {
+		t.Fatalf("expected vectors of the model's real dimension to match (match=%v, err=%v)", match, err)
+	}
+}
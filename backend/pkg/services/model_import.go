@@ -0,0 +1,141 @@
+package services
+
+import (
+	"CodeTextor/backend/pkg/embedding"
+	"CodeTextor/backend/pkg/models"
+	"CodeTextor/backend/pkg/utils"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	importedModelPrefix = "local/"
+	importedSourceType  = "import"
+)
+
+// ImportEmbeddingModel registers an ONNX model from a local directory or .tar.gz archive
+// without network access. The model and tokenizer.json are copied into the models directory,
+// the tokenizer is test-encoded, and the dimension is taken from a probe inference.
+// Importing the same source again replaces the earlier import.
+func (s *ProjectService) ImportEmbeddingModel(path string) (*models.EmbeddingModelInfo, error) {
+	source := strings.TrimSpace(path)
+	if source == "" {
+		return nil, fmt.Errorf("import path cannot be empty")
+	}
+	source, err := filepath.Abs(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid import path: %w", err)
+	}
+	if !s.enableONNXRuntime {
+		return nil, fmt.Errorf("importing a model requires ONNX Runtime to probe its dimension: set the shared library path in Settings → Projects and restart CodeTextor")
+	}
+
+	name := importedModelName(source)
+	slug := utils.GenerateSlug(strings.ReplaceAll(name, ".", "-"))
+	if slug == "" {
+		slug = "model"
+	}
+	id := importedModelPrefix + slug
+	createdAt := int64(0)
+	if existing, err := s.configStore.GetEmbeddingModel(id); err == nil {
+		if existing.SourceType != importedSourceType {
+			return nil, fmt.Errorf("embedding model %s already exists in the catalog", id)
+		}
+		createdAt = existing.CreatedAt
+	}
+
+	meta := &models.EmbeddingModelInfo{
+		ID:             id,
+		DisplayName:    name + " (imported)",
+		Backend:        "onnx",
+		Description:    "ONNX model imported from local files.",
+		SourceType:     importedSourceType,
+		CodeQuality:    "good",
+		CodeFocus:      "general",
+		Notes:          "Imported from " + source,
+		DownloadStatus: "downloading",
+		CreatedAt:      createdAt,
+	}
+	targetPath, err := embedding.ResolveModelPath(meta)
+	if err != nil {
+		return nil, err
+	}
+	files, err := s.modelDownloader.ImportLocalModel(meta.ID, source, filepath.Dir(targetPath), s.makeDownloadProgressEmitter())
+	if err != nil {
+		return nil, err
+	}
+	if err := embedding.VerifyTokenizer(files.TokenizerPath); err != nil {
+		return nil, err
+	}
+	meta.LocalPath = files.ModelPath
+	meta.TokenizerLocalPath = files.TokenizerPath
+	meta.MaxSequenceLength = files.MaxSequenceLength
+	if files.FastEmbed {
+		meta.Description = "ONNX model imported from a fastembed archive."
+	}
+
+	s.closeCachedEmbeddingClient(meta.ID)
+	client, err := embedding.NewONNXEmbeddingClient(meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load imported model: %w", err)
+	}
+	dimension, err := client.ProbeDimension()
+	client.Close()
+	if err != nil {
+		return nil, err
+	}
+	meta.Dimension = dimension
+
+	// Record digests so later EnsureLocal calls detect a corrupted copy.
+	if meta.SHA256, err = embedding.FileSHA256(meta.LocalPath); err != nil {
+		return nil, err
+	}
+	if meta.TokenizerSHA256, err = embedding.FileSHA256(meta.TokenizerLocalPath); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(meta.LocalPath); err == nil {
+		meta.DiskSizeBytes = info.Size()
+	}
+	meta.DownloadStatus = "ready"
+
+	if err := s.configStore.UpsertEmbeddingModel(meta.Clone()); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// isImportedEmbeddingModel reports whether modelID was registered by ImportEmbeddingModel; like
+// remote models, imports are accepted although they are not part of the built-in catalog.
+func (s *ProjectService) isImportedEmbeddingModel(modelID string) bool {
+	meta, err := s.configStore.GetEmbeddingModel(modelID)
+	return err == nil && meta.SourceType == importedSourceType
+}
+
+// importedModelName derives a catalog name from the directory or archive being imported.
+func importedModelName(source string) string {
+	name := filepath.Base(source)
+	lower := strings.ToLower(name)
+	for _, suffix := range []string{".tar.gz", ".tgz"} {
+		if strings.HasSuffix(lower, suffix) {
+			name = name[:len(name)-len(suffix)]
+			break
+		}
+	}
+	if name == "" || name == "." || name == string(filepath.Separator) {
+		return "model"
+	}
+	return name
+}
+
+// closeCachedEmbeddingClient drops the cached client of a model whose files changed.
+func (s *ProjectService) closeCachedEmbeddingClient(modelID string) {
+	s.dropAdditionalClients(func(_, cached string) bool { return strings.EqualFold(cached, modelID) })
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	if client, ok := s.embeddingClients[modelID]; ok {
+		client.Close()
+		delete(s.embeddingClients, modelID)
+	}
+}
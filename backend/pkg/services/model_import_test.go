@@ -0,0 +1,59 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"CodeTextor/backend/pkg/models"
+)
+
+func TestImportedModelName(t *testing.T) {
+	cases := map[string]string{
+		"/models/fast-bge-small-en-v1.5.tar.gz": "fast-bge-small-en-v1.5",
+		"/models/e5-small.TGZ":                  "e5-small",
+		"/models/jina-code":                     "jina-code",
+		"/":                                     "model",
+	}
+	for source, want := range cases {
+		if got := importedModelName(source); got != want {
+			t.Errorf("importedModelName(%q) = %q, want %q", source, got, want)
+		}
+	}
+}
+
+func TestImportEmbeddingModelRequiresRuntime(t *testing.T) {
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+	service.enableONNXRuntime = false
+
+	if _, err := service.ImportEmbeddingModel(" "); err == nil {
+		t.Fatalf("expected an empty path to be rejected")
+	}
+	_, err := service.ImportEmbeddingModel(t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "ONNX Runtime") {
+		t.Fatalf("expected missing runtime error, got %v", err)
+	}
+}
+
+func TestImportedModelCanBeSelected(t *testing.T) {
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+	service.enableONNXRuntime = true
+
+	if err := service.configStore.UpsertEmbeddingModel(&models.EmbeddingModelInfo{
+		ID:             "local/jina-code",
+		Backend:        "onnx",
+		Dimension:      768,
+		SourceType:     importedSourceType,
+		DownloadStatus: "ready",
+	}); err != nil {
+		t.Fatalf("store imported model: %v", err)
+	}
+	config := models.ProjectConfig{EmbeddingModel: "local/jina-code"}
+	if err := service.ensureEmbeddingModelSnapshot(&config); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	if config.EmbeddingModel != "local/jina-code" || config.EmbeddingModelInfo == nil || config.EmbeddingModelInfo.Dimension != 768 {
+		t.Fatalf("imported model replaced by %s", config.EmbeddingModel)
+	}
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"CodeTextor/backend/internal/store"
+	"CodeTextor/backend/pkg/embedding"
+	"CodeTextor/backend/pkg/indexing"
+	"CodeTextor/backend/pkg/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	// migrationBatchSize is how many chunks are re-embedded per request to the model.
+	migrationBatchSize = 32
+	// migrationPromoteAttempts bounds how often the swap is retried when chunks are re-indexed
+	// between the final catch-up and the swap.
+	migrationPromoteAttempts = 3
+)
+
+// MigrateEmbeddingModel switches a project to modelID without taking search offline. Chunks
+// are re-embedded in the background into chunk_embeddings while the current embeddings keep
+// serving queries; once every chunk is covered the indexer is paused, the remaining chunks are
+// caught up and the new vectors and project config are swapped in a single transaction.
+// Progress is reported through GetIndexingProgress with status "migrating" and the migration
+// can be cancelled with CancelEmbeddingMigration. Projects without indexed chunks switch at once.
+// Switching to one of the project's additional models reuses its stored vectors when no output
+// dimension applies, and removes it from the additional models.
+func (s *ProjectService) MigrateEmbeddingModel(projectID, modelID string) error {
+	modelID = strings.TrimSpace(modelID)
+	if modelID == "" {
+		return fmt.Errorf("embedding model cannot be empty")
+	}
+	project, err := s.GetProject(projectID)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(project.Config.EmbeddingModel, modelID) {
+		return fmt.Errorf("project %s already uses %s", projectID, modelID)
+	}
+
+	target := project.Config
+	target.EmbeddingModel = modelID
+	target.EmbeddingModelInfo = nil
+	if err := s.ensureEmbeddingModelSnapshot(&target); err != nil {
+		return err
+	}
+	if !strings.EqualFold(target.EmbeddingModel, modelID) {
+		return fmt.Errorf("embedding model %s cannot be used on this machine", modelID)
+	}
+	if err := s.normalizeOutputDimension(&target, true); err != nil {
+		return err
+	}
+
+	vectorStore, err := s.GetVectorStore(projectID)
+	if err != nil {
+		return err
+	}
+	stats, err := vectorStore.GetStats()
+	if err != nil {
+		return err
+	}
+	if stats.TotalChunks == 0 {
+		_, err := s.UpdateProjectConfig(projectID, target)
+		return err
+	}
+
+	reuse := target.OutputDimension == 0 && containsModelID(project.Config.AdditionalEmbeddingModels, target.EmbeddingModel)
+	return s.indexerManager.StartMigration(projectID, target.EmbeddingModel, func(ctx context.Context, progress *models.IndexingProgress) error {
+		return s.runEmbeddingMigration(ctx, projectID, target, reuse, vectorStore, progress)
+	})
+}
+
+// CancelEmbeddingMigration stops a running model migration; the project keeps its current
+// model and the partially built embeddings are discarded.
+func (s *ProjectService) CancelEmbeddingMigration(projectID string) error {
+	if !s.indexerManager.StopMigration(projectID) {
+		return fmt.Errorf("no embedding model migration is running for project %s", projectID)
+	}
+	return nil
+}
+
+// runEmbeddingMigration re-embeds the chunks with target's model and swaps it in. With reuse the
+// vectors the model already keeps as an additional model are completed instead of rebuilt.
+func (s *ProjectService) runEmbeddingMigration(ctx context.Context, projectID string, target models.ProjectConfig, reuse bool, vectorStore *store.VectorStore, progress *models.IndexingProgress) (err error) {
+	modelID := target.EmbeddingModel
+	// Vectors left by an earlier attempt may use another output dimension or storage mode.
+	if !reuse {
+		if err := vectorStore.DeleteModelEmbeddings(modelID); err != nil {
+			return err
+		}
+	}
+	defer func() {
+		if err == nil || reuse {
+			return
+		}
+		if cleanupErr := vectorStore.DeleteModelEmbeddings(modelID); cleanupErr != nil {
+			log.Printf("Failed to discard embeddings of the aborted migration of %s: %v", projectID, cleanupErr)
+		}
+	}()
+
+	client, err := s.configEmbeddingClient(projectID, &target)
+	if err != nil {
+		return err
+	}
+	if err := s.embedMissingChunks(ctx, projectID, vectorStore, client, modelID, progress); err != nil {
+		return err
+	}
+
+	// Pause the indexer so no chunks embedded with the old model appear between the last
+	// catch-up and the swap; it is restarted afterwards, with the new model on success.
+	s.indexerManager.StopIndexer(projectID)
+	promoted := false
+	defer func() {
+		if promoted {
+			return
+		}
+		if project, err := s.GetProject(projectID); err == nil && project.IsIndexing {
+			if err := s.StartIndexing(projectID); err != nil {
+				log.Printf("Failed to resume indexing of %s after migration: %v", projectID, err)
+			}
+		}
+	}()
+	for attempt := 1; ; attempt++ {
+		if err := s.embedMissingChunks(ctx, projectID, vectorStore, client, modelID, progress); err != nil {
+			return err
+		}
+		project, err := s.GetProject(projectID)
+		if err != nil {
+			return err
+		}
+		project.Config.EmbeddingModel = target.EmbeddingModel
+		project.Config.EmbeddingModelInfo = target.EmbeddingModelInfo
+		project.Config.EmbeddingBackend = target.EmbeddingBackend
+		project.Config.OutputDimension = target.OutputDimension
+		project.Config.AdditionalEmbeddingModels = removeModelID(project.Config.AdditionalEmbeddingModels, modelID)
+		project.UpdatedAt = time.Now().Unix()
+
+		count, err := vectorStore.PromoteModelEmbeddings(modelID, project)
+		if errors.Is(err, store.ErrModelEmbeddingsIncomplete) && attempt < migrationPromoteAttempts {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		promoted = true
+		s.dropAdditionalClients(func(id, cached string) bool { return id == projectID && strings.EqualFold(cached, modelID) })
+		log.Printf("Switched project %s to %s (%d chunks re-embedded)", projectID, modelID, count)
+
+		if project.IsIndexing {
+			if err := s.StartIndexing(projectID); err != nil {
+				return fmt.Errorf("model switched but indexing could not restart: %w", err)
+			}
+		}
+		return nil
+	}
+}
+
+// configEmbeddingClient returns an embedding client for a config that is not (yet) the project's.
+// Local models are downloaded first so getEmbeddingClient never persists config on the project.
+func (s *ProjectService) configEmbeddingClient(projectID string, target *models.ProjectConfig) (embedding.EmbeddingClient, error) {
+	meta := target.EmbeddingModelInfo
+	if strings.EqualFold(meta.Backend, "onnx") && (strings.TrimSpace(meta.LocalPath) == "" || !strings.EqualFold(meta.DownloadStatus, "ready")) {
+		updated, err := s.DownloadEmbeddingModel(meta.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download ONNX model %s: %w", meta.ID, err)
+		}
+		target.EmbeddingModelInfo = updated.Clone()
+		target.EmbeddingModelInfo.APIKey = ""
+	}
+	client, err := s.getEmbeddingClient(&models.Project{ID: projectID, Config: *target})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize embedding model: %w", err)
+	}
+	return client, nil
+}
+
+// embedMissingChunks embeds every chunk that has no vector from modelID yet, reporting the
+// coverage through progress, until none is left or ctx is cancelled. When progress is the one
+// of projectID's running migration, the coverage is also emitted as indexing status events.
+func (s *ProjectService) embedMissingChunks(ctx context.Context, projectID string, vectorStore *store.VectorStore, client embedding.EmbeddingClient, modelID string, progress *models.IndexingProgress) error {
+	return indexing.EmbedMissingChunks(ctx, vectorStore, client, modelID, migrationBatchSize, func(covered, total int) {
+		progress.ProcessedChunks = covered
+		progress.TotalChunks = total
+		s.indexerManager.ReportMigrationProgress(projectID, progress)
+	})
+}
+
+// removeModelID returns modelIDs without modelID, or nil when nothing is left.
+func removeModelID(modelIDs []string, modelID string) []string {
+	var kept []string
+	for _, candidate := range modelIDs {
+		if !strings.EqualFold(candidate, modelID) {
+			kept = append(kept, candidate)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"CodeTextor/backend/pkg/models"
+)
+
+// newMigrationTestServer serves an OpenAI-compatible endpoint returning dim-sized vectors. When
+// gate is non-nil every request blocks until it is closed.
+func newMigrationTestServer(t *testing.T, dim int, gate chan struct{}) *httptest.Server {
+	t.Helper()
+	embeddings := embeddingTestHandler(func(text string) []float32 {
+		vec := make([]float32, dim)
+		vec[len(text)%dim] = 1
+		return vec
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if gate != nil {
+			<-gate
+		}
+		embeddings.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// setupMigrationProject creates a project using local/old (2 dimensions) with chunkCount
+// indexed chunks and registers local/new (3 dimensions) served by newServer.
+func setupMigrationProject(t *testing.T, service *ProjectService, chunkCount int, newServer *httptest.Server) *models.Project {
+	t.Helper()
+	entries := []models.EmbeddingModelInfo{
+		{ID: "local/old", Backend: "openai", Dimension: 2, BaseURL: newMigrationTestServer(t, 2, nil).URL},
+		{ID: "local/new", Backend: "openai", Dimension: 3, BaseURL: newServer.URL},
+	}
+	for _, entry := range entries {
+		if _, err := service.SaveEmbeddingModel(entry); err != nil {
+			t.Fatalf("save %s: %v", entry.ID, err)
+		}
+	}
+
+	project := createProject(t, service, "Migrating Project")
+	config := project.Config
+	config.EmbeddingModel = "local/old"
+	config.EmbeddingModelInfo = nil
+	project, err := service.UpdateProjectConfig(project.ID, config)
+	if err != nil {
+		t.Fatalf("select old model: %v", err)
+	}
+
+	vectorStore, err := service.GetVectorStore(project.ID)
+	if err != nil {
+		t.Fatalf("open vector store: %v", err)
+	}
+	for i := 0; i < chunkCount; i++ {
+		chunk := &models.Chunk{FilePath: "main.go", LineStart: i * 10, LineEnd: i*10 + 5, Content: "func f()", Embedding: []float32{0.6, 0.8}, EmbeddingModelID: "local/old"}
+		if err := vectorStore.InsertChunk(chunk); err != nil {
+			t.Fatalf("insert chunk: %v", err)
+		}
+	}
+	return project
+}
+
+func waitForMigration(t *testing.T, service *ProjectService, projectID string) models.IndexingProgress {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if !service.indexerManager.IsMigrating(projectID) {
+			progress, err := service.GetIndexingProgress(projectID)
+			if err != nil {
+				t.Fatalf("progress: %v", err)
+			}
+			return progress
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("migration did not finish in time")
+	return models.IndexingProgress{}
+}
+
+func TestMigrateEmbeddingModelSwapsEmbeddings(t *testing.T) {
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+	project := setupMigrationProject(t, service, migrationBatchSize+5, newMigrationTestServer(t, 3, nil))
+
+	if err := service.MigrateEmbeddingModel(project.ID, "local/new"); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	progress := waitForMigration(t, service, project.ID)
+	if progress.Status != models.IndexingStatusCompleted || progress.MigrationModel != "local/new" {
+		t.Fatalf("unexpected final progress %+v", progress)
+	}
+	if progress.ProcessedChunks != migrationBatchSize+5 || progress.TotalChunks != migrationBatchSize+5 {
+		t.Fatalf("expected every chunk to be counted, got %+v", progress)
+	}
+
+	updated, err := service.GetProject(project.ID)
+	if err != nil {
+		t.Fatalf("get project: %v", err)
+	}
+	if updated.Config.EmbeddingModel != "local/new" || updated.Config.EmbeddingModelInfo.ID != "local/new" {
+		t.Fatalf("expected the project to use local/new, got %s", updated.Config.EmbeddingModel)
+	}
+
+	stats, err := service.GetProjectStats(project.ID)
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	if len(stats.EmbeddingModels) != 1 || stats.EmbeddingModels[0].ModelID != "local/new" || stats.EmbeddingModels[0].ChunkCount != migrationBatchSize+5 {
+		t.Fatalf("expected every chunk to be embedded by local/new, got %+v", stats.EmbeddingModels)
+	}
+	vectorStore, _ := service.GetVectorStore(project.ID)
+	if dims, _ := vectorStore.EmbeddingDimensions(); len(dims) != 1 || dims[0] != 3 {
+		t.Fatalf("expected 3-dimensional embeddings, got %v", dims)
+	}
+	if covered, _, _ := vectorStore.ModelEmbeddingCoverage("local/new"); covered != 0 {
+		t.Fatalf("expected promoted rows to be removed from chunk_embeddings, %d left", covered)
+	}
+	if results, _, err := vectorStore.SearchSimilarChunksAbove([]float32{1, 0, 0}, 1, math.Inf(-1)); err != nil || len(results) != 1 {
+		t.Fatalf("expected search to use the new vectors: %v %v", results, err)
+	}
+}
+
+func TestMigrationEmitsProgressBetweenBatches(t *testing.T) {
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+	embeddings := embeddingTestHandler(func(string) []float32 { return []float32{1, 0, 0} })
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(600 * time.Millisecond)
+		embeddings.ServeHTTP(w, r)
+	}))
+	t.Cleanup(slow.Close)
+	project := setupMigrationProject(t, service, migrationBatchSize+5, slow)
+
+	var mu sync.Mutex
+	var processed []int
+	service.AddEventListener(func(event string, data interface{}) {
+		payload, ok := data.(map[string]interface{})
+		if event != "project:indexingStatus" || !ok || payload["status"] != string(models.IndexingStatusMigrating) {
+			return
+		}
+		mu.Lock()
+		processed = append(processed, payload["processedChunks"].(int))
+		mu.Unlock()
+	})
+
+	if err := service.MigrateEmbeddingModel(project.ID, "local/new"); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	waitForMigration(t, service, project.ID)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(processed) < 3 || processed[0] != 0 || processed[1] != migrationBatchSize {
+		t.Fatalf("expected the start event, one per slow batch and the final one, got %v", processed)
+	}
+}
+
+func TestCancelEmbeddingMigrationKeepsCurrentModel(t *testing.T) {
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+	gate := make(chan struct{})
+	project := setupMigrationProject(t, service, 3, newMigrationTestServer(t, 3, gate))
+
+	if err := service.MigrateEmbeddingModel(project.ID, "local/new"); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := service.MigrateEmbeddingModel(project.ID, "local/new"); err == nil {
+		t.Fatalf("expected a second migration to be rejected")
+	}
+	if progress, _ := service.GetIndexingProgress(project.ID); progress.Status != models.IndexingStatusMigrating {
+		t.Fatalf("expected migrating status, got %+v", progress)
+	}
+	if err := service.CancelEmbeddingMigration(project.ID); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	close(gate)
+
+	if progress := waitForMigration(t, service, project.ID); progress.Status != models.IndexingStatusIdle {
+		t.Fatalf("expected cancelled migration to end idle, got %+v", progress)
+	}
+	updated, _ := service.GetProject(project.ID)
+	if updated.Config.EmbeddingModel != "local/old" {
+		t.Fatalf("expected the project to keep local/old, got %s", updated.Config.EmbeddingModel)
+	}
+	stats, _ := service.GetProjectStats(project.ID)
+	if len(stats.EmbeddingModels) != 1 || stats.EmbeddingModels[0].ModelID != "local/old" {
+		t.Fatalf("expected the old embeddings to stay, got %+v", stats.EmbeddingModels)
+	}
+	vectorStore, _ := service.GetVectorStore(project.ID)
+	if covered, _, _ := vectorStore.ModelEmbeddingCoverage("local/new"); covered != 0 {
+		t.Fatalf("expected partial embeddings to be discarded, %d left", covered)
+	}
+	if err := service.CancelEmbeddingMigration(project.ID); err == nil {
+		t.Fatalf("expected cancelling without a migration to fail")
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"CodeTextor/backend/pkg/embedding"
+	"CodeTextor/backend/pkg/models"
+	"fmt"
+	"log"
+	"strings"
+)
+
+const ollamaModelPrefix = "ollama/"
+
+// syncOllamaModels adds the embedding models pulled into the local Ollama server to the catalog
+// and marks catalog entries that are no longer pulled as missing. Unreachable servers are ignored
+// so listing the catalog never depends on Ollama running.
+func (s *ProjectService) syncOllamaModels() {
+	pulled, err := embedding.ListOllamaModels(embedding.DefaultOllamaBaseURL())
+	if err != nil {
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, model := range pulled {
+		if !model.IsEmbeddingModel() {
+			continue
+		}
+		id := ollamaModelID(model.Name)
+		seen[id] = true
+		entry := ollamaCatalogEntry(model)
+		if existing, err := s.configStore.GetEmbeddingModel(id); err == nil {
+			// Keep what was probed or edited by the user.
+			entry.Dimension = existing.Dimension
+			entry.BaseURL = existing.BaseURL
+			entry.BatchSize = existing.BatchSize
+			entry.MaxRetries = existing.MaxRetries
+			entry.TimeoutSeconds = existing.TimeoutSeconds
+			entry.QueryPrefix = existing.QueryPrefix
+			entry.DocumentPrefix = existing.DocumentPrefix
+			entry.CreatedAt = existing.CreatedAt
+		}
+		if err := s.configStore.UpsertEmbeddingModel(entry); err != nil {
+			log.Printf("Failed to store Ollama model %s: %v", id, err)
+		}
+	}
+
+	entries, err := s.configStore.ListEmbeddingModels()
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if strings.EqualFold(entry.Backend, "ollama") && entry.BaseURL == "" && !seen[entry.ID] && entry.DownloadStatus != "missing" {
+			entry.DownloadStatus = "missing"
+			if err := s.configStore.UpsertEmbeddingModel(entry); err != nil {
+				log.Printf("Failed to update Ollama model %s: %v", entry.ID, err)
+			}
+		}
+	}
+}
+
+// ollamaModelID maps an Ollama tag to a catalog id, dropping the implicit ":latest".
+func ollamaModelID(name string) string {
+	return ollamaModelPrefix + strings.TrimSuffix(name, ":latest")
+}
+
+func ollamaCatalogEntry(model embedding.OllamaModel) *models.EmbeddingModelInfo {
+	name := strings.TrimSuffix(model.Name, ":latest")
+	description := "Embedding model served by the local Ollama server."
+	if model.Details.ParameterSize != "" {
+		description = fmt.Sprintf("%s (%s parameters) served by the local Ollama server.", name, model.Details.ParameterSize)
+	}
+	queryPrefix, documentPrefix := ollamaInstructionPrefixes(name)
+	return &models.EmbeddingModelInfo{
+		ID:                 ollamaModelID(model.Name),
+		DisplayName:        "Ollama · " + name,
+		Backend:            "ollama",
+		Description:        description,
+		DiskSizeBytes:      model.Size,
+		SourceType:         "ollama",
+		RemoteModel:        model.Name,
+		CodeFocus:          "general",
+		Notes:              "Pulled with `ollama pull`; the dimension is detected on first use.",
+		DownloadStatus:     "ready",
+		QueryPrefix:        queryPrefix,
+		DocumentPrefix:     documentPrefix,
+		SupportsMatryoshka: ollamaSupportsMatryoshka(name),
+	}
+}
+
+// ollamaSupportsMatryoshka reports whether a well-known Ollama embedding model was trained
+// for Matryoshka truncation.
+func ollamaSupportsMatryoshka(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasPrefix(lower, "nomic-embed-text") || strings.HasPrefix(lower, "mxbai-embed-large")
+}
+
+// ollamaInstructionPrefixes returns the prefixes published for well-known Ollama embedding models.
+func ollamaInstructionPrefixes(name string) (string, string) {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasPrefix(lower, "nomic-embed-text"):
+		return "search_query: ", "search_document: "
+	case strings.HasPrefix(lower, "mxbai-embed-large"), strings.HasPrefix(lower, "snowflake-arctic-embed"):
+		return "Represent this sentence for searching relevant passages: ", ""
+	default:
+		return "", ""
+	}
+}
+
+// ensureOllamaDimension probes the server once for models whose dimension is still unknown
+// and stores the result in the catalog.
+func (s *ProjectService) ensureOllamaDimension(meta *models.EmbeddingModelInfo) error {
+	if !strings.EqualFold(meta.Backend, "ollama") || meta.Dimension > 0 {
+		return nil
+	}
+	client, err := embedding.NewOllamaEmbeddingClient(meta)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	dimension, err := client.ProbeDimension()
+	if err != nil {
+		return err
+	}
+	meta.Dimension = dimension
+	return s.configStore.UpsertEmbeddingModel(meta.Clone())
+}
+
+// ollamaAvailable reports whether the default Ollama server answers.
+func ollamaAvailable() bool {
+	_, err := embedding.ListOllamaModels(embedding.DefaultOllamaBaseURL())
+	return err == nil
+}
@@ -12,8 +12,10 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -79,11 +81,14 @@ type ProjectServiceAPI interface {
 	GetFileOutline(projectID, path string) ([]*models.OutlineNode, error)
 	GetFileChunks(projectID, path string) ([]*models.Chunk, error)
 	GetChunkByID(projectID, chunkID string) (*models.Chunk, error)
+	FindSymbolChunks(projectID string, names, kinds []string) ([]*models.Chunk, error)
 	GetOutlineTimestamps(projectID string) (map[string]int64, error)
+	ListIndexedFiles(projectID string) ([]string, error)
 	ReadFileContent(projectID, relativePath string) (string, error)
 	StartIndexing(projectID string) error
 	ResetProjectIndex(projectID string) error
 	ReindexProject(projectID string) error
+	ReindexFiles(projectID string, paths []string) ([]models.FileReindexResult, error)
 	StopIndexing(projectID string) error
 	GetIndexingProgress(projectID string) (models.IndexingProgress, error)
 	GetGitIgnorePatterns(projectID string) ([]string, error)
@@ -92,11 +97,23 @@ type ProjectServiceAPI interface {
 	ListEmbeddingModels() ([]*models.EmbeddingModelInfo, error)
 	SaveEmbeddingModel(model models.EmbeddingModelInfo) (*models.EmbeddingModelInfo, error)
 	DownloadEmbeddingModel(modelID string) (*models.EmbeddingModelInfo, error)
+	ImportEmbeddingModel(path string) (*models.EmbeddingModelInfo, error)
+	MigrateEmbeddingModel(projectID, modelID string) error
+	CancelEmbeddingMigration(projectID string) error
+	ListRerankerModels() ([]*models.EmbeddingModelInfo, error)
+	DownloadRerankerModel(modelID string) (*models.EmbeddingModelInfo, error)
 	GetEmbeddingCapabilities() (*models.EmbeddingCapabilities, error)
 	GetONNXRuntimeSettings() (*models.ONNXRuntimeSettings, error)
 	UpdateONNXRuntimeSettings(path string) (*models.ONNXRuntimeSettings, error)
 	TestONNXRuntimePath(path string) (*models.ONNXRuntimeTestResult, error)
 	Search(projectID string, query string, k int) (*models.SearchResponse, error)
+	SearchWithOptions(req models.SearchRequest) (*models.SearchResponse, error)
+	SimilarTo(projectID, chunkID string, k int, scope models.SimilarScope) (*models.SimilarResponse, error)
+	GetEvaluationCases(projectID string) ([]models.EvalCase, error)
+	SaveEvaluationCases(projectID string, cases []models.EvalCase) ([]models.EvalCase, error)
+	GenerateEvaluationCases(projectID string, limit int) ([]models.EvalCase, error)
+	RunEvaluation(ctx context.Context, req models.EvalRequest) (*models.EvalReport, error)
+	AddEventListener(listener func(string, interface{}))
 	Close() error
 }
 
@@ -111,8 +128,12 @@ type ProjectService struct {
 	vectorStores      map[string]*store.VectorStore
 	mu                sync.Mutex
 	eventEmitter      func(string, interface{})
+	eventListeners    []func(string, interface{})
+	listenersMu       sync.RWMutex
 	modelDownloader   *embedding.Downloader
 	embeddingClients  map[string]embedding.EmbeddingClient
+	rerankers         map[string]embedding.Reranker
+	additionalClients map[additionalClientKey]cachedAdditionalClient
 	clientsMu         sync.Mutex
 	enableONNXRuntime bool
 	onnxRuntimePath   string
@@ -157,13 +178,15 @@ func NewProjectService(ctx context.Context) (*ProjectService, error) {
 	service := &ProjectService{
 		indexesDir:        indexesDir,
 		configStore:       configStore,
-		indexerManager:    indexing.NewManager(eventEmitter),
 		vectorStores:      make(map[string]*store.VectorStore),
 		eventEmitter:      eventEmitter,
 		modelDownloader:   embedding.NewDownloader(),
 		embeddingClients:  make(map[string]embedding.EmbeddingClient),
+		rerankers:         make(map[string]embedding.Reranker),
+		additionalClients: make(map[additionalClientKey]cachedAdditionalClient),
 		enableONNXRuntime: false,
 	}
+	service.indexerManager = indexing.NewManager(service.emitEvent)
 
 	// Load persisted ONNX runtime path before detection so initialization uses it.
 	if path, ok, err := configStore.GetValue(onnxRuntimePathKey); err == nil && ok {
@@ -188,6 +211,29 @@ func NewProjectService(ctx context.Context) (*ProjectService, error) {
 	return service, nil
 }
 
+// AddEventListener registers a callback that receives every backend event
+// (indexing progress, file updates) in addition to the Wails runtime emitter.
+func (s *ProjectService) AddEventListener(listener func(string, interface{})) {
+	if listener == nil {
+		return
+	}
+	s.listenersMu.Lock()
+	s.eventListeners = append(s.eventListeners, listener)
+	s.listenersMu.Unlock()
+}
+
+func (s *ProjectService) emitEvent(event string, data interface{}) {
+	if s.eventEmitter != nil {
+		s.eventEmitter(event, data)
+	}
+	s.listenersMu.RLock()
+	listeners := append([]func(string, interface{}){}, s.eventListeners...)
+	s.listenersMu.RUnlock()
+	for _, listener := range listeners {
+		listener(event, data)
+	}
+}
+
 // initializeAutoIndexing starts indexing for all projects that have ContinuousIndexing enabled.
 func (s *ProjectService) initializeAutoIndexing() error {
 	projects, err := s.ListProjects()
@@ -381,6 +427,40 @@ func (s *ProjectService) applyConfig(project *models.Project, config models.Proj
 	if err := s.ensureEmbeddingModelSnapshot(&config); err != nil {
 		return err
 	}
+	modelChanged := !strings.EqualFold(config.EmbeddingModel, project.Config.EmbeddingModel)
+	if modelChanged {
+		// Choosing a model directly supersedes a background migration to another one.
+		s.indexerManager.StopMigration(project.ID)
+	}
+	carriedOver := modelChanged && config.OutputDimension == project.Config.OutputDimension
+	if err := s.normalizeOutputDimension(&config, carriedOver); err != nil {
+		return err
+	}
+	if err := s.normalizeAdditionalModels(&config, project.Config.AdditionalEmbeddingModels); err != nil {
+		return err
+	}
+
+	// Callers that predate reranking send configs without it; keep the stored settings.
+	if config.Rerank == nil {
+		config.Rerank = project.Config.Rerank
+	}
+	if err := normalizeRerankConfig(config.Rerank); err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(config.EmbeddingStorage) == "" {
+		config.EmbeddingStorage = project.Config.EmbeddingStorage
+	}
+	storage, err := normalizeEmbeddingStorage(config.EmbeddingStorage)
+	if err != nil {
+		return err
+	}
+	config.EmbeddingStorage = storage
+	if current, _ := normalizeEmbeddingStorage(project.Config.EmbeddingStorage); current != storage {
+		if err := s.migrateEmbeddingStorage(project.ID, storage); err != nil {
+			return err
+		}
+	}
 
 	project.Config = config
 	return nil
@@ -394,6 +474,7 @@ func (s *ProjectService) UpdateProject(req UpdateProjectRequest) (*models.Projec
 	}
 
 	updated := false
+	previousModels := project.Config.AdditionalEmbeddingModels
 	if req.Name != nil && *req.Name != project.Name {
 		project.Name = *req.Name
 		updated = true
@@ -416,6 +497,7 @@ func (s *ProjectService) UpdateProject(req UpdateProjectRequest) (*models.Projec
 	if err := s.updateProjectMetadata(project); err != nil {
 		return nil, err
 	}
+	s.syncAdditionalEmbeddings(project, previousModels)
 
 	return project, nil
 }
@@ -427,6 +509,7 @@ func (s *ProjectService) UpdateProjectConfig(projectID string, config models.Pro
 		return nil, err
 	}
 
+	previousModels := project.Config.AdditionalEmbeddingModels
 	if err := s.applyConfig(project, config); err != nil {
 		return nil, err
 	}
@@ -434,12 +517,16 @@ func (s *ProjectService) UpdateProjectConfig(projectID string, config models.Pro
 	if err := s.updateProjectMetadata(project); err != nil {
 		return nil, err
 	}
+	s.syncAdditionalEmbeddings(project, previousModels)
 
 	return project, nil
 }
 
 // DeleteProject removes a project database.
 func (s *ProjectService) DeleteProject(projectID string) error {
+	s.indexerManager.StopMigration(projectID)
+	s.indexerManager.StopEvaluation(projectID)
+	s.dropAdditionalClients(func(id, _ string) bool { return id == projectID })
 	s.mu.Lock()
 	if vs, ok := s.vectorStores[projectID]; ok {
 		vs.Close()
@@ -572,6 +659,7 @@ func (s *ProjectService) GetVectorStore(projectID string) (*store.VectorStore, e
 	if err != nil {
 		return nil, err
 	}
+	vs.SetEmbeddingStorage(project.Config.EmbeddingStorage)
 
 	s.vectorStores[projectID] = vs
 	return vs, nil
@@ -597,6 +685,10 @@ func (s *ProjectService) embeddingUsageMatchesSelection(project *models.Project,
 	normalizedSelected := strings.ToLower(selectedID)
 
 	for _, usage := range stats.EmbeddingModels {
+		if !usage.Primary {
+			// Vectors of additional models live apart from the chunks' own embeddings.
+			continue
+		}
 		usageID := strings.TrimSpace(usage.ModelID)
 		if usageID == "" {
 			if normalizedSelected != "" {
@@ -613,6 +705,21 @@ func (s *ProjectService) embeddingUsageMatchesSelection(project *models.Project,
 			return false, nil
 		}
 	}
+
+	// Vectors of another length (e.g. after changing the output dimension) cannot be compared.
+	// Only an explicit output dimension is checked: catalog dimensions are not always what the
+	// model actually produces.
+	if expected := project.Config.OutputDimension; expected > 0 {
+		dimensions, err := vectorStore.EmbeddingDimensions()
+		if err != nil {
+			return false, err
+		}
+		for _, dimension := range dimensions {
+			if dimension != expected {
+				return false, nil
+			}
+		}
+	}
 	return true, nil
 }
 
@@ -638,7 +745,7 @@ func (s *ProjectService) StartIndexing(projectID string) error {
 		return fmt.Errorf("failed to initialize embedding model: %w", err)
 	}
 
-	if err := s.indexerManager.StartIndexer(project, files, vectorStore, client, nil); err != nil {
+	if err := s.indexerManager.StartIndexer(project, files, vectorStore, client, s.additionalEmbeddingClients(project), nil); err != nil {
 		return fmt.Errorf("failed to start indexer: %w", err)
 	}
 	return nil
@@ -651,7 +758,8 @@ func (s *ProjectService) ResetProjectIndex(projectID string) error {
 		return err
 	}
 
-	// Ensure no indexer is running while we wipe data.
+	// Ensure no indexer or migration is running while we wipe data.
+	s.indexerManager.StopMigration(projectID)
 	s.indexerManager.StopIndexer(projectID)
 
 	vectorStore, err := s.GetVectorStore(project.ID)
@@ -673,7 +781,8 @@ func (s *ProjectService) ReindexProject(projectID string) error {
 		return err
 	}
 
-	// Ensure no indexer is running while we wipe data.
+	// Ensure no indexer or migration is running while we wipe data.
+	s.indexerManager.StopMigration(projectID)
 	s.indexerManager.StopIndexer(projectID)
 
 	vectorStore, err := s.GetVectorStore(project.ID)
@@ -695,14 +804,77 @@ func (s *ProjectService) ReindexProject(projectID string) error {
 		return fmt.Errorf("failed to initialize embedding model: %w", err)
 	}
 
-	if err := s.indexerManager.StartIndexer(project, files, vectorStore, client, nil); err != nil {
+	if err := s.indexerManager.StartIndexer(project, files, vectorStore, client, s.additionalEmbeddingClients(project), nil); err != nil {
 		return fmt.Errorf("failed to start indexer: %w", err)
 	}
 	return nil
 }
 
-// StopIndexing halts the project indexer.
+// ReindexFiles re-indexes specific files (relative to the project root) without a full run.
+// Paths outside the project root are reported as failed instead of aborting the batch.
+func (s *ProjectService) ReindexFiles(projectID string, paths []string) ([]models.FileReindexResult, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("at least one path is required")
+	}
+	project, err := s.GetProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	vectorStore, err := s.GetVectorStore(project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open vector store for reindexing: %w", err)
+	}
+
+	client, err := s.getEmbeddingClient(project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize embedding model: %w", err)
+	}
+
+	results := make([]models.FileReindexResult, len(paths))
+	absPaths := make([]string, 0, len(paths))
+	positions := make([]int, 0, len(paths))
+	for idx, path := range paths {
+		path = strings.TrimSpace(path)
+		results[idx] = models.FileReindexResult{Path: path}
+		absPath := path
+		if !filepath.IsAbs(absPath) {
+			absPath = filepath.Join(project.Config.RootPath, filepath.FromSlash(path))
+		}
+		rel, ok := utils.RelativePathWithinRoot(project.Config.RootPath, absPath)
+		if path == "" || !ok || rel == "" {
+			results[idx].Status = models.FileReindexFailed
+			results[idx].Error = "path must point to a file inside the project root"
+			continue
+		}
+		results[idx].Path = rel
+		if !fileIncludedByConfig(project.Config, absPath) {
+			results[idx].Status = models.FileReindexExcluded
+			results[idx].Error = "file is excluded by the project's include/exclude filters"
+			continue
+		}
+		absPaths = append(absPaths, absPath)
+		positions = append(positions, idx)
+	}
+	if len(absPaths) == 0 {
+		return results, nil
+	}
+
+	updated, err := s.indexerManager.ReindexFiles(project, absPaths, vectorStore, client, s.additionalEmbeddingClients(project))
+	if err != nil {
+		return nil, fmt.Errorf("failed to reindex files: %w", err)
+	}
+	for idx, result := range updated {
+		result.Path = results[positions[idx]].Path
+		results[positions[idx]] = result
+	}
+	return results, nil
+}
+
+// StopIndexing halts the project indexer, any embedding model migration and any evaluation.
 func (s *ProjectService) StopIndexing(projectID string) error {
+	s.indexerManager.StopMigration(projectID)
+	s.indexerManager.StopEvaluation(projectID)
 	s.indexerManager.StopIndexer(projectID)
 	return nil
 }
@@ -741,7 +913,7 @@ func (s *ProjectService) ensureEmbeddingModelSnapshot(config *models.ProjectConf
 		config.EmbeddingModel = defaultFastEmbedModelID
 	}
 
-	if _, ok := supportedEmbeddingModelIDs[strings.ToLower(config.EmbeddingModel)]; !ok {
+	if _, ok := supportedEmbeddingModelIDs[strings.ToLower(config.EmbeddingModel)]; !ok && !s.isRemoteEmbeddingModel(config.EmbeddingModel) && !s.isImportedEmbeddingModel(config.EmbeddingModel) {
 		log.Printf("Embedding model %s unsupported, falling back to default", config.EmbeddingModel)
 		config.EmbeddingModel = defaultFastEmbedModelID
 	}
@@ -767,17 +939,41 @@ func (s *ProjectService) ensureEmbeddingModelSnapshot(config *models.ProjectConf
 		return s.ensureEmbeddingModelSnapshot(config)
 	}
 
+	if err := s.ensureOllamaDimension(meta); err != nil {
+		log.Printf("Could not detect the dimension of %s yet: %v", meta.ID, err)
+	}
 	refreshModelLocalStatus(meta)
 	if err := s.configStore.UpsertEmbeddingModel(meta.Clone()); err != nil {
 		return err
 	}
 
 	config.EmbeddingModelInfo = meta.Clone()
+	config.EmbeddingModelInfo.APIKey = ""
 	config.EmbeddingBackend = meta.Backend
 	return nil
 }
 
+// isRemoteEmbeddingModel reports whether a user-defined catalog entry uses an HTTP backend;
+// those are accepted even though they are not part of the built-in catalog.
+func (s *ProjectService) isRemoteEmbeddingModel(modelID string) bool {
+	meta, err := s.configStore.GetEmbeddingModel(modelID)
+	return err == nil && meta.IsRemote()
+}
+
+// getEmbeddingClient returns the project's embedding client with the model's query/document
+// prefixes and the project's output dimension applied. Prefixes come from the project snapshot,
+// i.e. the settings the index was built with.
 func (s *ProjectService) getEmbeddingClient(project *models.Project) (embedding.EmbeddingClient, error) {
+	client, err := s.getBackendEmbeddingClient(project)
+	if err != nil {
+		return nil, err
+	}
+	meta := project.Config.EmbeddingModelInfo
+	client = embedding.WithInstructionPrefixes(client, meta.QueryPrefix, meta.DocumentPrefix)
+	return embedding.WithOutputDimension(client, project.Config.OutputDimension), nil
+}
+
+func (s *ProjectService) getBackendEmbeddingClient(project *models.Project) (embedding.EmbeddingClient, error) {
 	if project.Config.EmbeddingModelInfo == nil {
 		if err := s.ensureEmbeddingModelSnapshot(&project.Config); err != nil {
 			return nil, err
@@ -838,6 +1034,29 @@ func (s *ProjectService) getEmbeddingClient(project *models.Project) (embedding.
 		s.embeddingClients[meta.ID] = newClient
 		s.clientsMu.Unlock()
 		return newClient, nil
+	case "openai":
+		// Endpoint settings and the API key live in the config DB; the project snapshot
+		// only identifies the model.
+		if stored, err := s.configStore.GetEmbeddingModel(meta.ID); err == nil {
+			meta = stored
+		}
+		client, err := embedding.NewOpenAIEmbeddingClient(meta)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize embedding endpoint for %s: %w", meta.ID, err)
+		}
+		return client, nil
+	case "ollama":
+		if stored, err := s.configStore.GetEmbeddingModel(meta.ID); err == nil {
+			meta = stored
+		}
+		if err := s.ensureOllamaDimension(meta); err != nil {
+			return nil, fmt.Errorf("failed to reach Ollama for %s: %w", meta.ID, err)
+		}
+		client, err := embedding.NewOllamaEmbeddingClient(meta)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize Ollama client for %s: %w", meta.ID, err)
+		}
+		return client, nil
 	default:
 		return nil, fmt.Errorf("embedding backend %s is not supported", meta.Backend)
 	}
@@ -847,6 +1066,14 @@ func refreshModelLocalStatus(meta *models.EmbeddingModelInfo) {
 	if meta == nil {
 		return
 	}
+	if meta.IsRemote() {
+		// Nothing to download: the server hosts the model. "missing" marks Ollama models that
+		// were removed from the server (see syncOllamaModels).
+		if meta.DownloadStatus != "missing" {
+			meta.DownloadStatus = "ready"
+		}
+		return
+	}
 	if strings.EqualFold(meta.Backend, "fastembed") {
 		dir, err := embedding.ResolveFastEmbedDir(meta)
 		if err != nil {
@@ -895,7 +1122,9 @@ func (s *ProjectService) makeDownloadProgressEmitter() embedding.DownloadProgres
 }
 
 // ListEmbeddingModels returns the catalog entries stored in the config DB.
+// Locally pulled Ollama embedding models are added on the fly when the server is running.
 func (s *ProjectService) ListEmbeddingModels() ([]*models.EmbeddingModelInfo, error) {
+	s.syncOllamaModels()
 	entries, err := s.configStore.ListEmbeddingModels()
 	if err != nil {
 		return nil, err
@@ -923,9 +1152,22 @@ func (s *ProjectService) SaveEmbeddingModel(model models.EmbeddingModelInfo) (*m
 	if sanitized.ID == "" {
 		return nil, fmt.Errorf("embedding model id cannot be empty")
 	}
-	if sanitized.Dimension <= 0 {
+	sanitized.Backend = strings.ToLower(strings.TrimSpace(sanitized.Backend))
+	// Ollama dimensions are probed on first use.
+	if sanitized.Dimension <= 0 && sanitized.Backend != "ollama" {
 		return nil, fmt.Errorf("embedding model dimension must be greater than zero")
 	}
+	if sanitized.Backend == "openai" {
+		if _, err := embedding.NewOpenAIEmbeddingClient(sanitized); err != nil {
+			return nil, err
+		}
+	}
+	if sanitized.IsRemote() {
+		if sanitized.SourceType == "" || sanitized.SourceType == "custom" {
+			sanitized.SourceType = sanitized.Backend
+		}
+		sanitized.DownloadStatus = "ready"
+	}
 	if sanitized.SourceType == "" {
 		sanitized.SourceType = "custom"
 	}
@@ -942,6 +1184,8 @@ func (s *ProjectService) SaveEmbeddingModel(model models.EmbeddingModelInfo) (*m
 	if err := s.configStore.UpsertEmbeddingModel(sanitized.Clone()); err != nil {
 		return nil, err
 	}
+	// Additional-model clients were built from the previous endpoint settings.
+	s.dropAdditionalClients(func(_, modelID string) bool { return strings.EqualFold(modelID, sanitized.ID) })
 
 	return sanitized, nil
 }
@@ -952,6 +1196,10 @@ func (s *ProjectService) DownloadEmbeddingModel(modelID string) (*models.Embeddi
 	if err != nil {
 		return nil, err
 	}
+	if meta.IsRemote() {
+		refreshModelLocalStatus(meta)
+		return meta, nil
+	}
 
 	metaClone := meta.Clone()
 	metaClone.DownloadStatus = "downloading"
@@ -972,20 +1220,27 @@ func (s *ProjectService) DownloadEmbeddingModel(modelID string) (*models.Embeddi
 		return nil, err
 	}
 
-	s.clientsMu.Lock()
-	if client, ok := s.embeddingClients[modelID]; ok {
-		client.Close()
-		delete(s.embeddingClients, modelID)
-	}
-	s.clientsMu.Unlock()
+	s.closeCachedEmbeddingClient(modelID)
 
 	return cloned, nil
 }
 
 // Search executes a semantic search over indexed chunks for a project.
 func (s *ProjectService) Search(projectID string, query string, k int) (*models.SearchResponse, error) {
+	return s.SearchWithOptions(models.SearchRequest{ProjectID: projectID, Query: query, K: k})
+}
+
+// SearchWithOptions executes a semantic search with optional diversification. When the project
+// enables reranking, the top-N bi-encoder candidates are rescored by a cross-encoder first; MMR,
+// contiguous merging and the per-file cap then run over the (re)ranked candidate pool.
+func (s *ProjectService) SearchWithOptions(req models.SearchRequest) (*models.SearchResponse, error) {
 	start := time.Now()
-	trimmed := strings.TrimSpace(query)
+	projectID := req.ProjectID
+	k := req.K
+	if k <= 0 {
+		k = 10
+	}
+	trimmed := strings.TrimSpace(req.Query)
 	if trimmed == "" {
 		return nil, fmt.Errorf("query cannot be empty")
 	}
@@ -995,29 +1250,69 @@ func (s *ProjectService) Search(projectID string, query string, k int) (*models.
 		return nil, err
 	}
 
-	client, err := s.getEmbeddingClient(project)
+	queries, err := s.embedSearchQueries(project, trimmed, req)
 	if err != nil {
 		return nil, err
 	}
+	return s.rankSearch(project, req, trimmed, k, queries, project.Config.Rerank, start)
+}
 
-	vecs, err := client.GenerateEmbeddings([]string{trimmed})
-	if err != nil || len(vecs) == 0 {
-		if err != nil {
-			return nil, fmt.Errorf("failed to embed query: %w", err)
-		}
-		return nil, fmt.Errorf("embedding client returned no vector")
+// rankSearch runs the retrieval stages of SearchWithOptions for already embedded queries, with
+// rerank standing in for the project's reranking settings.
+func (s *ProjectService) rankSearch(project *models.Project, req models.SearchRequest, trimmed string, k int, queries []modelQuery, rerank *models.RerankConfig, start time.Time) (*models.SearchResponse, error) {
+	projectID := project.ID
+	vectorStore, err := s.GetVectorStore(projectID)
+	if err != nil {
+		return nil, err
 	}
 
-	vectorStore, err := s.GetVectorStore(projectID)
+	indexVersion, err := vectorStore.IndexVersion()
+	if err != nil {
+		return nil, err
+	}
+	fingerprint := searchFingerprint(queries[0].vector, req, rerank)
+	offset, err := resolveSearchOffset(req.Cursor, fingerprint, indexVersion)
 	if err != nil {
 		return nil, err
 	}
 
-	results, err := vectorStore.SearchSimilarChunks(vecs[0], k)
+	// Every page recomputes the ranking up to offset+k; the candidate pools below do not depend
+	// on the page so earlier pages keep their order.
+	window := offset + k
+	pool := max(rerankCandidateCount(rerank, window), window)
+	if req.Diversified() {
+		pool = diversityCandidatePool
+	}
+	minScore := math.Inf(-1)
+	if req.MinScore > 0 {
+		minScore = req.MinScore
+	}
+	results, total, err := searchModelQueries(vectorStore, queries, pool, minScore)
 	if err != nil {
 		return nil, err
 	}
 
+	results = applyMMR(results, req)
+	var rerankStats *models.RerankStats
+	if rerank != nil && rerank.Enabled {
+		results, rerankStats = s.applyRerank(rerank, trimmed, results)
+	}
+	if req.Diversified() {
+		results = diversifyChunks(results, req, window)
+	} else {
+		results = truncateChunks(results, window)
+	}
+
+	nextCursor := ""
+	if len(results) == window && window < total && (!req.Diversified() || window < pool) {
+		nextCursor = encodeSearchCursor(searchCursor{Query: fingerprint, Offset: window, Index: indexVersion})
+	}
+	if offset < len(results) {
+		results = results[offset:]
+	} else {
+		results = []*models.Chunk{}
+	}
+
 	for _, c := range results {
 		c.ProjectID = projectID
 		// Drop embeddings to avoid large payloads but keep a non-nil slice so MCP schema validation
@@ -1027,15 +1322,25 @@ func (s *ProjectService) Search(projectID string, query string, k int) (*models.
 
 	resp := &models.SearchResponse{
 		Chunks:       results,
-		TotalResults: len(results),
+		TotalResults: total,
 		QueryTimeMs:  time.Since(start).Milliseconds(),
+		Offset:       offset,
+		NextCursor:   nextCursor,
+		Rerank:       rerankStats,
+	}
+	for _, q := range queries {
+		resp.EmbeddingModels = append(resp.EmbeddingModels, q.model)
 	}
 	return resp, nil
 }
 
 // GetEmbeddingCapabilities reports which embedding backends are currently available.
 func (s *ProjectService) GetEmbeddingCapabilities() (*models.EmbeddingCapabilities, error) {
-	return &models.EmbeddingCapabilities{OnnxRuntimeAvailable: s.enableONNXRuntime}, nil
+	return &models.EmbeddingCapabilities{
+		OnnxRuntimeAvailable: s.enableONNXRuntime,
+		OllamaAvailable:      ollamaAvailable(),
+		OllamaBaseURL:        embedding.DefaultOllamaBaseURL(),
+	}, nil
 }
 
 // GetONNXRuntimeSettings returns the persisted runtime path plus current status.
@@ -1138,6 +1443,9 @@ func mergeConfig(base, override models.ProjectConfig) models.ProjectConfig {
 	if override.MaxResponseBytes != 0 {
 		result.MaxResponseBytes = override.MaxResponseBytes
 	}
+	if override.Rerank != nil {
+		result.Rerank = override.Rerank
+	}
 	return result
 }
 
@@ -1172,6 +1480,63 @@ func isPathWithinRoot(root, target string) bool {
 	return strings.HasPrefix(target, root)
 }
 
+func isHiddenName(name string) bool {
+	return strings.HasPrefix(name, ".") && len(name) > 1
+}
+
+// pathExcluded reports whether a file or directory is skipped by the hidden-file and exclude
+// pattern rules; relativePath is relative to the project root.
+func pathExcluded(config models.ProjectConfig, path, relativePath, name string) bool {
+	if config.AutoExcludeHidden && isHiddenName(name) {
+		return true
+	}
+	for _, pattern := range config.ExcludePatterns {
+		if matched, _ := filepath.Match(pattern, relativePath); matched {
+			return true
+		}
+		if matched, _ := filepath.Match(pattern, path); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// fileIncludedByConfig reports whether the file scan would pick up absPath: it must sit under
+// an include path, no directory on the way may be excluded, and its extension must be allowed.
+func fileIncludedByConfig(config models.ProjectConfig, absPath string) bool {
+	absPath = filepath.Clean(absPath)
+	for _, includePath := range resolveIncludePaths(config.RootPath, config.IncludePaths) {
+		includePath = filepath.Clean(includePath)
+		if absPath == includePath || !isPathWithinRoot(includePath, absPath) {
+			continue
+		}
+		rel, err := filepath.Rel(includePath, absPath)
+		if err != nil {
+			continue
+		}
+		excluded := false
+		current := includePath
+		for _, part := range strings.Split(rel, string(filepath.Separator)) {
+			current = filepath.Join(current, part)
+			relativePath := filepath.ToSlash(current)
+			if rootRelative, err := filepath.Rel(config.RootPath, current); err == nil {
+				relativePath = filepath.ToSlash(rootRelative)
+			}
+			if pathExcluded(config, current, relativePath, part) {
+				excluded = true
+				break
+			}
+		}
+		if excluded {
+			continue
+		}
+		if len(config.FileExtensions) == 0 || slices.Contains(config.FileExtensions, filepath.Ext(absPath)) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetFilePreviews returns files that match the provided configuration.
 func (s *ProjectService) GetFilePreviews(projectID string, config models.ProjectConfig) ([]*models.FilePreview, error) {
 	project, err := s.GetProject(projectID)
@@ -1215,29 +1580,14 @@ func (s *ProjectService) GetFilePreviews(projectID string, config models.Project
 				}
 			}
 
-			isHidden := strings.HasPrefix(d.Name(), ".") && len(d.Name()) > 1
-			if finalConfig.AutoExcludeHidden && isHidden {
+			isHidden := isHiddenName(d.Name())
+			if pathExcluded(finalConfig, path, relativePath, d.Name()) {
 				if d.IsDir() {
 					return filepath.SkipDir
 				}
 				return nil
 			}
 
-			for _, pattern := range finalConfig.ExcludePatterns {
-				if matched, _ := filepath.Match(pattern, relativePath); matched {
-					if d.IsDir() {
-						return filepath.SkipDir
-					}
-					return nil
-				}
-				if matched, _ := filepath.Match(pattern, path); matched {
-					if d.IsDir() {
-						return filepath.SkipDir
-					}
-					return nil
-				}
-			}
-
 			if d.IsDir() {
 				return nil
 			}
@@ -1457,6 +1807,28 @@ func (s *ProjectService) GetChunkByID(projectID, chunkID string) (*models.Chunk,
 	return chunk, nil
 }
 
+// FindSymbolChunks returns indexed chunks declaring any of the given symbol names,
+// optionally limited to the given symbol kinds.
+func (s *ProjectService) FindSymbolChunks(projectID string, names, kinds []string) ([]*models.Chunk, error) {
+	project, err := s.GetProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	vectorStore, err := s.GetVectorStore(project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open vector store: %w", err)
+	}
+	chunks, err := vectorStore.FindSymbolChunks(names, kinds, 50)
+	if err != nil {
+		return nil, err
+	}
+	for _, chunk := range chunks {
+		chunk.ProjectID = project.ID
+		chunk.Embedding = []float32{}
+	}
+	return chunks, nil
+}
+
 // GetOutlineTimestamps retrieves all outline update timestamps for a project.
 // Returns a map of relative file paths to their last update timestamps (Unix time).
 func (s *ProjectService) GetOutlineTimestamps(projectID string) (map[string]int64, error) {
@@ -1494,6 +1866,29 @@ func (s *ProjectService) GetOutlineTimestamps(projectID string) (map[string]int6
 	return relativeTimestamps, nil
 }
 
+// ListIndexedFiles returns the sorted relative paths of all files tracked in the project index.
+func (s *ProjectService) ListIndexedFiles(projectID string) ([]string, error) {
+	project, err := s.GetProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	vectorStore, err := s.GetVectorStore(project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := vectorStore.ListAllFilePaths()
+	if err != nil {
+		return nil, err
+	}
+	if paths == nil {
+		paths = []string{}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
 // ReadFileContent reads the content of a file within a project.
 // The relativePath is relative to the project root.
 func (s *ProjectService) ReadFileContent(projectID, relativePath string) (string, error) {
@@ -1616,6 +2011,16 @@ func (s *ProjectService) GetProjectStats(projectID string) (*models.ProjectStats
 		}
 	}
 
+	// Additional models without any vectors yet still show up, with zero coverage.
+	for _, modelID := range project.Config.AdditionalEmbeddingModels {
+		listed := false
+		for _, usage := range stats.EmbeddingModels {
+			listed = listed || (!usage.Primary && strings.EqualFold(usage.ModelID, modelID))
+		}
+		if !listed {
+			stats.EmbeddingModels = append(stats.EmbeddingModels, models.ProjectEmbeddingModelUsage{ModelID: modelID})
+		}
+	}
 	for idx := range stats.EmbeddingModels {
 		stats.EmbeddingModels[idx].ModelInfo = resolveModelInfo(stats.EmbeddingModels[idx].ModelID)
 	}
@@ -1669,6 +2074,8 @@ func (s *ProjectService) GetAllProjectsStats() (*models.ProjectStats, error) {
 		cumulativeStats.TotalChunks += stats.TotalChunks
 		cumulativeStats.TotalSymbols += stats.TotalSymbols
 		cumulativeStats.DatabaseSize += stats.DatabaseSize
+		cumulativeStats.EmbeddingBytes += stats.EmbeddingBytes
+		cumulativeStats.EmbeddingBytesSaved += stats.EmbeddingBytesSaved
 
 		// Track the most recent indexing time across all projects
 		if stats.LastIndexedAt != nil {
@@ -1717,6 +2124,7 @@ func (s *ProjectService) Close() error {
 			firstErr = err
 		}
 	}
+	s.dropAdditionalClients(func(string, string) bool { return true })
 	s.clientsMu.Lock()
 	for id, client := range s.embeddingClients {
 		if err := client.Close(); err != nil && firstErr == nil {
@@ -1724,6 +2132,12 @@ func (s *ProjectService) Close() error {
 		}
 		delete(s.embeddingClients, id)
 	}
+	for id, reranker := range s.rerankers {
+		if err := reranker.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(s.rerankers, id)
+	}
 	s.clientsMu.Unlock()
 	return firstErr
 }
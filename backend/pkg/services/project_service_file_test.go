@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"CodeTextor/backend/pkg/models"
 )
 
 func TestReadFileContent(t *testing.T) {
@@ -120,3 +122,57 @@ func TestReadFileContent_NonExistentFile(t *testing.T) {
 		t.Error("Expected error when reading non-existent file, got nil")
 	}
 }
+
+func TestReindexFilesSkipsExcludedFiles(t *testing.T) {
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+
+	if _, err := service.SaveEmbeddingModel(models.EmbeddingModelInfo{ID: "local/test", Backend: "openai", Dimension: 2, BaseURL: newMigrationTestServer(t, 2, nil).URL}); err != nil {
+		t.Fatalf("save model: %v", err)
+	}
+	project := createProject(t, service, "Filtered Project")
+	config := project.Config
+	config.EmbeddingModel = "local/test"
+	config.EmbeddingModelInfo = nil
+	config.AutoExcludeHidden = true
+	config.ExcludePatterns = []string{"node_modules"}
+	config.FileExtensions = []string{".go"}
+	project, err := service.UpdateProjectConfig(project.ID, config)
+	if err != nil {
+		t.Fatalf("update config: %v", err)
+	}
+
+	files := map[string]string{
+		"main.go":                 "package main\n\nfunc main() {}\n",
+		".env":                    "SECRET=1\n",
+		"node_modules/dep/dep.go": "package dep\n",
+		"notes.txt":               "notes\n",
+		".private/keys/keys.go":   "package keys\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(project.Config.RootPath, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := service.ReindexFiles(project.ID, []string{"main.go", ".env", "node_modules/dep/dep.go", "notes.txt", ".private/keys/keys.go"})
+	if err != nil {
+		t.Fatalf("reindex: %v", err)
+	}
+	if results[0].Status != models.FileReindexIndexed {
+		t.Fatalf("expected main.go to be indexed, got %+v", results[0])
+	}
+	for _, result := range results[1:] {
+		if result.Status != models.FileReindexExcluded {
+			t.Fatalf("expected %s to be excluded, got %+v", result.Path, result)
+		}
+	}
+	vectorStore, _ := service.GetVectorStore(project.ID)
+	if chunks, _ := vectorStore.GetFileChunks(".env"); len(chunks) != 0 {
+		t.Fatalf("excluded file was indexed: %+v", chunks)
+	}
+}
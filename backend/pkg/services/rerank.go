@@ -0,0 +1,173 @@
+package services
+
+import (
+	"CodeTextor/backend/pkg/embedding"
+	"CodeTextor/backend/pkg/models"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// normalizeRerankConfig fills defaults and validates the reranker selection.
+func normalizeRerankConfig(cfg *models.RerankConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	cfg.Model = strings.TrimSpace(cfg.Model)
+	if cfg.Model == "" {
+		cfg.Model = models.DefaultRerankerModelID
+	}
+	meta := models.FindRerankerModel(cfg.Model)
+	if meta == nil {
+		return fmt.Errorf("unknown reranker model %s", cfg.Model)
+	}
+	cfg.Model = meta.ID
+	if cfg.TopN <= 0 {
+		cfg.TopN = models.DefaultRerankTopN
+	}
+	if cfg.TopN > models.MaxRerankTopN {
+		cfg.TopN = models.MaxRerankTopN
+	}
+	return nil
+}
+
+// rerankCandidateCount returns how many bi-encoder hits to fetch so the reranker sees TopN of them.
+func rerankCandidateCount(cfg *models.RerankConfig, k int) int {
+	if cfg == nil || !cfg.Enabled || cfg.TopN <= k {
+		return k
+	}
+	return cfg.TopN
+}
+
+// ListRerankerModels returns the cross-encoder catalog with local download status.
+func (s *ProjectService) ListRerankerModels() ([]*models.EmbeddingModelInfo, error) {
+	entries := models.DefaultRerankerModels()
+	for _, entry := range entries {
+		refreshModelLocalStatus(entry)
+	}
+	return entries, nil
+}
+
+// DownloadRerankerModel fetches a reranker's ONNX file and tokenizer into the models directory.
+func (s *ProjectService) DownloadRerankerModel(modelID string) (*models.EmbeddingModelInfo, error) {
+	meta := models.FindRerankerModel(modelID)
+	if meta == nil {
+		return nil, fmt.Errorf("unknown reranker model %s", modelID)
+	}
+	updated, err := s.modelDownloader.EnsureLocal(meta, s.makeDownloadProgressEmitter())
+	if err != nil {
+		return nil, fmt.Errorf("failed to download reranker %s: %w", meta.ID, err)
+	}
+	return updated.Clone(), nil
+}
+
+// getReranker returns a cached cross-encoder. Models are never downloaded here: a search must not
+// block on a download of up to a gigabyte, so a missing model is reported as unavailable until
+// DownloadRerankerModel has fetched it.
+func (s *ProjectService) getReranker(modelID string) (embedding.Reranker, error) {
+	if !s.enableONNXRuntime {
+		return nil, fmt.Errorf("reranking requires ONNX Runtime: set the shared library path in Settings → Projects and restart CodeTextor")
+	}
+
+	s.clientsMu.Lock()
+	reranker, ok := s.rerankers[strings.ToLower(modelID)]
+	s.clientsMu.Unlock()
+	if ok {
+		return reranker, nil
+	}
+
+	meta := models.FindRerankerModel(modelID)
+	if meta == nil {
+		return nil, fmt.Errorf("unknown reranker model %s", modelID)
+	}
+	if refreshModelLocalStatus(meta); meta.DownloadStatus != "ready" {
+		return nil, fmt.Errorf("reranker %s is not downloaded; download it in the Indexing view", meta.ID)
+	}
+	// Already on disk, so this only verifies the files and resolves their paths.
+	meta, err := s.DownloadRerankerModel(meta.ID)
+	if err != nil {
+		return nil, err
+	}
+	encoder, err := embedding.NewONNXCrossEncoder(meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize reranker %s: %w", meta.ID, err)
+	}
+
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	if existing, ok := s.rerankers[strings.ToLower(meta.ID)]; ok {
+		encoder.Close()
+		return existing, nil
+	}
+	s.rerankers[strings.ToLower(meta.ID)] = encoder
+	return encoder, nil
+}
+
+// applyRerank rescores the first TopN candidates with the project's cross-encoder; candidates
+// beyond TopN (deeper result pages) follow in bi-encoder order. Failures are reported in the
+// returned stats and leave the bi-encoder order untouched.
+func (s *ProjectService) applyRerank(cfg *models.RerankConfig, query string, candidates []*models.Chunk) ([]*models.Chunk, *models.RerankStats) {
+	head, tail := candidates, []*models.Chunk(nil)
+	if cfg.TopN > 0 && len(candidates) > cfg.TopN {
+		head, tail = candidates[:cfg.TopN], candidates[cfg.TopN:]
+	}
+	stats := &models.RerankStats{Model: cfg.Model, Candidates: len(head)}
+	start := time.Now()
+	defer func() { stats.LatencyMs = time.Since(start).Milliseconds() }()
+
+	reranker, err := s.getReranker(cfg.Model)
+	if err == nil {
+		var reranked []*models.Chunk
+		if reranked, err = rerankChunks(reranker, query, head, 0); err == nil {
+			return append(reranked, tail...), stats
+		}
+	}
+	log.Printf("Rerank skipped for %s: %v", cfg.Model, err)
+	stats.Error = err.Error()
+	return candidates, stats
+}
+
+// rerankChunks orders candidates by cross-encoder score (ties keep bi-encoder order) and returns the top k.
+func rerankChunks(reranker embedding.Reranker, query string, candidates []*models.Chunk, k int) ([]*models.Chunk, error) {
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+	passages := make([]string, len(candidates))
+	for i, c := range candidates {
+		passages[i] = rerankPassage(c)
+	}
+	scores, err := reranker.Score(query, passages)
+	if err != nil {
+		return nil, err
+	}
+	if len(scores) != len(candidates) {
+		return nil, fmt.Errorf("reranker returned %d scores for %d candidates", len(scores), len(candidates))
+	}
+
+	reranked := make([]*models.Chunk, len(candidates))
+	copy(reranked, candidates)
+	for i, c := range reranked {
+		c.RerankScore = float64(scores[i])
+	}
+	sort.SliceStable(reranked, func(i, j int) bool {
+		return reranked[i].RerankScore > reranked[j].RerankScore
+	})
+	return truncateChunks(reranked, k), nil
+}
+
+// rerankPassage prefers the enriched chunk content (path and symbol headers help the cross-encoder).
+func rerankPassage(c *models.Chunk) string {
+	if strings.TrimSpace(c.Content) != "" {
+		return c.Content
+	}
+	return c.SourceCode
+}
+
+func truncateChunks(chunks []*models.Chunk, k int) []*models.Chunk {
+	if k > 0 && len(chunks) > k {
+		return chunks[:k]
+	}
+	return chunks
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"CodeTextor/backend/pkg/embedding"
+	"CodeTextor/backend/pkg/models"
+)
+
+// keywordReranker scores passages by whether they contain the query verbatim.
+type keywordReranker struct {
+	err error
+}
+
+func (r *keywordReranker) Score(query string, passages []string) ([]float32, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	scores := make([]float32, len(passages))
+	for i, passage := range passages {
+		if strings.Contains(passage, query) {
+			scores[i] = 0.9
+		} else {
+			scores[i] = 0.1
+		}
+	}
+	return scores, nil
+}
+
+func (r *keywordReranker) Close() error { return nil }
+
+func TestRerankChunksReordersAndTruncates(t *testing.T) {
+	candidates := []*models.Chunk{
+		{ID: "near-miss", Content: "func parseConfig()", Similarity: 0.9},
+		{ID: "other", Content: "func loadUsers()", Similarity: 0.8},
+		{ID: "answer", Content: "func parseConfigFile()", SourceCode: "ignored", Similarity: 0.7},
+	}
+
+	results, err := rerankChunks(&keywordReranker{}, "parseConfigFile", candidates, 2)
+	if err != nil {
+		t.Fatalf("rerank failed: %v", err)
+	}
+	if len(results) != 2 || results[0].ID != "answer" || results[1].ID != "near-miss" {
+		t.Fatalf("unexpected order: %s, %s", results[0].ID, results[1].ID)
+	}
+	if results[0].RerankScore != float64(float32(0.9)) || results[0].Similarity != 0.7 {
+		t.Fatalf("expected rerank score alongside the original similarity, got %+v", results[0])
+	}
+}
+
+func TestApplyRerankFallsBackWithoutRuntime(t *testing.T) {
+	s := &ProjectService{enableONNXRuntime: false}
+	cfg := &models.RerankConfig{Enabled: true}
+	if err := normalizeRerankConfig(cfg); err != nil {
+		t.Fatalf("normalize failed: %v", err)
+	}
+	candidates := []*models.Chunk{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+
+	results, stats := s.applyRerank(cfg, "query", candidates)
+	if len(results) != 3 || results[0].ID != "a" {
+		t.Fatalf("expected bi-encoder order to be kept, got %d results", len(results))
+	}
+	if stats.Error == "" || stats.Candidates != 3 || stats.Model != models.DefaultRerankerModelID {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestNormalizeRerankConfig(t *testing.T) {
+	cfg := &models.RerankConfig{Enabled: true, Model: "BAAI/BGE-Reranker-Base", TopN: 10000}
+	if err := normalizeRerankConfig(cfg); err != nil {
+		t.Fatalf("normalize failed: %v", err)
+	}
+	if cfg.Model != "baai/bge-reranker-base" || cfg.TopN != models.MaxRerankTopN {
+		t.Fatalf("unexpected normalized config: %+v", cfg)
+	}
+	if err := normalizeRerankConfig(&models.RerankConfig{Model: "nope"}); err == nil {
+		t.Fatalf("expected unknown reranker to be rejected")
+	}
+	if got := rerankCandidateCount(cfg, 5); got != models.MaxRerankTopN {
+		t.Fatalf("expected TopN candidates, got %d", got)
+	}
+	if got := rerankCandidateCount(&models.RerankConfig{TopN: 50}, 5); got != 5 {
+		t.Fatalf("disabled rerank should fetch k candidates, got %d", got)
+	}
+}
+
+func TestRerankChunksPropagatesErrors(t *testing.T) {
+	_, err := rerankChunks(&keywordReranker{err: errors.New("boom")}, "q", []*models.Chunk{{ID: "a"}}, 1)
+	if err == nil {
+		t.Fatalf("expected reranker error")
+	}
+}
+
+func TestUpdateProjectConfigKeepsRerankSettings(t *testing.T) {
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+	project := createProject(t, service, "Rerank Project")
+
+	config := project.Config
+	config.Rerank = &models.RerankConfig{Enabled: true}
+	if _, err := service.UpdateProjectConfig(project.ID, config); err != nil {
+		t.Fatalf("enable rerank: %v", err)
+	}
+
+	config.Rerank = nil
+	updated, err := service.UpdateProjectConfig(project.ID, config)
+	if err != nil {
+		t.Fatalf("update config: %v", err)
+	}
+	if updated.Config.Rerank == nil || !updated.Config.Rerank.Enabled || updated.Config.Rerank.TopN != models.DefaultRerankTopN {
+		t.Fatalf("expected rerank settings to survive a config update, got %+v", updated.Config.Rerank)
+	}
+}
+
+func TestApplyRerankDoesNotDownloadDuringSearch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	s := &ProjectService{enableONNXRuntime: true, rerankers: make(map[string]embedding.Reranker)}
+	cfg := &models.RerankConfig{Enabled: true}
+	if err := normalizeRerankConfig(cfg); err != nil {
+		t.Fatalf("normalize failed: %v", err)
+	}
+	candidates := []*models.Chunk{{ID: "a"}, {ID: "b"}}
+
+	results, stats := s.applyRerank(cfg, "query", candidates)
+	if len(results) != 2 || results[0].ID != "a" {
+		t.Fatalf("expected bi-encoder order to be kept, got %+v", results)
+	}
+	if !strings.Contains(stats.Error, "not downloaded") {
+		t.Fatalf("expected a not-downloaded error, got %+v", stats)
+	}
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"CodeTextor/backend/pkg/models"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// searchCursor is the decoded form of SearchRequest.Cursor / SearchResponse.NextCursor.
+type searchCursor struct {
+	// Query fingerprints the query vector and every option that affects ordering.
+	Query string `json:"q"`
+	// Offset is the number of results already returned.
+	Offset int `json:"o"`
+	// Index is the VectorStore.IndexVersion the first page was computed against.
+	Index string `json:"v"`
+}
+
+func encodeSearchCursor(c searchCursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeSearchCursor(value string) (searchCursor, error) {
+	var c searchCursor
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return c, fmt.Errorf("invalid search cursor")
+	}
+	if err := json.Unmarshal(raw, &c); err != nil || c.Offset < 0 || c.Query == "" {
+		return c, fmt.Errorf("invalid search cursor")
+	}
+	return c, nil
+}
+
+// searchFingerprint hashes the query vector together with the options that change result
+// order, so a cursor cannot be replayed against a different query. K is excluded: page size
+// may change between pages.
+func searchFingerprint(vec []float32, req models.SearchRequest, rerank *models.RerankConfig) string {
+	h := sha256.New()
+	buf := make([]byte, 4)
+	for _, v := range vec {
+		binary.LittleEndian.PutUint32(buf, math.Float32bits(v))
+		h.Write(buf)
+	}
+	fmt.Fprintf(h, "|min=%g|mmr=%t|lambda=%g|perFile=%d|merge=%t",
+		req.MinScore, req.MMR, req.MMRLambda, req.MaxPerFile, req.MergeContiguous)
+	fmt.Fprintf(h, "|model=%s|fuse=%t", strings.ToLower(strings.TrimSpace(req.EmbeddingModel)), req.FuseModels)
+	if rerank != nil && rerank.Enabled {
+		fmt.Fprintf(h, "|rerank=%s/%d", rerank.Model, rerank.TopN)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:12])
+}
+
+// resolveSearchOffset validates a cursor against the current query and index state.
+func resolveSearchOffset(cursor, fingerprint, indexVersion string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	c, err := decodeSearchCursor(cursor)
+	if err != nil {
+		return 0, err
+	}
+	if c.Query != fingerprint {
+		return 0, fmt.Errorf("search cursor belongs to a different query or search options")
+	}
+	if c.Index != indexVersion {
+		return 0, fmt.Errorf("the index changed since this cursor was issued; run the search again")
+	}
+	return c.Offset, nil
+}
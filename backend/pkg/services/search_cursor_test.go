@@ -0,0 +1,75 @@
+package services
+
+import (
+	"testing"
+
+	"CodeTextor/backend/pkg/models"
+)
+
+func TestResolveSearchOffset(t *testing.T) {
+	vec := []float32{0.1, 0.2, 0.3}
+	req := models.SearchRequest{Query: "save user", K: 5, MMR: true}
+	fp := searchFingerprint(vec, req, nil)
+	cursor := encodeSearchCursor(searchCursor{Query: fp, Offset: 5, Index: "3.3.100"})
+
+	if offset, err := resolveSearchOffset("", fp, "3.3.100"); err != nil || offset != 0 {
+		t.Fatalf("expected an empty cursor to start at 0, got %d (%v)", offset, err)
+	}
+	if offset, err := resolveSearchOffset(cursor, fp, "3.3.100"); err != nil || offset != 5 {
+		t.Fatalf("expected offset 5, got %d (%v)", offset, err)
+	}
+
+	req.K = 20
+	if searchFingerprint(vec, req, nil) != fp {
+		t.Fatalf("page size must not change the fingerprint")
+	}
+	req.MaxPerFile = 2
+	if _, err := resolveSearchOffset(cursor, searchFingerprint(vec, req, nil), "3.3.100"); err == nil {
+		t.Fatalf("expected a cursor from different options to be rejected")
+	}
+	if _, err := resolveSearchOffset(cursor, fp, "4.4.120"); err == nil {
+		t.Fatalf("expected a cursor to be rejected after the index changed")
+	}
+	if _, err := resolveSearchOffset("not-a-cursor!", fp, "3.3.100"); err == nil {
+		t.Fatalf("expected a malformed cursor to be rejected")
+	}
+}
+
+func TestSearchSimilarChunksAboveCountsMatches(t *testing.T) {
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+	project := createProject(t, service, "Cursor Project")
+
+	vectorStore, err := service.GetVectorStore(project.ID)
+	if err != nil {
+		t.Fatalf("open vector store: %v", err)
+	}
+	before, err := vectorStore.IndexVersion()
+	if err != nil {
+		t.Fatalf("index version: %v", err)
+	}
+	for i, vec := range [][]float32{{1, 0}, {0.9, 0.1}, {0.7, 0.7}, {0, 1}} {
+		chunk := &models.Chunk{FilePath: "a.go", LineStart: i*10 + 1, LineEnd: i*10 + 5, Embedding: vec, EmbeddingModelID: "m"}
+		if err := vectorStore.InsertChunk(chunk); err != nil {
+			t.Fatalf("insert chunk: %v", err)
+		}
+	}
+	after, err := vectorStore.IndexVersion()
+	if err != nil {
+		t.Fatalf("index version: %v", err)
+	}
+	if before == after {
+		t.Fatalf("expected inserts to change the index version")
+	}
+
+	results, total, err := vectorStore.SearchSimilarChunksAbove([]float32{1, 0}, 2, 0.5)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 2 || total != 3 {
+		t.Fatalf("expected 2 results of 3 above the threshold, got %d of %d", len(results), total)
+	}
+	if results[0].Similarity < results[1].Similarity {
+		t.Fatalf("expected results ordered by similarity")
+	}
+}
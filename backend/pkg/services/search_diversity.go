@@ -0,0 +1,194 @@
+package services
+
+import (
+	"CodeTextor/backend/pkg/models"
+	"math"
+	"strings"
+)
+
+// diversityCandidatePool is the fixed number of bi-encoder hits MMR, merging and per-file caps
+// choose from. It does not depend on k or the page, so paginated results stay stable.
+const diversityCandidatePool = 200
+
+// applyMMR reorders bi-encoder candidates by MMR when the request asks for it. It runs before
+// reranking so the whole pool is compared on the same cosine scale; the cross-encoder then
+// reorders the head of the diversified list.
+func applyMMR(candidates []*models.Chunk, req models.SearchRequest) []*models.Chunk {
+	if !req.MMR {
+		return candidates
+	}
+	lambda := req.MMRLambda
+	if lambda <= 0 || lambda > 1 {
+		lambda = models.DefaultMMRLambda
+	}
+	return mmrOrder(candidates, lambda)
+}
+
+// diversifyChunks applies contiguous-range merging and the per-file cap to an ordered
+// candidate list, returning at most k results.
+func diversifyChunks(candidates []*models.Chunk, req models.SearchRequest, k int) []*models.Chunk {
+	results := make([]*models.Chunk, 0, k)
+	perFile := make(map[string]int)
+	for _, candidate := range candidates {
+		if req.MergeContiguous {
+			if target := touchingResult(results, candidate); target != nil {
+				mergeChunkInto(target, candidate)
+				before := len(results)
+				results = absorbTouchingResults(results, target)
+				perFile[target.FilePath] -= before - len(results)
+				continue
+			}
+		}
+		if len(results) >= k {
+			break
+		}
+		if req.MaxPerFile > 0 && perFile[candidate.FilePath] >= req.MaxPerFile {
+			continue
+		}
+		perFile[candidate.FilePath]++
+		results = append(results, candidate)
+	}
+	return results
+}
+
+// mmrOrder greedily orders chunks by lambda*similarity - (1-lambda)*max similarity to already
+// picked chunks, using the stored chunk embeddings.
+func mmrOrder(chunks []*models.Chunk, lambda float64) []*models.Chunk {
+	remaining := make([]*models.Chunk, len(chunks))
+	copy(remaining, chunks)
+	redundancy := make([]float64, len(remaining))
+	ordered := make([]*models.Chunk, 0, len(chunks))
+
+	for len(remaining) > 0 {
+		best := 0
+		bestScore := math.Inf(-1)
+		for i, c := range remaining {
+			score := lambda*c.Similarity - (1-lambda)*redundancy[i]
+			if score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		picked := remaining[best]
+		ordered = append(ordered, picked)
+		remaining = append(remaining[:best], remaining[best+1:]...)
+		redundancy = append(redundancy[:best], redundancy[best+1:]...)
+		for i, c := range remaining {
+			if sim := cosineSimilarity(picked.Embedding, c.Embedding); sim > redundancy[i] {
+				redundancy[i] = sim
+			}
+		}
+	}
+	return ordered
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// rangesTouch reports whether two chunks of the same file overlap or sit on adjacent lines.
+func rangesTouch(a, b *models.Chunk) bool {
+	return a.FilePath == b.FilePath && b.LineStart <= a.LineEnd+1 && a.LineStart <= b.LineEnd+1
+}
+
+func touchingResult(results []*models.Chunk, candidate *models.Chunk) *models.Chunk {
+	for _, r := range results {
+		if rangesTouch(r, candidate) {
+			return r
+		}
+	}
+	return nil
+}
+
+// absorbTouchingResults folds any other result that now touches the grown target into it.
+func absorbTouchingResults(results []*models.Chunk, target *models.Chunk) []*models.Chunk {
+	kept := results[:0]
+	for _, r := range results {
+		if r != target && rangesTouch(target, r) {
+			mergeChunkInto(target, r)
+			continue
+		}
+		kept = append(kept, r)
+	}
+	return kept
+}
+
+// mergeChunkInto widens dst to cover src. dst keeps its id and symbol metadata (it ranked
+// higher); the source text is stitched line by line so overlapping lines are not repeated.
+func mergeChunkInto(dst, src *models.Chunk) {
+	if len(dst.MergedChunkIDs) == 0 {
+		dst.MergedChunkIDs = []string{dst.ID}
+	}
+	if len(src.MergedChunkIDs) > 0 {
+		dst.MergedChunkIDs = append(dst.MergedChunkIDs, src.MergedChunkIDs...)
+	} else {
+		dst.MergedChunkIDs = append(dst.MergedChunkIDs, src.ID)
+	}
+
+	dst.SourceCode = stitchLines(dst.LineStart, chunkSource(dst), src.LineStart, chunkSource(src))
+	dst.Content = dst.SourceCode
+	if src.LineStart < dst.LineStart {
+		dst.LineStart = src.LineStart
+		dst.CharStart = src.CharStart
+	}
+	if src.LineEnd > dst.LineEnd {
+		dst.LineEnd = src.LineEnd
+		dst.CharEnd = src.CharEnd
+	}
+	if src.Similarity > dst.Similarity {
+		dst.Similarity = src.Similarity
+	}
+	if src.RerankScore > dst.RerankScore {
+		dst.RerankScore = src.RerankScore
+	}
+	dst.TokenCount += src.TokenCount
+}
+
+func chunkSource(c *models.Chunk) string {
+	if c.SourceCode != "" {
+		return c.SourceCode
+	}
+	return c.Content
+}
+
+// stitchLines combines two line ranges of the same file; lines present in both come from a.
+func stitchLines(aStart int, aSource string, bStart int, bSource string) string {
+	lines := make(map[int]string)
+	first, last := aStart, aStart
+	add := func(start int, source string, overwrite bool) {
+		for i, line := range strings.Split(source, "\n") {
+			n := start + i
+			if _, ok := lines[n]; ok && !overwrite {
+				continue
+			}
+			lines[n] = line
+			if n < first {
+				first = n
+			}
+			if n > last {
+				last = n
+			}
+		}
+	}
+	add(aStart, aSource, true)
+	add(bStart, bSource, false)
+
+	out := make([]string, 0, last-first+1)
+	for n := first; n <= last; n++ {
+		if line, ok := lines[n]; ok {
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"CodeTextor/backend/pkg/embedding"
+	"CodeTextor/backend/pkg/models"
+)
+
+func TestDiversifyChunksMergesContiguousHits(t *testing.T) {
+	candidates := []*models.Chunk{
+		{ID: "a2", FilePath: "a.go", LineStart: 11, LineEnd: 20, SourceCode: "l11\nl12", Similarity: 0.9},
+		{ID: "a1", FilePath: "a.go", LineStart: 1, LineEnd: 10, SourceCode: "l1\nl2", Similarity: 0.8},
+		{ID: "b1", FilePath: "b.go", LineStart: 5, LineEnd: 9, Similarity: 0.7},
+		{ID: "a3", FilePath: "a.go", LineStart: 40, LineEnd: 50, Similarity: 0.6},
+	}
+
+	results := diversifyChunks(candidates, models.SearchRequest{MergeContiguous: true}, 2)
+	if len(results) != 2 || results[0].ID != "a2" || results[1].ID != "b1" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	merged := results[0]
+	if merged.LineStart != 1 || merged.LineEnd != 20 || merged.Similarity != 0.9 {
+		t.Fatalf("expected a1 and a2 merged into lines 1-20, got %d-%d", merged.LineStart, merged.LineEnd)
+	}
+	if len(merged.MergedChunkIDs) != 2 || merged.MergedChunkIDs[1] != "a1" {
+		t.Fatalf("unexpected merged ids: %v", merged.MergedChunkIDs)
+	}
+}
+
+func TestDiversifyChunksCapsPerFile(t *testing.T) {
+	candidates := []*models.Chunk{
+		{ID: "a1", FilePath: "a.go", LineStart: 1, LineEnd: 5, Similarity: 0.9},
+		{ID: "a2", FilePath: "a.go", LineStart: 20, LineEnd: 25, Similarity: 0.85},
+		{ID: "a3", FilePath: "a.go", LineStart: 40, LineEnd: 45, Similarity: 0.8},
+		{ID: "b1", FilePath: "b.go", LineStart: 1, LineEnd: 5, Similarity: 0.5},
+	}
+
+	results := diversifyChunks(candidates, models.SearchRequest{MaxPerFile: 2}, 3)
+	ids := []string{results[0].ID, results[1].ID, results[2].ID}
+	if len(results) != 3 || ids[0] != "a1" || ids[1] != "a2" || ids[2] != "b1" {
+		t.Fatalf("unexpected results: %v", ids)
+	}
+}
+
+func TestMMROrderPrefersDiverseChunks(t *testing.T) {
+	candidates := []*models.Chunk{
+		{ID: "x", Similarity: 0.9, Embedding: []float32{1, 0}},
+		{ID: "x-dup", Similarity: 0.89, Embedding: []float32{1, 0.01}},
+		{ID: "y", Similarity: 0.7, Embedding: []float32{0, 1}},
+	}
+
+	ordered := mmrOrder(candidates, 0.5)
+	if ordered[0].ID != "x" || ordered[1].ID != "y" || ordered[2].ID != "x-dup" {
+		t.Fatalf("unexpected MMR order: %s, %s, %s", ordered[0].ID, ordered[1].ID, ordered[2].ID)
+	}
+}
+
+func TestMMRRunsBeforeRerankOnTheWholePool(t *testing.T) {
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+	project := setupEvalProject(t, service)
+
+	rerank := &models.RerankConfig{Enabled: true, TopN: 2}
+	if err := normalizeRerankConfig(rerank); err != nil {
+		t.Fatalf("normalize failed: %v", err)
+	}
+	service.enableONNXRuntime = true
+	service.rerankers = map[string]embedding.Reranker{strings.ToLower(rerank.Model): &keywordReranker{}}
+
+	// The reranker scores every passage 0.1, well below the cosine similarity of the chunks left
+	// outside TopN; those must not overtake the reranked head.
+	query := "alpha beta gamma"
+	queries := []modelQuery{{model: "local/keywords", vector: keywordEmbedding(query)}}
+	req := models.SearchRequest{MMR: true}
+	resp, err := service.rankSearch(project, req, query, 3, queries, rerank, time.Now())
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if len(resp.Chunks) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(resp.Chunks))
+	}
+	for i, chunk := range resp.Chunks {
+		if reranked := chunk.RerankScore > 0; reranked != (i < rerank.TopN) {
+			t.Fatalf("expected only the first %d results to be reranked, result %d (%s) has score %v", rerank.TopN, i, chunk.SymbolName, chunk.RerankScore)
+		}
+	}
+}
+
+func TestStitchLinesSkipsOverlap(t *testing.T) {
+	got := stitchLines(3, "c\nd\ne", 1, "a\nb\nc\nd")
+	if got != "a\nb\nc\nd\ne" {
+		t.Fatalf("unexpected stitched source: %q", got)
+	}
+}
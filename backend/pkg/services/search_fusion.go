@@ -0,0 +1,129 @@
+package services
+
+import (
+	"CodeTextor/backend/internal/store"
+	"CodeTextor/backend/pkg/models"
+	"fmt"
+	"log"
+	"sort"
+)
+
+// rrfK dampens the weight of top ranks in reciprocal rank fusion; 60 is the usual choice.
+const rrfK = 60
+
+// modelQuery is a query embedded with one of the project's models.
+type modelQuery struct {
+	model string
+	// additional is set when the model's vectors live in chunk_embeddings.
+	additional bool
+	vector     []float32
+}
+
+// embedSearchQueries embeds the query with the models a search uses: the requested model, or
+// the primary model followed by every additional model when fusing. Additional models that
+// fail while fusing are skipped.
+func (s *ProjectService) embedSearchQueries(project *models.Project, query string, req models.SearchRequest) ([]modelQuery, error) {
+	requested, err := resolveProjectModel(project.Config, req.EmbeddingModel)
+	if err != nil && !req.FuseModels {
+		return nil, err
+	}
+
+	var queries []modelQuery
+	if req.FuseModels || requested == "" {
+		client, err := s.getEmbeddingClient(project)
+		if err != nil {
+			return nil, err
+		}
+		vec, err := embedQuery(client.GenerateQueryEmbeddings, query)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, modelQuery{model: project.Config.EmbeddingModel, vector: vec})
+	}
+
+	var additional []string
+	switch {
+	case req.FuseModels:
+		additional = project.Config.AdditionalEmbeddingModels
+	case requested != "":
+		additional = []string{requested}
+	}
+	for _, modelID := range additional {
+		client, err := s.additionalEmbeddingClient(project, modelID)
+		var vec []float32
+		if err == nil {
+			vec, err = embedQuery(client.GenerateQueryEmbeddings, query)
+		}
+		if err != nil {
+			if !req.FuseModels {
+				return nil, err
+			}
+			log.Printf("Leaving %s out of the fused search of %s: %v", modelID, project.ID, err)
+			continue
+		}
+		queries = append(queries, modelQuery{model: modelID, additional: true, vector: vec})
+	}
+	return queries, nil
+}
+
+func embedQuery(generate func([]string) ([][]float32, error), query string) ([]float32, error) {
+	vecs, err := generate([]string{query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	if len(vecs) == 0 {
+		return nil, fmt.Errorf("embedding client returned no vector")
+	}
+	return vecs[0], nil
+}
+
+// searchModelQueries returns up to pool candidates for the queries. A single query keeps its
+// similarity ranking; several are merged with reciprocal rank fusion and the total is the
+// largest per-model total.
+func searchModelQueries(vectorStore *store.VectorStore, queries []modelQuery, pool int, minScore float64) ([]*models.Chunk, int, error) {
+	rankings := make([][]*models.Chunk, 0, len(queries))
+	total := 0
+	for _, q := range queries {
+		var results []*models.Chunk
+		var count int
+		var err error
+		if q.additional {
+			results, count, err = vectorStore.SearchModelChunksAbove(q.model, q.vector, pool, minScore)
+		} else {
+			results, count, err = vectorStore.SearchSimilarChunksAbove(q.vector, pool, minScore)
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		rankings = append(rankings, results)
+		total = max(total, count)
+	}
+	if len(rankings) == 1 {
+		return rankings[0], total, nil
+	}
+	return truncateChunks(fuseRankings(rankings), pool), total, nil
+}
+
+// fuseRankings merges rankings with reciprocal rank fusion. A chunk found by several models is
+// represented by its entry from the earliest ranking, with the best similarity of all.
+func fuseRankings(rankings [][]*models.Chunk) []*models.Chunk {
+	scores := make(map[string]float64)
+	byID := make(map[string]*models.Chunk)
+	var fused []*models.Chunk
+	for _, ranking := range rankings {
+		for rank, chunk := range ranking {
+			scores[chunk.ID] += 1 / float64(rrfK+rank+1)
+			existing, seen := byID[chunk.ID]
+			if !seen {
+				byID[chunk.ID] = chunk
+				fused = append(fused, chunk)
+				continue
+			}
+			existing.Similarity = max(existing.Similarity, chunk.Similarity)
+		}
+	}
+	sort.SliceStable(fused, func(a, b int) bool {
+		return scores[fused[a].ID] > scores[fused[b].ID]
+	})
+	return fused
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"CodeTextor/backend/pkg/models"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// maxSimilarCandidates bounds the pool fetched before self/overlap/scope filtering.
+const maxSimilarCandidates = 200
+
+// SimilarTo finds chunks whose stored embeddings are closest to the given chunk's embedding.
+// No text is re-embedded. The seed itself and chunks overlapping its line range are excluded;
+// scope can further exclude the seed's file or package.
+func (s *ProjectService) SimilarTo(projectID, chunkID string, k int, scope models.SimilarScope) (*models.SimilarResponse, error) {
+	start := time.Now()
+	if k <= 0 {
+		k = 10
+	}
+	switch scope {
+	case "":
+		scope = models.SimilarScopeAny
+	case models.SimilarScopeAny, models.SimilarScopeOtherFiles, models.SimilarScopeOtherPackages:
+	default:
+		return nil, fmt.Errorf("unknown similarity scope %q (expected any, otherFiles or otherPackages)", scope)
+	}
+
+	seed, err := s.GetChunkByID(projectID, chunkID)
+	if err != nil {
+		return nil, err
+	}
+	vectorStore, err := s.GetVectorStore(projectID)
+	if err != nil {
+		return nil, err
+	}
+	vec, modelID, err := vectorStore.GetChunkEmbedding(seed.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(vec) == 0 {
+		return nil, fmt.Errorf("chunk %s has no stored embedding; re-index the file first", seed.ID)
+	}
+
+	pool := k*3 + 20
+	if pool > maxSimilarCandidates {
+		pool = maxSimilarCandidates
+	}
+	candidates, err := vectorStore.SearchSimilarChunks(vec, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	results := filterSimilarCandidates(seed, modelID, candidates, scope, k)
+	for _, c := range results {
+		c.ProjectID = projectID
+		c.Embedding = []float32{}
+	}
+	return &models.SimilarResponse{
+		Seed:         seed,
+		Chunks:       results,
+		TotalResults: len(results),
+		QueryTimeMs:  time.Since(start).Milliseconds(),
+	}, nil
+}
+
+// filterSimilarCandidates drops the seed, chunks overlapping it, chunks embedded by another
+// model, and chunks outside the requested scope, keeping at most k.
+func filterSimilarCandidates(seed *models.Chunk, modelID string, candidates []*models.Chunk, scope models.SimilarScope, k int) []*models.Chunk {
+	seedPackage := packageKey(seed)
+	results := make([]*models.Chunk, 0, k)
+	for _, c := range candidates {
+		if len(results) >= k {
+			break
+		}
+		if c.ID == seed.ID {
+			continue
+		}
+		if modelID != "" && c.EmbeddingModelID != "" && c.EmbeddingModelID != modelID {
+			continue
+		}
+		sameFile := c.FilePath == seed.FilePath
+		if sameFile && c.LineStart <= seed.LineEnd && seed.LineStart <= c.LineEnd {
+			continue
+		}
+		switch scope {
+		case models.SimilarScopeOtherFiles:
+			if sameFile {
+				continue
+			}
+		case models.SimilarScopeOtherPackages:
+			if packageKey(c) == seedPackage {
+				continue
+			}
+		}
+		results = append(results, c)
+	}
+	return results
+}
+
+// packageKey identifies a chunk's package: the language package name qualified by directory,
+// or the directory alone when the chunker did not record one.
+func packageKey(c *models.Chunk) string {
+	dir := path.Dir(strings.ReplaceAll(c.FilePath, "\\", "/"))
+	if c.PackageName != "" {
+		return dir + ":" + c.PackageName
+	}
+	return dir
+}
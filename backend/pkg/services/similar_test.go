@@ -0,0 +1,71 @@
+package services
+
+import (
+	"testing"
+
+	"CodeTextor/backend/pkg/models"
+)
+
+func TestSimilarToUsesStoredEmbedding(t *testing.T) {
+	service, cleanup := setupTestService(t)
+	defer cleanup()
+	project := createProject(t, service, "Similar Project")
+
+	vectorStore, err := service.GetVectorStore(project.ID)
+	if err != nil {
+		t.Fatalf("open vector store: %v", err)
+	}
+	chunks := []*models.Chunk{
+		{FilePath: "pkg/a/save.go", LineStart: 1, LineEnd: 20, Embedding: []float32{1, 0, 0}, EmbeddingModelID: "m"},
+		{FilePath: "pkg/a/save.go", LineStart: 10, LineEnd: 15, Embedding: []float32{1, 0.01, 0}, EmbeddingModelID: "m"},
+		{FilePath: "pkg/a/load.go", LineStart: 1, LineEnd: 10, Embedding: []float32{0.9, 0.1, 0}, EmbeddingModelID: "m"},
+		{FilePath: "pkg/b/save.go", LineStart: 1, LineEnd: 10, Embedding: []float32{0.8, 0.2, 0}, EmbeddingModelID: "m"},
+		{FilePath: "pkg/b/other.go", LineStart: 1, LineEnd: 10, Embedding: []float32{1, 0, 0}, EmbeddingModelID: "other-model"},
+	}
+	for _, c := range chunks {
+		if err := vectorStore.InsertChunk(c); err != nil {
+			t.Fatalf("insert chunk: %v", err)
+		}
+	}
+	seedID := chunks[0].ID
+
+	resp, err := service.SimilarTo(project.ID, seedID, 5, models.SimilarScopeAny)
+	if err != nil {
+		t.Fatalf("similar: %v", err)
+	}
+	if resp.Seed.ID != seedID || len(resp.Chunks) != 2 {
+		t.Fatalf("expected seed, overlap and foreign-model chunks excluded, got %d results", len(resp.Chunks))
+	}
+	if resp.Chunks[0].FilePath != "pkg/a/load.go" || resp.Chunks[1].FilePath != "pkg/b/save.go" {
+		t.Fatalf("unexpected order: %s, %s", resp.Chunks[0].FilePath, resp.Chunks[1].FilePath)
+	}
+
+	resp, err = service.SimilarTo(project.ID, seedID, 5, models.SimilarScopeOtherPackages)
+	if err != nil {
+		t.Fatalf("similar in other packages: %v", err)
+	}
+	if len(resp.Chunks) != 1 || resp.Chunks[0].FilePath != "pkg/b/save.go" {
+		t.Fatalf("expected only the pkg/b hit, got %+v", resp.Chunks)
+	}
+
+	if _, err := service.SimilarTo(project.ID, seedID, 5, "elsewhere"); err == nil {
+		t.Fatalf("expected an unknown scope to be rejected")
+	}
+}
+
+func TestFilterSimilarCandidatesOtherFiles(t *testing.T) {
+	seed := &models.Chunk{ID: "s", FilePath: "a.go", LineStart: 1, LineEnd: 5}
+	candidates := []*models.Chunk{
+		seed,
+		{ID: "same-file", FilePath: "a.go", LineStart: 30, LineEnd: 40},
+		{ID: "other-file", FilePath: "b.go", LineStart: 1, LineEnd: 5},
+	}
+
+	results := filterSimilarCandidates(seed, "", candidates, models.SimilarScopeOtherFiles, 5)
+	if len(results) != 1 || results[0].ID != "other-file" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if results := filterSimilarCandidates(seed, "", candidates, models.SimilarScopeAny, 5); len(results) != 2 {
+		t.Fatalf("expected non-overlapping same-file chunks in scope any, got %d", len(results))
+	}
+}
@@ -13,12 +13,24 @@ type ProjectConfigInput = Omit<models.ProjectConfig, 'convertValues'> & {
   convertValues?: () => void
 }
 
+type MCPRateLimit = { requestsPerMinute: number; burst: number }
+
 type MCPConfigInput = models.MCPServerConfig | {
   host: string
   port: number
   protocol: string
   autoStart: boolean
   maxConnections: number
+  allowAnonymous: boolean
+  tlsCertFile?: string
+  tlsKeyFile?: string
+  socketPath?: string
+  socketMode?: string
+  auditRetentionDays?: number
+  auditMaxEntries?: number
+  clientRateLimit?: MCPRateLimit
+  toolRateLimits?: Record<string, MCPRateLimit>
+  maxConcurrentEmbeddingCalls?: number
 }
 
 const toBackendConfig = (config: ProjectConfigInput): models.ProjectConfig => {
@@ -234,9 +246,46 @@ export const backend = {
   async downloadEmbeddingModel(modelId: string): Promise<models.EmbeddingModelInfo> {
     return App.DownloadEmbeddingModel(modelId)
   },
+  async importEmbeddingModel(path: string): Promise<models.EmbeddingModelInfo> {
+    return App.ImportEmbeddingModel(path)
+  },
+  async migrateEmbeddingModel(projectId: string, modelId: string): Promise<void> {
+    return App.MigrateEmbeddingModel(projectId, modelId)
+  },
+  async cancelEmbeddingMigration(projectId: string): Promise<void> {
+    return App.CancelEmbeddingMigration(projectId)
+  },
+  async listRerankerModels(): Promise<models.EmbeddingModelInfo[]> {
+    return App.ListRerankerModels()
+  },
+  async downloadRerankerModel(modelId: string): Promise<models.EmbeddingModelInfo> {
+    return App.DownloadRerankerModel(modelId)
+  },
+
+  /**
+   * Runs semantic search; pass the previous response's nextCursor to fetch the next page.
+   */
+  async search(projectId: string, query: string, k: number, cursor?: string): Promise<models.SearchResponse> {
+    return App.Search(models.SearchRequest.createFrom({ projectId, query, k, cursor }))
+  },
+  async searchWithOptions(request: models.SearchRequest): Promise<models.SearchResponse> {
+    return App.Search(request)
+  },
 
-  async search(projectId: string, query: string, k: number): Promise<models.SearchResponse> {
-    return App.Search(projectId, query, k)
+  /**
+   * Retrieval evaluation: stored (query, expected file/symbol) cases and benchmark runs.
+   */
+  async getEvaluationCases(projectId: string): Promise<models.EvalCase[]> {
+    return App.GetEvaluationCases(projectId)
+  },
+  async saveEvaluationCases(projectId: string, cases: models.EvalCase[]): Promise<models.EvalCase[]> {
+    return App.SaveEvaluationCases(projectId, cases)
+  },
+  async generateEvaluationCases(projectId: string, limit: number): Promise<models.EvalCase[]> {
+    return App.GenerateEvaluationCases(projectId, limit)
+  },
+  async runEvaluation(request: models.EvalRequest): Promise<models.EvalReport> {
+    return App.RunEvaluation(request)
   },
 
   /**
@@ -347,6 +396,46 @@ export const backend = {
   async toggleMCPTool(name: string): Promise<void> {
     return App.ToggleMCPTool(name)
   },
+
+  /**
+   * Issues an MCP API token. Empty scopes grant access to all projects or tools.
+   * @returns Promise resolving to the token metadata and its one-time secret
+   */
+  async createMCPToken(name: string, projects: string[] = [], tools: string[] = []): Promise<models.MCPAPITokenSecret> {
+    return App.CreateMCPToken(name, projects, tools)
+  },
+
+  async listMCPTokens(): Promise<models.MCPAPIToken[]> {
+    return App.ListMCPTokens()
+  },
+
+  async revokeMCPToken(id: string): Promise<void> {
+    return App.RevokeMCPToken(id)
+  },
+
+  /**
+   * Queries the MCP audit log (newest first).
+   * @param query - Optional filters (client, project, tool name, time range, paging)
+   */
+  async queryMCPAuditLog(query: Partial<models.MCPAuditQuery> = {}): Promise<models.MCPAuditEntry[]> {
+    return App.QueryMCPAuditLog(models.MCPAuditQuery.createFrom(query))
+  },
+
+  async getMCPClientStats(): Promise<models.MCPClientStats[]> {
+    return App.GetMCPClientStats()
+  },
+
+  /**
+   * Exports matching audit entries as JSON Lines.
+   * @returns Promise resolving to the number of exported entries
+   */
+  async exportMCPAuditLog(path: string, query: Partial<models.MCPAuditQuery> = {}): Promise<number> {
+    return App.ExportMCPAuditLog(path, models.MCPAuditQuery.createFrom(query))
+  },
+
+  async clearMCPAuditLog(): Promise<void> {
+    return App.ClearMCPAuditLog()
+  },
 }
 
 // Export types for use in components
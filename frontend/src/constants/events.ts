@@ -1,2 +1,3 @@
 export const FILE_INDEXED_EVENT = 'project:fileIndexed'
+export const INDEXING_STATUS_EVENT = 'project:indexingStatus'
 export const EMBEDDING_DOWNLOAD_PROGRESS_EVENT = 'embedding:download-progress'
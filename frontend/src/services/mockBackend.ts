@@ -402,6 +402,9 @@ export class MockBackendService {
       totalChunks: 342 + Math.floor(Math.random() * 85),
       totalSymbols: 1205 + Math.floor(Math.random() * 220),
       databaseSize: 2458624 + Math.floor(Math.random() * 300000), // ~2.4 MB area
+      embeddingStorage: 'float32',
+      embeddingBytes: 1400832,
+      embeddingBytesSaved: 0,
       lastIndexedAt: new Date(now - Math.floor(Math.random() * 45 * 60 * 1000)),
       isIndexing,
       indexingProgress
@@ -461,7 +464,8 @@ export class MockBackendService {
       port: 3000,
       protocol: 'http',
       autoStart: false,
-      maxConnections: 10
+      maxConnections: 10,
+      allowAnonymous: false
     };
   }
 
@@ -526,9 +530,17 @@ export class MockBackendService {
     await delay(100);
 
     return [
-      { name: 'search', description: 'Semantic chunk search', enabled: true, callCount: 142 },
-      { name: 'outline', description: 'File outline tree', enabled: true, callCount: 87 },
-      { name: 'nodeSource', description: 'Source snippet for a chunk/outline node', enabled: true, callCount: 98 }
+      { name: 'search', kind: 'tool', description: 'Semantic chunk search', enabled: true, callCount: 142 },
+      { name: 'outline', kind: 'tool', description: 'File outline tree', enabled: true, callCount: 87 },
+      { name: 'nodeSource', kind: 'tool', description: 'Source snippet for a chunk/outline node', enabled: true, callCount: 98 },
+      { name: 'similar', kind: 'tool', description: 'Chunks similar to an indexed chunk or symbol', enabled: true, callCount: 9 },
+      { name: 'contextPack', kind: 'tool', description: 'Token-budgeted context bundle for a task', enabled: true, callCount: 21 },
+      { name: 'indexStatus', kind: 'tool', description: 'Indexing progress; optionally waits for a fresh index', enabled: true, callCount: 4 },
+      { name: 'reindexFiles', kind: 'tool', description: 'Re-index edited files (opt-in)', enabled: false, callCount: 0 },
+      { name: 'reindexProject', kind: 'tool', description: 'Rebuild the project index (opt-in)', enabled: false, callCount: 0 },
+      { name: 'explainFile', kind: 'prompt', description: 'Explain a file using its outline and source', enabled: true, callCount: 12 },
+      { name: 'findFeature', kind: 'prompt', description: 'Locate where a feature is implemented', enabled: true, callCount: 9 },
+      { name: 'reviewChanges', kind: 'prompt', description: 'Review recently re-indexed files under a path', enabled: true, callCount: 4 }
     ];
   }
 
@@ -126,6 +126,10 @@ export interface SearchResponse {
   chunks: Chunk[]
   totalResults: number
   queryTime: number
+  offset?: number
+  nextCursor?: string
+  rerank?: models.RerankStats
+  embeddingModels?: string[]
 }
 
 // Outline request
@@ -150,12 +154,27 @@ export interface SymbolSearchRequest {
 }
 
 // MCP Server configuration/state/types
+export interface MCPRateLimit {
+  requestsPerMinute: number
+  burst: number
+}
+
 export interface MCPServerConfig {
   host: string
   port: number
-  protocol: 'http' | 'stdio'
+  protocol: 'http' | 'https' | 'unix' | 'stdio'
   autoStart: boolean
   maxConnections: number
+  allowAnonymous: boolean
+  tlsCertFile?: string
+  tlsKeyFile?: string
+  socketPath?: string
+  socketMode?: string
+  auditRetentionDays?: number
+  auditMaxEntries?: number
+  clientRateLimit?: MCPRateLimit
+  toolRateLimits?: Record<string, MCPRateLimit>
+  maxConcurrentEmbeddingCalls?: number
 }
 
 export interface MCPServerStatus {
@@ -167,8 +186,44 @@ export interface MCPServerStatus {
   lastError?: string
 }
 
+export interface MCPAPIToken {
+  id: string
+  name: string
+  prefix: string
+  projects: string[]
+  tools: string[]
+  createdAt: number
+  lastUsedAt?: number
+}
+
+export interface MCPAuditEntry {
+  id: number
+  timestamp: number
+  sessionId?: string
+  clientName?: string
+  clientVersion?: string
+  tokenName?: string
+  projectId?: string
+  method: string
+  name: string
+  arguments?: string
+  resultBytes: number
+  durationMs: number
+  error?: string
+}
+
+export interface MCPClientStats {
+  clientName: string
+  clientVersion?: string
+  callCount: number
+  errorCount: number
+  averageResponseTime: number
+  lastSeen: number
+}
+
 export interface MCPTool {
   name: string
+  kind: 'tool' | 'prompt'
   description: string
   enabled: boolean
   callCount: number
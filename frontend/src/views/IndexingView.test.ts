@@ -34,6 +34,7 @@ vi.mock('../api/backend', () => ({
     reindexProject: vi.fn().mockResolvedValue(undefined),
     saveEmbeddingModel: vi.fn().mockResolvedValue({}),
     downloadEmbeddingModel: vi.fn().mockResolvedValue({}),
+    listRerankerModels: vi.fn().mockResolvedValue([]),
     updateProjectConfig: vi.fn().mockResolvedValue({})
   }
 }));
@@ -12,7 +12,8 @@ vi.mock('../api/backend', async () => {
     ...actual,
     backend: {
       ...actual.backend,
-      search: vi.fn()
+      search: vi.fn(),
+      searchWithOptions: vi.fn()
     }
   };
 });
@@ -58,6 +59,31 @@ describe('SearchView.vue', () => {
     expect(semanticSearchSpy).toHaveBeenCalledWith('p1', 'test query', 10);
   });
 
+  it('searches across every embedding model when fused results are selected', async () => {
+    currentProjectRef.value = {
+      id: 'p1',
+      name: 'Test Project',
+      config: { embeddingModel: 'model/a', additionalEmbeddingModels: ['model/b'] },
+    };
+    backendMock.searchWithOptions.mockResolvedValue(
+      models.SearchResponse.createFrom({ chunks: [], totalResults: 0, queryTime: 0, embeddingModels: ['model/a', 'model/b'] })
+    );
+
+    const wrapper = mountComponent();
+    const options = wrapper.findAll('#searchModel option').map((option) => option.text());
+    expect(options).toEqual(['model/a', 'model/b', 'All models (fused)']);
+    await wrapper.find('#searchModel').setValue('__fuse__');
+    await wrapper.find('#query').setValue('test query');
+    await wrapper.find('button.btn-primary').trigger('click');
+    await flushPromises();
+
+    expect(backendMock.search).not.toHaveBeenCalled();
+    expect(backendMock.searchWithOptions).toHaveBeenCalledWith(
+      expect.objectContaining({ projectId: 'p1', query: 'test query', k: 10, fuseModels: true })
+    );
+    expect(wrapper.text()).toContain('fused model/a, model/b');
+  });
+
   it('displays search results', async () => {
     const chunks = [
       { id: 'c1', projectId: 'p1', symbolName: 'Chunk 1', content: 'content 1', filePath: 'file1.ts', embedding: [], lineStart: 1, lineEnd: 10, charStart: 0, charEnd: 100, createdAt: 0, updatedAt: 0, similarity: 0.9, symbolKind: 'function' },
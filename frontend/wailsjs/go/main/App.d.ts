@@ -2,18 +2,32 @@
 // This file is automatically generated. DO NOT EDIT
 import {models} from '../models';
 
+export function CancelEmbeddingMigration(arg1:string):Promise<void>;
+
+export function ClearMCPAuditLog():Promise<void>;
+
 export function ClearSelectedProject():Promise<void>;
 
+export function CreateMCPToken(arg1:string,arg2:Array<string>,arg3:Array<string>):Promise<models.MCPAPITokenSecret>;
+
 export function CreateProject(arg1:string,arg2:string,arg3:string,arg4:string):Promise<models.Project>;
 
 export function DeleteProject(arg1:string):Promise<void>;
 
 export function DownloadEmbeddingModel(arg1:string):Promise<models.EmbeddingModelInfo>;
 
+export function DownloadRerankerModel(arg1:string):Promise<models.EmbeddingModelInfo>;
+
+export function ExportMCPAuditLog(arg1:string,arg2:models.MCPAuditQuery):Promise<number>;
+
+export function GenerateEvaluationCases(arg1:string,arg2:number):Promise<Array<models.EvalCase>>;
+
 export function GetAllProjectsStats():Promise<models.ProjectStats>;
 
 export function GetEmbeddingCapabilities():Promise<models.EmbeddingCapabilities>;
 
+export function GetEvaluationCases(arg1:string):Promise<Array<models.EvalCase>>;
+
 export function GetFileChunks(arg1:string,arg2:string):Promise<Array<models.Chunk>>;
 
 export function GetFileOutline(arg1:string,arg2:string):Promise<Array<models.OutlineNode>>;
@@ -24,6 +38,8 @@ export function GetGitignorePatterns(arg1:string):Promise<Array<string>>;
 
 export function GetIndexingProgress(arg1:string):Promise<models.IndexingProgress>;
 
+export function GetMCPClientStats():Promise<Array<models.MCPClientStats>>;
+
 export function GetMCPConfig():Promise<models.MCPServerConfig>;
 
 export function GetMCPStatus():Promise<models.MCPServerStatus>;
@@ -42,21 +58,37 @@ export function GetSelectedProject():Promise<models.Project>;
 
 export function Greet(arg1:string):Promise<string>;
 
+export function ImportEmbeddingModel(arg1:string):Promise<models.EmbeddingModelInfo>;
+
 export function ListEmbeddingModels():Promise<Array<models.EmbeddingModelInfo>>;
 
+export function ListMCPTokens():Promise<Array<models.MCPAPIToken>>;
+
 export function ListProjects():Promise<Array<models.Project>>;
 
+export function ListRerankerModels():Promise<Array<models.EmbeddingModelInfo>>;
+
+export function MigrateEmbeddingModel(arg1:string,arg2:string):Promise<void>;
+
 export function ProjectExists(arg1:string):Promise<boolean>;
 
+export function QueryMCPAuditLog(arg1:models.MCPAuditQuery):Promise<Array<models.MCPAuditEntry>>;
+
 export function ReadFileContent(arg1:string,arg2:string):Promise<string>;
 
 export function ReindexProject(arg1:string):Promise<void>;
 
 export function ResetProjectIndex(arg1:string):Promise<void>;
 
+export function RevokeMCPToken(arg1:string):Promise<void>;
+
+export function RunEvaluation(arg1:models.EvalRequest):Promise<models.EvalReport>;
+
 export function SaveEmbeddingModel(arg1:models.EmbeddingModelInfo):Promise<models.EmbeddingModelInfo>;
 
-export function Search(arg1:string,arg2:string,arg3:number):Promise<models.SearchResponse>;
+export function SaveEvaluationCases(arg1:string,arg2:Array<models.EvalCase>):Promise<Array<models.EvalCase>>;
+
+export function Search(arg1:models.SearchRequest):Promise<models.SearchResponse>;
 
 export function SelectDirectory(arg1:string,arg2:string):Promise<string>;
 
@@ -66,6 +98,8 @@ export function SetProjectIndexing(arg1:string,arg2:boolean):Promise<void>;
 
 export function SetSelectedProject(arg1:string):Promise<void>;
 
+export function SimilarTo(arg1:string,arg2:string,arg3:number,arg4:string):Promise<models.SimilarResponse>;
+
 export function StartIndexing(arg1:string):Promise<void>;
 
 export function StartMCPServer():Promise<void>;
@@ -2,10 +2,22 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function CancelEmbeddingMigration(arg1) {
+  return window['go']['main']['App']['CancelEmbeddingMigration'](arg1);
+}
+
+export function ClearMCPAuditLog() {
+  return window['go']['main']['App']['ClearMCPAuditLog']();
+}
+
 export function ClearSelectedProject() {
   return window['go']['main']['App']['ClearSelectedProject']();
 }
 
+export function CreateMCPToken(arg1, arg2, arg3) {
+  return window['go']['main']['App']['CreateMCPToken'](arg1, arg2, arg3);
+}
+
 export function CreateProject(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['CreateProject'](arg1, arg2, arg3, arg4);
 }
@@ -18,6 +30,18 @@ export function DownloadEmbeddingModel(arg1) {
   return window['go']['main']['App']['DownloadEmbeddingModel'](arg1);
 }
 
+export function DownloadRerankerModel(arg1) {
+  return window['go']['main']['App']['DownloadRerankerModel'](arg1);
+}
+
+export function ExportMCPAuditLog(arg1, arg2) {
+  return window['go']['main']['App']['ExportMCPAuditLog'](arg1, arg2);
+}
+
+export function GenerateEvaluationCases(arg1, arg2) {
+  return window['go']['main']['App']['GenerateEvaluationCases'](arg1, arg2);
+}
+
 export function GetAllProjectsStats() {
   return window['go']['main']['App']['GetAllProjectsStats']();
 }
@@ -26,6 +50,10 @@ export function GetEmbeddingCapabilities() {
   return window['go']['main']['App']['GetEmbeddingCapabilities']();
 }
 
+export function GetEvaluationCases(arg1) {
+  return window['go']['main']['App']['GetEvaluationCases'](arg1);
+}
+
 export function GetFileChunks(arg1, arg2) {
   return window['go']['main']['App']['GetFileChunks'](arg1, arg2);
 }
@@ -46,6 +74,10 @@ export function GetIndexingProgress(arg1) {
   return window['go']['main']['App']['GetIndexingProgress'](arg1);
 }
 
+export function GetMCPClientStats() {
+  return window['go']['main']['App']['GetMCPClientStats']();
+}
+
 export function GetMCPConfig() {
   return window['go']['main']['App']['GetMCPConfig']();
 }
@@ -82,18 +114,38 @@ export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
 
+export function ImportEmbeddingModel(arg1) {
+  return window['go']['main']['App']['ImportEmbeddingModel'](arg1);
+}
+
 export function ListEmbeddingModels() {
   return window['go']['main']['App']['ListEmbeddingModels']();
 }
 
+export function ListMCPTokens() {
+  return window['go']['main']['App']['ListMCPTokens']();
+}
+
 export function ListProjects() {
   return window['go']['main']['App']['ListProjects']();
 }
 
+export function ListRerankerModels() {
+  return window['go']['main']['App']['ListRerankerModels']();
+}
+
+export function MigrateEmbeddingModel(arg1, arg2) {
+  return window['go']['main']['App']['MigrateEmbeddingModel'](arg1, arg2);
+}
+
 export function ProjectExists(arg1) {
   return window['go']['main']['App']['ProjectExists'](arg1);
 }
 
+export function QueryMCPAuditLog(arg1) {
+  return window['go']['main']['App']['QueryMCPAuditLog'](arg1);
+}
+
 export function ReadFileContent(arg1, arg2) {
   return window['go']['main']['App']['ReadFileContent'](arg1, arg2);
 }
@@ -106,12 +158,24 @@ export function ResetProjectIndex(arg1) {
   return window['go']['main']['App']['ResetProjectIndex'](arg1);
 }
 
+export function RevokeMCPToken(arg1) {
+  return window['go']['main']['App']['RevokeMCPToken'](arg1);
+}
+
+export function RunEvaluation(arg1) {
+  return window['go']['main']['App']['RunEvaluation'](arg1);
+}
+
 export function SaveEmbeddingModel(arg1) {
   return window['go']['main']['App']['SaveEmbeddingModel'](arg1);
 }
 
-export function Search(arg1, arg2, arg3) {
-  return window['go']['main']['App']['Search'](arg1, arg2, arg3);
+export function SaveEvaluationCases(arg1, arg2) {
+  return window['go']['main']['App']['SaveEvaluationCases'](arg1, arg2);
+}
+
+export function Search(arg1) {
+  return window['go']['main']['App']['Search'](arg1);
 }
 
 export function SelectDirectory(arg1, arg2) {
@@ -130,6 +194,10 @@ export function SetSelectedProject(arg1) {
   return window['go']['main']['App']['SetSelectedProject'](arg1);
 }
 
+export function SimilarTo(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SimilarTo'](arg1, arg2, arg3, arg4);
+}
+
 export function StartIndexing(arg1) {
   return window['go']['main']['App']['StartIndexing'](arg1);
 }
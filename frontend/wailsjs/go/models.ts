@@ -8,6 +8,7 @@ export namespace models {
 	    embedding: number[];
 	    embeddingModelId?: string;
 	    similarity?: number;
+	    rerankScore?: number;
 	    lineStart: number;
 	    lineEnd: number;
 	    charStart: number;
@@ -25,6 +26,7 @@ export namespace models {
 	    tokenCount?: number;
 	    isCollapsed?: boolean;
 	    sourceCode?: string;
+	    mergedChunkIds?: string[];
 	
 	    static createFrom(source: any = {}) {
 	        return new Chunk(source);
@@ -39,6 +41,7 @@ export namespace models {
 	        this.embedding = source["embedding"];
 	        this.embeddingModelId = source["embeddingModelId"];
 	        this.similarity = source["similarity"];
+	        this.rerankScore = source["rerankScore"];
 	        this.lineStart = source["lineStart"];
 	        this.lineEnd = source["lineEnd"];
 	        this.charStart = source["charStart"];
@@ -56,10 +59,13 @@ export namespace models {
 	        this.tokenCount = source["tokenCount"];
 	        this.isCollapsed = source["isCollapsed"];
 	        this.sourceCode = source["sourceCode"];
+	        this.mergedChunkIds = source["mergedChunkIds"];
 	    }
 	}
 	export class EmbeddingCapabilities {
 	    onnxRuntimeAvailable: boolean;
+	    ollamaAvailable: boolean;
+	    ollamaBaseUrl?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new EmbeddingCapabilities(source);
@@ -68,6 +74,8 @@ export namespace models {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.onnxRuntimeAvailable = source["onnxRuntimeAvailable"];
+	        this.ollamaAvailable = source["ollamaAvailable"];
+	        this.ollamaBaseUrl = source["ollamaBaseUrl"];
 	    }
 	}
 	export class EmbeddingModelInfo {
@@ -87,6 +95,8 @@ export namespace models {
 	    localPath?: string;
 	    tokenizerUri?: string;
 	    tokenizerLocalPath?: string;
+	    sha256?: string;
+	    tokenizerSha256?: string;
 	    license?: string;
 	    downloadStatus?: string;
 	    requiresConversion?: boolean;
@@ -96,7 +106,16 @@ export namespace models {
 	    codeFocus?: string;
 	    estimatedTokensPerSecond?: number;
 	    supportsQuantization?: boolean;
+	    supportsMatryoshka?: boolean;
 	    maxSequenceLength?: number;
+	    baseUrl?: string;
+	    remoteModel?: string;
+	    apiKey?: string;
+	    batchSize?: number;
+	    maxRetries?: number;
+	    timeoutSeconds?: number;
+	    queryPrefix?: string;
+	    documentPrefix?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new EmbeddingModelInfo(source);
@@ -120,6 +139,8 @@ export namespace models {
 	        this.localPath = source["localPath"];
 	        this.tokenizerUri = source["tokenizerUri"];
 	        this.tokenizerLocalPath = source["tokenizerLocalPath"];
+	        this.sha256 = source["sha256"];
+	        this.tokenizerSha256 = source["tokenizerSha256"];
 	        this.license = source["license"];
 	        this.downloadStatus = source["downloadStatus"];
 	        this.requiresConversion = source["requiresConversion"];
@@ -129,9 +150,199 @@ export namespace models {
 	        this.codeFocus = source["codeFocus"];
 	        this.estimatedTokensPerSecond = source["estimatedTokensPerSecond"];
 	        this.supportsQuantization = source["supportsQuantization"];
+	        this.supportsMatryoshka = source["supportsMatryoshka"];
 	        this.maxSequenceLength = source["maxSequenceLength"];
+	        this.baseUrl = source["baseUrl"];
+	        this.remoteModel = source["remoteModel"];
+	        this.apiKey = source["apiKey"];
+	        this.batchSize = source["batchSize"];
+	        this.maxRetries = source["maxRetries"];
+	        this.timeoutSeconds = source["timeoutSeconds"];
+	        this.queryPrefix = source["queryPrefix"];
+	        this.documentPrefix = source["documentPrefix"];
 	    }
 	}
+	export class EvalCase {
+	    query: string;
+	    expectedFile: string;
+	    expectedSymbol?: string;
+	    source?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new EvalCase(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.query = source["query"];
+	        this.expectedFile = source["expectedFile"];
+	        this.expectedSymbol = source["expectedSymbol"];
+	        this.source = source["source"];
+	    }
+	}
+	export class EvalCaseResult {
+	    query: string;
+	    expectedFile: string;
+	    expectedSymbol?: string;
+	    rank: number;
+	    latencyMs: number;
+	    topFile?: string;
+	    topSymbol?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new EvalCaseResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.query = source["query"];
+	        this.expectedFile = source["expectedFile"];
+	        this.expectedSymbol = source["expectedSymbol"];
+	        this.rank = source["rank"];
+	        this.latencyMs = source["latencyMs"];
+	        this.topFile = source["topFile"];
+	        this.topSymbol = source["topSymbol"];
+	    }
+	}
+	export class EvalRequest {
+	    projectId: string;
+	    models?: string[];
+	    modes?: string[];
+	    k?: number;
+	    cases?: EvalCase[];
+	    generateCases?: boolean;
+	    maxCases?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new EvalRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.projectId = source["projectId"];
+	        this.models = source["models"];
+	        this.modes = source["modes"];
+	        this.k = source["k"];
+	        this.cases = this.convertValues(source["cases"], EvalCase);
+	        this.generateCases = source["generateCases"];
+	        this.maxCases = source["maxCases"];
+	    }
+
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EvalRun {
+	    model: string;
+	    mode: string;
+	    error?: string;
+	    recallAt1: number;
+	    recallAt5: number;
+	    recallAtK: number;
+	    mrr: number;
+	    meanLatencyMs: number;
+	    p95LatencyMs: number;
+	    indexBytes: number;
+	    embeddingDim?: number;
+	    embedTimeMs?: number;
+	    cases?: EvalCaseResult[];
+	
+	    static createFrom(source: any = {}) {
+	        return new EvalRun(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = source["model"];
+	        this.mode = source["mode"];
+	        this.error = source["error"];
+	        this.recallAt1 = source["recallAt1"];
+	        this.recallAt5 = source["recallAt5"];
+	        this.recallAtK = source["recallAtK"];
+	        this.mrr = source["mrr"];
+	        this.meanLatencyMs = source["meanLatencyMs"];
+	        this.p95LatencyMs = source["p95LatencyMs"];
+	        this.indexBytes = source["indexBytes"];
+	        this.embeddingDim = source["embeddingDim"];
+	        this.embedTimeMs = source["embedTimeMs"];
+	        this.cases = this.convertValues(source["cases"], EvalCaseResult);
+	    }
+
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EvalReport {
+	    projectId: string;
+	    k: number;
+	    caseCount: number;
+	    caseSource: string;
+	    runs: EvalRun[];
+	    durationMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new EvalReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.projectId = source["projectId"];
+	        this.k = source["k"];
+	        this.caseCount = source["caseCount"];
+	        this.caseSource = source["caseSource"];
+	        this.runs = this.convertValues(source["runs"], EvalRun);
+	        this.durationMs = source["durationMs"];
+	    }
+
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class FilePreview {
 	    absolutePath: string;
 	    relativePath: string;
@@ -160,6 +371,10 @@ export namespace models {
 	    currentFile: string;
 	    status: string;
 	    error?: string;
+	    migrationModel?: string;
+	    migrationBackfill?: boolean;
+	    totalChunks?: number;
+	    processedChunks?: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new IndexingProgress(source);
@@ -172,6 +387,164 @@ export namespace models {
 	        this.currentFile = source["currentFile"];
 	        this.status = source["status"];
 	        this.error = source["error"];
+	        this.migrationModel = source["migrationModel"];
+	        this.migrationBackfill = source["migrationBackfill"];
+	        this.totalChunks = source["totalChunks"];
+	        this.processedChunks = source["processedChunks"];
+	    }
+	}
+	export class MCPAPIToken {
+	    id: string;
+	    name: string;
+	    prefix: string;
+	    projects: string[];
+	    tools: string[];
+	    createdAt: number;
+	    lastUsedAt?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPAPIToken(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.prefix = source["prefix"];
+	        this.projects = source["projects"];
+	        this.tools = source["tools"];
+	        this.createdAt = source["createdAt"];
+	        this.lastUsedAt = source["lastUsedAt"];
+	    }
+	}
+	export class MCPAPITokenSecret {
+	    token: MCPAPIToken;
+	    secret: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPAPITokenSecret(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.token = this.convertValues(source["token"], MCPAPIToken);
+	        this.secret = source["secret"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MCPAuditEntry {
+	    id: number;
+	    timestamp: number;
+	    sessionId?: string;
+	    clientName?: string;
+	    clientVersion?: string;
+	    tokenName?: string;
+	    projectId?: string;
+	    method: string;
+	    name: string;
+	    arguments?: string;
+	    resultBytes: number;
+	    durationMs: number;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPAuditEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.timestamp = source["timestamp"];
+	        this.sessionId = source["sessionId"];
+	        this.clientName = source["clientName"];
+	        this.clientVersion = source["clientVersion"];
+	        this.tokenName = source["tokenName"];
+	        this.projectId = source["projectId"];
+	        this.method = source["method"];
+	        this.name = source["name"];
+	        this.arguments = source["arguments"];
+	        this.resultBytes = source["resultBytes"];
+	        this.durationMs = source["durationMs"];
+	        this.error = source["error"];
+	    }
+	}
+	export class MCPAuditQuery {
+	    clientName?: string;
+	    projectId?: string;
+	    name?: string;
+	    errorsOnly?: boolean;
+	    since?: number;
+	    until?: number;
+	    limit?: number;
+	    offset?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPAuditQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.clientName = source["clientName"];
+	        this.projectId = source["projectId"];
+	        this.name = source["name"];
+	        this.errorsOnly = source["errorsOnly"];
+	        this.since = source["since"];
+	        this.until = source["until"];
+	        this.limit = source["limit"];
+	        this.offset = source["offset"];
+	    }
+	}
+	export class MCPClientStats {
+	    clientName: string;
+	    clientVersion?: string;
+	    callCount: number;
+	    errorCount: number;
+	    averageResponseTime: number;
+	    lastSeen: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPClientStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.clientName = source["clientName"];
+	        this.clientVersion = source["clientVersion"];
+	        this.callCount = source["callCount"];
+	        this.errorCount = source["errorCount"];
+	        this.averageResponseTime = source["averageResponseTime"];
+	        this.lastSeen = source["lastSeen"];
+	    }
+	}
+	export class MCPRateLimit {
+	    requestsPerMinute: number;
+	    burst: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPRateLimit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.requestsPerMinute = source["requestsPerMinute"];
+	        this.burst = source["burst"];
 	    }
 	}
 	export class MCPServerConfig {
@@ -180,6 +553,16 @@ export namespace models {
 	    protocol: string;
 	    autoStart: boolean;
 	    maxConnections: number;
+	    allowAnonymous: boolean;
+	    tlsCertFile?: string;
+	    tlsKeyFile?: string;
+	    socketPath?: string;
+	    socketMode?: string;
+	    auditRetentionDays: number;
+	    auditMaxEntries: number;
+	    clientRateLimit: MCPRateLimit;
+	    toolRateLimits?: Record<string, MCPRateLimit>;
+	    maxConcurrentEmbeddingCalls: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new MCPServerConfig(source);
@@ -192,7 +575,35 @@ export namespace models {
 	        this.protocol = source["protocol"];
 	        this.autoStart = source["autoStart"];
 	        this.maxConnections = source["maxConnections"];
+	        this.allowAnonymous = source["allowAnonymous"];
+	        this.tlsCertFile = source["tlsCertFile"];
+	        this.tlsKeyFile = source["tlsKeyFile"];
+	        this.socketPath = source["socketPath"];
+	        this.socketMode = source["socketMode"];
+	        this.auditRetentionDays = source["auditRetentionDays"];
+	        this.auditMaxEntries = source["auditMaxEntries"];
+	        this.clientRateLimit = this.convertValues(source["clientRateLimit"], MCPRateLimit);
+	        this.toolRateLimits = this.convertValues(source["toolRateLimits"], MCPRateLimit, true);
+	        this.maxConcurrentEmbeddingCalls = source["maxConcurrentEmbeddingCalls"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class MCPServerStatus {
 	    isRunning: boolean;
@@ -218,6 +629,7 @@ export namespace models {
 	}
 	export class MCPTool {
 	    name: string;
+	    kind: string;
 	    description: string;
 	    enabled: boolean;
 	    callCount: number;
@@ -229,6 +641,7 @@ export namespace models {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.name = source["name"];
+	        this.kind = source["kind"];
 	        this.description = source["description"];
 	        this.enabled = source["enabled"];
 	        this.callCount = source["callCount"];
@@ -313,6 +726,8 @@ export namespace models {
 	export class ProjectEmbeddingModelUsage {
 	    modelId: string;
 	    chunkCount: number;
+	    coverage: number;
+	    primary: boolean;
 	    modelInfo?: EmbeddingModelInfo;
 	
 	    static createFrom(source: any = {}) {
@@ -323,6 +738,8 @@ export namespace models {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.modelId = source["modelId"];
 	        this.chunkCount = source["chunkCount"];
+	        this.coverage = source["coverage"];
+	        this.primary = source["primary"];
 	        this.modelInfo = this.convertValues(source["modelInfo"], EmbeddingModelInfo);
 	    }
 	
@@ -354,6 +771,9 @@ export namespace models {
 	    lastIndexedAtUnix?: number;
 	    embeddingModels?: ProjectEmbeddingModelUsage[];
 	    lastEmbeddingModel?: EmbeddingModelInfo;
+	    embeddingStorage?: string;
+	    embeddingBytes?: number;
+	    embeddingBytesSaved?: number;
 	    isIndexing: boolean;
 	    indexingProgress: number;
 	
@@ -371,6 +791,9 @@ export namespace models {
 	        this.lastIndexedAtUnix = source["lastIndexedAtUnix"];
 	        this.embeddingModels = this.convertValues(source["embeddingModels"], ProjectEmbeddingModelUsage);
 	        this.lastEmbeddingModel = this.convertValues(source["lastEmbeddingModel"], EmbeddingModelInfo);
+	        this.embeddingStorage = source["embeddingStorage"];
+	        this.embeddingBytes = source["embeddingBytes"];
+	        this.embeddingBytesSaved = source["embeddingBytesSaved"];
 	        this.isIndexing = source["isIndexing"];
 	        this.indexingProgress = source["indexingProgress"];
 	    }
@@ -393,6 +816,22 @@ export namespace models {
 		    return a;
 		}
 	}
+	export class RerankConfig {
+	    enabled: boolean;
+	    model?: string;
+	    topN?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RerankConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.model = source["model"];
+	        this.topN = source["topN"];
+	    }
+	}
 	export class ProjectConfig {
 	    includePaths: string[];
 	    excludePatterns: string[];
@@ -406,6 +845,10 @@ export namespace models {
 	    embeddingBackend?: string;
 	    embeddingModelInfo?: EmbeddingModelInfo;
 	    maxResponseBytes: number;
+	    rerank?: RerankConfig;
+	    embeddingStorage?: string;
+	    outputDimension?: number;
+	    additionalEmbeddingModels?: string[];
 	
 	    static createFrom(source: any = {}) {
 	        return new ProjectConfig(source);
@@ -425,6 +868,10 @@ export namespace models {
 	        this.embeddingBackend = source["embeddingBackend"];
 	        this.embeddingModelInfo = this.convertValues(source["embeddingModelInfo"], EmbeddingModelInfo);
 	        this.maxResponseBytes = source["maxResponseBytes"];
+	        this.rerank = this.convertValues(source["rerank"], RerankConfig);
+	        this.embeddingStorage = source["embeddingStorage"];
+	        this.outputDimension = source["outputDimension"];
+	        this.additionalEmbeddingModels = source["additionalEmbeddingModels"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -492,10 +939,65 @@ export namespace models {
 	
 	
 	
+	
+	export class RerankStats {
+	    model: string;
+	    candidates: number;
+	    latencyMs: number;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RerankStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = source["model"];
+	        this.candidates = source["candidates"];
+	        this.latencyMs = source["latencyMs"];
+	        this.error = source["error"];
+	    }
+	}
+	export class SearchRequest {
+	    projectId: string;
+	    query: string;
+	    k: number;
+	    mmr?: boolean;
+	    mmrLambda?: number;
+	    maxPerFile?: number;
+	    mergeContiguous?: boolean;
+	    minScore?: number;
+	    cursor?: string;
+	    embeddingModel?: string;
+	    fuseModels?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.projectId = source["projectId"];
+	        this.query = source["query"];
+	        this.k = source["k"];
+	        this.mmr = source["mmr"];
+	        this.mmrLambda = source["mmrLambda"];
+	        this.maxPerFile = source["maxPerFile"];
+	        this.mergeContiguous = source["mergeContiguous"];
+	        this.minScore = source["minScore"];
+	        this.cursor = source["cursor"];
+	        this.embeddingModel = source["embeddingModel"];
+	        this.fuseModels = source["fuseModels"];
+	    }
+	}
 	export class SearchResponse {
 	    chunks: Chunk[];
 	    totalResults: number;
 	    queryTime: number;
+	    offset: number;
+	    nextCursor?: string;
+	    rerank?: RerankStats;
+	    embeddingModels?: string[];
 	
 	    static createFrom(source: any = {}) {
 	        return new SearchResponse(source);
@@ -506,6 +1008,46 @@ export namespace models {
 	        this.chunks = this.convertValues(source["chunks"], Chunk);
 	        this.totalResults = source["totalResults"];
 	        this.queryTime = source["queryTime"];
+	        this.offset = source["offset"];
+	        this.nextCursor = source["nextCursor"];
+	        this.rerank = this.convertValues(source["rerank"], RerankStats);
+	        this.embeddingModels = source["embeddingModels"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SimilarResponse {
+	    seed?: Chunk;
+	    chunks: Chunk[];
+	    totalResults: number;
+	    queryTime: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SimilarResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.seed = this.convertValues(source["seed"], Chunk);
+	        this.chunks = this.convertValues(source["chunks"], Chunk);
+	        this.totalResults = source["totalResults"];
+	        this.queryTime = source["queryTime"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {